@@ -5,10 +5,12 @@ import (
 	"time"
 
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
-	"github.com/ONSdigital/dp-graph/v2/mock"
-	"github.com/ONSdigital/dp-graph/v2/neo4j"
 	"github.com/ONSdigital/dp-graph/v2/neptune"
 	"github.com/kelseyhightower/envconfig"
+
+	// register the drivers provided by this module
+	_ "github.com/ONSdigital/dp-graph/v2/mock"
+	_ "github.com/ONSdigital/dp-graph/v2/neo4j"
 )
 
 // Configuration allows environment variables to be read and sent to the
@@ -16,7 +18,10 @@ import (
 type Configuration struct {
 	DriverChoice    string        `envconfig:"GRAPH_DRIVER_TYPE"`
 	DatabaseAddress string        `envconfig:"GRAPH_ADDR" json:"-"`
+	ReaderAddress   string        `envconfig:"GRAPH_READER_ADDR" json:"-"`
 	PoolSize        int           `envconfig:"GRAPH_POOL_SIZE"`
+	PoolMaxLifetime time.Duration `envconfig:"GRAPH_POOL_MAX_LIFETIME"`
+	PoolIdleTimeout time.Duration `envconfig:"GRAPH_POOL_IDLE_TIMEOUT"`
 	MaxRetries      int           `envconfig:"MAX_RETRIES"`
 	RetryTime       time.Duration `envconfig:"RETRY_TIME"`
 	QueryTimeout    int           `envconfig:"GRAPH_QUERY_TIMEOUT"`
@@ -26,22 +31,33 @@ type Configuration struct {
 }
 
 // NeptuneConfig defines the neptune-specific configuration
-type NeptuneConfig struct {
-	BatchSizeReader int  `envconfig:"NEPTUNE_BATCH_SIZE_READER"`
-	BatchSizeWriter int  `envconfig:"NEPTUNE_BATCH_SIZE_WRITER"`
-	MaxWorkers      int  `envconfig:"NEPTUNE_MAX_WORKERS"`
-	TLSSkipVerify   bool `envconfig:"NEPTUNE_TLS_SKIP_VERIFY"`
-}
+type NeptuneConfig = neptune.Settings
 
 var cfg *Configuration
 
-// Get reads config and returns the configured instantiated driver
+// Get reads config from the environment and returns the configured instantiated driver.
+// The configuration is read once and shared by all callers.
 func Get(errs chan error) (*Configuration, error) {
 	if cfg != nil {
 		return cfg, nil
 	}
 
-	cfg = &Configuration{
+	c, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Driver, err = c.NewDriver(errs); err != nil {
+		return nil, err
+	}
+
+	cfg = c
+	return cfg, nil
+}
+
+// Load returns the configuration read from the environment, without instantiating a driver
+func Load() (*Configuration, error) {
+	c := &Configuration{
 		DriverChoice: "",
 		Neptune: NeptuneConfig{
 			BatchSizeReader: 25000,
@@ -51,38 +67,38 @@ func Get(errs chan error) (*Configuration, error) {
 		},
 	}
 
-	err := envconfig.Process("", cfg)
-
-	var d driver.Driver
-
-	switch cfg.DriverChoice {
-	case "neo4j":
-		d, err = neo4j.New(cfg.DatabaseAddress, cfg.PoolSize, cfg.QueryTimeout, cfg.MaxRetries)
-		if err != nil {
-			return nil, err
-		}
-	case "neptune":
-		d, err = neptune.New(
-			cfg.DatabaseAddress,
-			cfg.PoolSize,
-			cfg.QueryTimeout,
-			cfg.MaxRetries,
-			cfg.Neptune.BatchSizeReader,
-			cfg.Neptune.BatchSizeWriter,
-			cfg.Neptune.MaxWorkers,
-			cfg.Neptune.TLSSkipVerify,
-			cfg.RetryTime,
-			errs)
-		if err != nil {
-			return nil, err
-		}
-	case "mock":
-		d = &mock.Mock{}
-	default:
+	if err := envconfig.Process("", c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewDriver returns a new driver of the configured type, sending asynchronous errors to errs.
+// The type is the name of a driver registered with driver.Register.
+func (c *Configuration) NewDriver(errs chan error) (driver.Driver, error) {
+	if c.DriverChoice == "" {
 		return nil, errors.New("driver type config not provided")
 	}
 
-	cfg.Driver = d
+	return driver.Open(c.DriverChoice, driver.Config{
+		Address:         c.DatabaseAddress,
+		ReaderAddress:   c.ReaderAddress,
+		PoolSize:        c.PoolSize,
+		PoolMaxLifetime: c.PoolMaxLifetime,
+		PoolIdleTimeout: c.PoolIdleTimeout,
+		MaxRetries:      c.MaxRetries,
+		RetryTime:       c.RetryTime,
+		QueryTimeout:    c.QueryTimeout,
+		LoadSettings:    c.loadSettings,
+	}, errs)
+}
 
-	return cfg, nil
+// loadSettings provides the settings specific to the chosen driver: the neptune configuration for the neptune driver,
+// and the settings read from the environment for other drivers
+func (c *Configuration) loadSettings(settings interface{}) error {
+	if s, ok := settings.(*NeptuneConfig); ok {
+		*s = c.Neptune
+		return nil
+	}
+	return envconfig.Process("", settings)
 }
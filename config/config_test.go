@@ -4,10 +4,32 @@ import (
 	"os"
 	"testing"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/mock"
 	"github.com/ONSdigital/dp-graph/v2/neo4j"
+	"github.com/ONSdigital/dp-graph/v2/neptune"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
+// customDriver is returned by the driver registered as "config-test", which is registered once for all runs
+// of the tests as drivers cannot be unregistered
+var (
+	customDriver = &mock.Mock{IsBackendReachable: true}
+	customConfig driver.Config
+)
+
+// customSettings are the settings specific to the "config-test" driver
+type customSettings struct {
+	BatchSize int `envconfig:"CUSTOM_BATCH_SIZE"`
+}
+
+func init() {
+	driver.Register("config-test", func(cfg driver.Config, errs chan error) (driver.Driver, error) {
+		customConfig = cfg
+		return customDriver, nil
+	})
+}
+
 func TestGetFailsByDefault(t *testing.T) {
 	Convey("When configuration not provided, fail by default", t, func() {
 		os.Clearenv()
@@ -39,3 +61,123 @@ func TestGetReturnsChosenDriver(t *testing.T) {
 		})
 	})
 }
+
+func TestLoad(t *testing.T) {
+	Convey("Given environment variables configuring neptune", t, func() {
+		os.Clearenv()
+		os.Setenv("GRAPH_DRIVER_TYPE", "neptune")
+		os.Setenv("GRAPH_ADDR", "ws://writer:8182/gremlin")
+		os.Setenv("GRAPH_READER_ADDR", "ws://reader:8182/gremlin")
+		os.Setenv("NEPTUNE_MAX_WORKERS", "10")
+
+		Convey("When Load is called", func() {
+			c, err := Load()
+
+			Convey("Then the configuration is read from the environment, with defaults for the variables not set", func() {
+				So(err, ShouldBeNil)
+				So(c.DriverChoice, ShouldEqual, "neptune")
+				So(c.DatabaseAddress, ShouldEqual, "ws://writer:8182/gremlin")
+				So(c.ReaderAddress, ShouldEqual, "ws://reader:8182/gremlin")
+				So(c.Neptune.MaxWorkers, ShouldEqual, 10)
+				So(c.Neptune.BatchSizeReader, ShouldEqual, 25000)
+				So(c.Driver, ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given an invalid environment variable", t, func() {
+		os.Clearenv()
+		os.Setenv("GRAPH_POOL_SIZE", "thirty")
+
+		Convey("When Load is called", func() {
+			c, err := Load()
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(c, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestNewDriver(t *testing.T) {
+	Convey("Given configurations choosing different drivers", t, func() {
+		neo := Configuration{DriverChoice: "neo4j", PoolSize: 5}
+		m := Configuration{DriverChoice: "mock"}
+
+		Convey("When NewDriver is called on each", func() {
+			d1, err1 := neo.NewDriver(nil)
+			d2, err2 := m.NewDriver(nil)
+
+			Convey("Then a separate driver of each type is returned", func() {
+				So(err1, ShouldBeNil)
+				So(err2, ShouldBeNil)
+				db, ok := d1.(*neo4j.Neo4j)
+				So(ok, ShouldBeTrue)
+				So(db.Stats().MaxOpenConnections, ShouldEqual, 5)
+				_, ok = d2.(*mock.Mock)
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a custom driver registered by another package", t, func() {
+		os.Clearenv()
+		os.Setenv("CUSTOM_BATCH_SIZE", "10")
+		c := Configuration{DriverChoice: "config-test", DatabaseAddress: "custom-addr"}
+
+		Convey("When NewDriver is called", func() {
+			d, err := c.NewDriver(nil)
+
+			Convey("Then the custom driver is returned, created with the configuration", func() {
+				So(err, ShouldBeNil)
+				So(d, ShouldEqual, customDriver)
+				So(customConfig.Address, ShouldEqual, "custom-addr")
+				var settings customSettings
+				So(customConfig.LoadSettings(&settings), ShouldBeNil)
+				So(settings.BatchSize, ShouldEqual, 10)
+			})
+		})
+	})
+
+	Convey("Given a configuration choosing neptune with neptune-specific settings", t, func() {
+		c := Configuration{DriverChoice: "neptune", PoolSize: 8, Neptune: NeptuneConfig{MaxWorkers: 10}}
+
+		Convey("When NewDriver is called", func() {
+			d, err := c.NewDriver(nil)
+
+			Convey("Then the neptune driver is created with the pool size and the settings", func() {
+				So(err, ShouldBeNil)
+				db, ok := d.(*neptune.NeptuneDB)
+				So(ok, ShouldBeTrue)
+				So(db.Stats().MaxOpenConnections, ShouldEqual, 8)
+			})
+		})
+	})
+
+	Convey("Given a configuration choosing a driver not registered", t, func() {
+		c := Configuration{DriverChoice: "unknown"}
+
+		Convey("When NewDriver is called", func() {
+			d, err := c.NewDriver(nil)
+
+			Convey("Then an error is returned", func() {
+				So(d, ShouldBeNil)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a configuration without a driver choice", t, func() {
+		c := Configuration{}
+
+		Convey("When NewDriver is called", func() {
+			d, err := c.NewDriver(nil)
+
+			Convey("Then an error is returned", func() {
+				So(d, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "driver type config not provided")
+			})
+		})
+	})
+}
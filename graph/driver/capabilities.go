@@ -0,0 +1,58 @@
+package driver
+
+// Capability is an optional feature of a driver, which services can check before relying on it
+type Capability string
+
+// Possible capabilities of a driver
+const (
+	// CapabilityOrderedCodes is the order of codes in code list editions, used to sort codes and returned by GetCodesOrder
+	CapabilityOrderedCodes Capability = "ordered_codes"
+	// CapabilityCodeCounts is the counting of the codes of a code list edition by CountCodes
+	CapabilityCodeCounts Capability = "code_counts"
+	// CapabilitySparseHierarchy is the import of the part of a hierarchy that has data, using the ID-based hierarchy functions
+	CapabilitySparseHierarchy Capability = "sparse_hierarchy"
+	// CapabilityStreaming is the streaming of the observations of an instance by StreamCSVRows
+	CapabilityStreaming Capability = "streaming"
+	// CapabilityBulkLoad is the writing of whole instances as files for the bulk loading tool of the database
+	CapabilityBulkLoad Capability = "bulk_load"
+	// CapabilityTransactions is the execution of several statements in a single transaction
+	CapabilityTransactions Capability = "transactions"
+)
+
+// Capabilities is the set of capabilities supported by a driver
+type Capabilities []Capability
+
+// Has returns true if the provided capability is supported
+func (c Capabilities) Has(capability Capability) bool {
+	for _, supported := range c {
+		if supported == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing returns the provided capabilities that are not supported, in the order provided
+func (c Capabilities) Missing(capabilities ...Capability) Capabilities {
+	var missing Capabilities
+	for _, capability := range capabilities {
+		if !c.Has(capability) {
+			missing = append(missing, capability)
+		}
+	}
+	return missing
+}
+
+// CapabilityReporter is implemented by drivers advertising the capabilities they support.
+// It is not part of the Driver interface so that drivers registered by other modules need not implement it.
+type CapabilityReporter interface {
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the capabilities of the provided driver, none if it does not report them
+func CapabilitiesOf(d interface{}) Capabilities {
+	if r, ok := d.(CapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package driver
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type reporter struct{}
+
+func (reporter) Capabilities() Capabilities {
+	return Capabilities{CapabilityStreaming, CapabilityOrderedCodes}
+}
+
+func TestCapabilities(t *testing.T) {
+	Convey("Given the capabilities of a driver", t, func() {
+		c := Capabilities{CapabilityStreaming, CapabilityOrderedCodes}
+
+		Convey("Then Has returns whether a capability is supported", func() {
+			So(c.Has(CapabilityStreaming), ShouldBeTrue)
+			So(c.Has(CapabilityTransactions), ShouldBeFalse)
+		})
+
+		Convey("Then Missing returns the capabilities not supported", func() {
+			So(c.Missing(CapabilityTransactions, CapabilityStreaming, CapabilityBulkLoad), ShouldResemble,
+				Capabilities{CapabilityTransactions, CapabilityBulkLoad})
+			So(c.Missing(CapabilityStreaming), ShouldBeEmpty)
+		})
+	})
+
+	Convey("When CapabilitiesOf is called", t, func() {
+		Convey("Then the capabilities of a driver reporting them are returned", func() {
+			So(CapabilitiesOf(reporter{}), ShouldResemble, Capabilities{CapabilityStreaming, CapabilityOrderedCodes})
+		})
+
+		Convey("Then no capabilities are returned for a driver not reporting them", func() {
+			So(CapabilitiesOf(struct{}{}), ShouldBeEmpty)
+		})
+	})
+
+	Convey("When ErrNotSupported is returned", t, func() {
+		err := ErrNotSupported{Capabilities: Capabilities{CapabilityTransactions, CapabilityBulkLoad}}
+
+		Convey("Then its message lists the capabilities not supported", func() {
+			So(err.Error(), ShouldEqual, "configured driver does not support: transactions, bulk_load")
+		})
+	})
+}
@@ -14,19 +14,28 @@ type Driver interface {
 	Close(ctx context.Context) error
 	Healthcheck() (string, error)
 	Checker(ctx context.Context, state *health.CheckState) error
+	// Stats returns statistics about the usage of the connection pool of the driver
+	Stats() PoolStats
 }
 
 // CodeList defines functions to retrieve code list and code nodes
 type CodeList interface {
 	GetCodeLists(ctx context.Context, filterBy string) (*models.CodeListResults, error)
+	// GetCodeListsByFlags returns the code lists which have all the provided type flags set
+	GetCodeListsByFlags(ctx context.Context, flags []string) (*models.CodeListResults, error)
 	GetCodeList(ctx context.Context, codeListID string) (*models.CodeList, error)
+	GetCodeListDetail(ctx context.Context, codeListID string) (*models.CodeListDetail, error)
 	GetEditions(ctx context.Context, codeListID string) (*models.Editions, error)
 	GetEdition(ctx context.Context, codeListID, edition string) (*models.Edition, error)
 	CountCodes(ctx context.Context, codeListID string, edition string) (int64, error)
-	GetCodes(ctx context.Context, codeListID, edition string) (*models.CodeResults, error)
-	GetCode(ctx context.Context, codeListID, edition string, code string) (*models.Code, error)
+	GetCodes(ctx context.Context, codeListID, edition string, opts ...ReadOption) (*models.CodeResults, error)
+	GetCode(ctx context.Context, codeListID, edition string, code string, opts ...ReadOption) (*models.Code, error)
 	GetCodeDatasets(ctx context.Context, codeListID, edition string, code string) (*models.Datasets, error)
 	GetCodesOrder(ctx context.Context, codeListID string, codes []string) (codeOrders map[string]*int, err error)
+	// GetCodesBatch looks up the label and order of codes across code lists. Codes that are not found are omitted from the result.
+	GetCodesBatch(ctx context.Context, refs []models.CodeRef) (map[models.CodeRef]models.CodeLookup, error)
+	// DiffEditions compares the codes used by two editions of a code list, and the label and order of their 'usedBy' edges
+	DiffEditions(ctx context.Context, codeListID, fromEdition, toEdition string) (*models.CodeListDiff, error)
 }
 
 // Hierarchy defines functions to create and retrieve generic and instance hierarchy nodes
@@ -34,13 +43,20 @@ type Hierarchy interface {
 	// read
 	HierarchyExists(ctx context.Context, instanceID, dimension string) (hierarchyExists bool, err error)
 	GetHierarchyCodelist(ctx context.Context, instanceID, dimension string) (string, error)
-	GetHierarchyRoot(ctx context.Context, instanceID, dimension string) (*models.HierarchyResponse, error)
-	GetHierarchyElement(ctx context.Context, instanceID, dimension, code string) (*models.HierarchyResponse, error)
+	GetHierarchyRoot(ctx context.Context, instanceID, dimension string, opts ...ReadOption) (*models.HierarchyResponse, error)
+	// GetHierarchyRoots returns all the nodes without a parent, as hierarchies can be forests
+	GetHierarchyRoots(ctx context.Context, instanceID, dimension string, opts ...ReadOption) ([]*models.HierarchyElement, error)
+	GetHierarchyElement(ctx context.Context, instanceID, dimension, code string, opts ...ReadOption) (*models.HierarchyResponse, error)
+	// SearchHierarchy returns up to limit nodes whose label in the requested language contains the provided text,
+	// ignoring case, with their breadcrumbs. Nodes whose label starts with the text are returned first.
+	SearchHierarchy(ctx context.Context, instanceID, dimension, text string, limit int, opts ...ReadOption) ([]*models.HierarchyElement, error)
 	GetCodesWithData(ctx context.Context, attempt int, instanceID, dimensionName string) (codes []string, err error)
 	GetGenericHierarchyNodeIDs(ctx context.Context, attempt int, codeListID string, codes []string) (nodeIDs map[string]string, err error)
 	GetGenericHierarchyAncestriesIDs(ctx context.Context, attempt int, codeListID string, codes []string) (nodeIDs map[string]string, err error)
 	CountNodes(ctx context.Context, instanceID, dimensionName string) (count int64, err error)
 	GetHierarchyNodeIDs(ctx context.Context, attempt int, instanceID, dimensionName string) (ids map[string]string, err error)
+	CheckGenericHierarchy(ctx context.Context, codeListID, edition string) (*models.HierarchyIntegrityReport, error)
+	CheckInstanceHierarchy(ctx context.Context, instanceID, dimension, edition string) (*models.HierarchyIntegrityReport, error)
 	// write
 	CreateInstanceHierarchyConstraints(ctx context.Context, attempt int, instanceID, dimensionName string) error
 	CloneNodes(ctx context.Context, attempt int, instanceID, codeListID, dimensionName string) error
@@ -54,9 +70,15 @@ type Hierarchy interface {
 	RemoveCloneEdges(ctx context.Context, attempt int, instanceID, dimensionName string) (err error)
 	RemoveCloneEdgesFromSourceIDs(ctx context.Context, attempt int, ids map[string]string) (err error)
 	SetHasData(ctx context.Context, attempt int, instanceID, dimensionName string) error
+	// SetRollupCounts is an optional step, run after SetHasData, which stores on each node the number of dimension
+	// options matching its code and the codes of its descendants, and the number of observations of those options
+	SetRollupCounts(ctx context.Context, attempt int, instanceID, dimensionName string) error
 	MarkNodesToRemain(ctx context.Context, attempt int, instanceID, dimensionName string) error
 	RemoveNodesNotMarkedToRemain(ctx context.Context, attempt int, instanceID, dimensionName string) error
 	RemoveRemainMarker(ctx context.Context, attempt int, instanceID, dimensionName string) error
+	// SyncInstanceHierarchy applies the label, parent and order changes made to the generic hierarchy since
+	// the instance hierarchy was cloned, preserving hasData and recomputing numberOfChildren
+	SyncInstanceHierarchy(ctx context.Context, instanceID, dimension string) (*models.HierarchySyncReport, error)
 }
 
 // Observation defines functions to create and retrieve observation nodes
@@ -66,6 +88,10 @@ type Observation interface {
 	// memory overhead.
 	StreamCSVRows(ctx context.Context, instanceID, filterID string, filters *observation.DimensionFilters, limit *int) (observation.StreamRowReader, error)
 	InsertObservationBatch(ctx context.Context, attempt int, instanceID string, observations []*models.Observation, dimensionIDs map[string]string) error
+	// RecordImportCheckpoint stores a checkpoint on the instance node once a batch has been inserted.
+	// Recording the same checkpoint more than once has no further effect.
+	RecordImportCheckpoint(ctx context.Context, instanceID string, checkpoint *models.ImportCheckpoint) error
+	GetImportProgress(ctx context.Context, instanceID string) (*models.ImportProgress, error)
 }
 
 // Instance defines functions to create, update and retrieve details about instances
@@ -78,6 +104,17 @@ type Instance interface {
 	CountInsertedObservations(ctx context.Context, instanceID string) (count int64, err error)
 	AddVersionDetailsToInstance(ctx context.Context, instanceID, datasetID, edition string, version int) error
 	SetInstanceIsPublished(ctx context.Context, instanceID string) error
+	SetInstancePublishState(ctx context.Context, instanceID string, state models.PublishState) error
+	GetInstance(ctx context.Context, instanceID string) (*models.Instance, error)
+	ListInstances(ctx context.Context, filter *models.InstanceFilter) ([]*models.Instance, error)
+	ValidateInstance(ctx context.Context, instanceID string) (*models.InstanceValidationReport, error)
+}
+
+// Dataset defines functions to navigate from a version of a dataset to the code lists and codes
+// it uses, following the 'inDataset' relationships from codes to the instance of the version
+type Dataset interface {
+	GetDatasetDimensions(ctx context.Context, datasetID, edition string, version int) (*models.DatasetDimensions, error)
+	GetDatasetCodes(ctx context.Context, datasetID, edition string, version int, codeListID string) (*models.CodeResults, error)
 }
 
 // Dimension defines functions to create dimension nodes
@@ -3,6 +3,7 @@ package driver
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // ErrNotFound is returned when the result set from the database held 0 records
@@ -15,6 +16,9 @@ var ErrMultipleFound = errors.New("multiple found where should be one")
 // ErrNotImplemented is returned when a method is called but the driver does not implement it
 var ErrNotImplemented = errors.New("method not implemented by driver")
 
+// ErrInstanceUnpublished is returned when reading the observations of an instance that has been unpublished
+var ErrInstanceUnpublished = errors.New("instance has been unpublished")
+
 // ErrAttemptsExceededLimit is returned when the number of attempts has reaced
 // the maximum permitted
 type ErrAttemptsExceededLimit struct {
@@ -33,3 +37,30 @@ type ErrNonRetriable struct {
 func (e ErrNonRetriable) Error() string {
 	return fmt.Sprintf("received a non retriable error from neo4j: %s", e.WrappedErr.Error())
 }
+
+// ErrMultipleRoots is returned when a hierarchy is expected to have a single root but has several
+type ErrMultipleRoots struct {
+	Codes []string
+}
+
+func (e ErrMultipleRoots) Error() string {
+	return fmt.Sprintf("hierarchy has multiple roots: %s", strings.Join(e.Codes, ", "))
+}
+
+// Is allows ErrMultipleRoots to be identified as ErrMultipleFound
+func (e ErrMultipleRoots) Is(target error) bool {
+	return target == ErrMultipleFound
+}
+
+// ErrNotSupported is returned when capabilities required by a service are not supported by the configured driver
+type ErrNotSupported struct {
+	Capabilities Capabilities
+}
+
+func (e ErrNotSupported) Error() string {
+	names := make([]string, len(e.Capabilities))
+	for i, c := range e.Capabilities {
+		names[i] = string(c)
+	}
+	return fmt.Sprintf("configured driver does not support: %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,128 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultLanguage is the language of the 'label' property of codes and hierarchy nodes.
+// Labels in other languages are held in 'label_<language>' properties, e.g. 'label_cy'.
+const DefaultLanguage = "en"
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// SortBy defines how the results of a read function are sorted
+type SortBy string
+
+// Possible sorts for the children of a hierarchy node and the codes of a code list edition
+const (
+	// SortDefault sorts children by their order property if they have one, or by label otherwise.
+	// Codes are sorted by their order in the edition if they have one, or by code otherwise.
+	SortDefault SortBy = ""
+	SortByOrder SortBy = "order"
+	SortByLabel SortBy = "label"
+	SortByCode  SortBy = "code"
+)
+
+// ReadOptions holds the options that can be provided to read functions
+type ReadOptions struct {
+	// Language is the language labels are returned in. Labels not available in the language
+	// fall back to the default language.
+	Language string
+	// Offset is the number of children of a hierarchy node to skip
+	Offset int
+	// Limit is the maximum number of children of a hierarchy node to return, 0 meaning no limit
+	Limit int
+	// Sort is how the children of a hierarchy node or the codes of a code list edition are sorted
+	Sort SortBy
+	// SingleRoot requires a hierarchy to have a single root when getting its root
+	SingleRoot bool
+}
+
+// ReadOption sets an option of a read function
+type ReadOption func(*ReadOptions)
+
+// WithLanguage requests labels in the provided ISO 639 language code, e.g. "cy"
+func WithLanguage(language string) ReadOption {
+	return func(o *ReadOptions) {
+		o.Language = language
+	}
+}
+
+// WithOffset skips the provided number of children of a hierarchy node
+func WithOffset(offset int) ReadOption {
+	return func(o *ReadOptions) {
+		o.Offset = offset
+	}
+}
+
+// WithLimit returns at most the provided number of children of a hierarchy node
+func WithLimit(limit int) ReadOption {
+	return func(o *ReadOptions) {
+		o.Limit = limit
+	}
+}
+
+// WithSort sorts the children of a hierarchy node as provided
+func WithSort(sort SortBy) ReadOption {
+	return func(o *ReadOptions) {
+		o.Sort = sort
+	}
+}
+
+// WithSingleRoot makes getting the root of a hierarchy fail with ErrMultipleRoots if the hierarchy has several roots
+func WithSingleRoot() ReadOption {
+	return func(o *ReadOptions) {
+		o.SingleRoot = true
+	}
+}
+
+// NewReadOptions returns the default read options with the provided options applied,
+// or an error if the resulting options are not valid
+func NewReadOptions(opts ...ReadOption) (*ReadOptions, error) {
+	o := &ReadOptions{
+		Language: DefaultLanguage,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if !languagePattern.MatchString(o.Language) {
+		return nil, fmt.Errorf("invalid language %q", o.Language)
+	}
+	if o.Offset < 0 {
+		return nil, fmt.Errorf("invalid offset %d", o.Offset)
+	}
+	if o.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", o.Limit)
+	}
+	switch o.Sort {
+	case SortDefault, SortByOrder, SortByLabel, SortByCode:
+	default:
+		return nil, fmt.Errorf("invalid sort %q", o.Sort)
+	}
+	return o, nil
+}
+
+// IsPaginated returns true if only a page of the children of a hierarchy node is requested
+func (o *ReadOptions) IsPaginated() bool {
+	return o.Offset > 0 || o.Limit > 0
+}
+
+// IsDefaultLanguage returns true if labels are requested in the default language
+func (o *ReadOptions) IsDefaultLanguage() bool {
+	return o.Language == DefaultLanguage
+}
+
+// LabelProperty returns the name of the property holding labels in the requested language
+func (o *ReadOptions) LabelProperty() string {
+	return LabelProperty(o.Language)
+}
+
+// LabelProperty returns the name of the property holding labels in the provided language
+func LabelProperty(language string) string {
+	if language == "" || language == DefaultLanguage {
+		return "label"
+	}
+	return "label_" + language
+}
@@ -0,0 +1,81 @@
+package driver
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Config holds the configuration provided to the factory of a driver. Zero values are left to the driver to default.
+type Config struct {
+	// Address is the address of the database
+	Address string
+	// ReaderAddress is the address of a reader endpoint used for read-only queries, if supported
+	ReaderAddress string
+	// PoolSize is the size of the connection pool
+	PoolSize int
+	// PoolMaxLifetime is the maximum time a connection is kept open
+	PoolMaxLifetime time.Duration
+	// PoolIdleTimeout is the time after which the connections of a pool are closed if none have been used
+	PoolIdleTimeout time.Duration
+	// MaxRetries is the maximum number of attempts for transient query failures
+	MaxRetries int
+	// RetryTime is the initial sleep time between attempts of a query
+	RetryTime time.Duration
+	// QueryTimeout is the maximum number of seconds allowed for a query
+	QueryTimeout int
+	// LoadSettings decodes the settings specific to a backend into the struct pointed to by settings, which the
+	// factory of the driver declares with the name of their environment variable in envconfig tags. It is nil if
+	// no settings are provided.
+	LoadSettings func(settings interface{}) error
+}
+
+// Factory returns a new driver with the provided configuration, sending asynchronous errors to errs
+type Factory func(cfg Config, errs chan error) (Driver, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a driver available by the provided name, which is chosen with the GRAPH_DRIVER_TYPE
+// environment variable. It is typically called in the init function of the package implementing the driver,
+// and panics if the factory is nil or a driver is already registered with the name.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("graph: Register factory is nil")
+	}
+	if _, exists := factories[name]; exists {
+		panic("graph: Register called twice for driver " + name)
+	}
+	factories[name] = factory
+}
+
+// Drivers returns the sorted names of the registered drivers
+func Drivers() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open returns a new driver created by the factory registered with the provided name
+func Open(name string, cfg Config, errs chan error) (Driver, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("graph: unknown driver %q (forgotten import?)", name)
+	}
+	return factory(cfg, errs)
+}
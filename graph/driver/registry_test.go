@@ -0,0 +1,60 @@
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRegistry(t *testing.T) {
+	Convey("Given a driver registered with a factory", t, func() {
+		errFactory := errors.New("factory called")
+		var got Config
+		Register("registry-test", func(cfg Config, errs chan error) (Driver, error) {
+			got = cfg
+			return nil, errFactory
+		})
+		defer func() {
+			factoriesMu.Lock()
+			delete(factories, "registry-test")
+			factoriesMu.Unlock()
+		}()
+
+		Convey("Then it is listed in the registered drivers", func() {
+			So(Drivers(), ShouldContain, "registry-test")
+		})
+
+		Convey("When Open is called with its name", func() {
+			_, err := Open("registry-test", Config{Address: "addr", PoolSize: 3}, nil)
+
+			Convey("Then the factory is called with the configuration", func() {
+				So(err, ShouldEqual, errFactory)
+				So(got, ShouldResemble, Config{Address: "addr", PoolSize: 3})
+			})
+		})
+
+		Convey("When a driver is registered again with the same name", func() {
+			Convey("Then Register panics", func() {
+				So(func() {
+					Register("registry-test", func(cfg Config, errs chan error) (Driver, error) { return nil, nil })
+				}, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("When a driver is registered with a nil factory", t, func() {
+		Convey("Then Register panics", func() {
+			So(func() { Register("registry-nil", nil) }, ShouldPanic)
+		})
+	})
+
+	Convey("When Open is called with a name not registered", t, func() {
+		d, err := Open("registry-unknown", Config{}, nil)
+
+		Convey("Then an error is returned", func() {
+			So(d, ShouldBeNil)
+			So(err.Error(), ShouldEqual, `graph: unknown driver "registry-unknown" (forgotten import?)`)
+		})
+	})
+}
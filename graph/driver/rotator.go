@@ -0,0 +1,203 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// PoolRotator holds the connection pool of a driver, replacing it with a new pool once it has been idle for the idle
+// timeout, or open for the max lifetime, if provided. The pools used by the drivers cannot close individual connections,
+// so the connections of a pool that has been replaced are closed with the pool, once none of them are in use.
+// Pools are expected to open their connections on first use, so that a new pool does not hold any connection, and to keep
+// them open until closed.
+type PoolRotator[P any] struct {
+	newPool     func() (P, error)
+	closePool   func(P) error
+	idleTimeout time.Duration
+	maxLifetime time.Duration
+
+	mu       sync.Mutex
+	current  *poolGeneration[P]
+	retiring map[*poolGeneration[P]]struct{}
+	closed   bool
+	stop     chan struct{}
+}
+
+// poolGeneration is a pool held by a PoolRotator, with the usage deciding when it is replaced and closed
+type poolGeneration[P any] struct {
+	pool     P
+	created  time.Time
+	lastUsed time.Time
+	used     bool
+	inUse    int
+	open     int
+	retired  bool
+}
+
+// NewPoolRotator returns a PoolRotator holding the provided pool, and replacing it with pools returned by newPool once
+// it has been idle for idleTimeout or open for maxLifetime, 0 meaning never. Pools replaced are closed with closePool.
+func NewPoolRotator[P any](pool P, newPool func() (P, error), closePool func(P) error, idleTimeout, maxLifetime time.Duration) *PoolRotator[P] {
+	r := &PoolRotator[P]{
+		newPool:     newPool,
+		closePool:   closePool,
+		idleTimeout: idleTimeout,
+		maxLifetime: maxLifetime,
+		current:     &poolGeneration[P]{pool: pool, created: time.Now()},
+		retiring:    make(map[*poolGeneration[P]]struct{}),
+	}
+
+	interval := checkInterval(idleTimeout, maxLifetime)
+	if newPool != nil && interval > 0 {
+		r.stop = make(chan struct{})
+		go r.run(interval)
+	}
+	return r
+}
+
+// checkInterval returns how often to check whether the pool should be replaced, 0 if it never should
+func checkInterval(idleTimeout, maxLifetime time.Duration) time.Duration {
+	d := idleTimeout
+	if maxLifetime > 0 && (d == 0 || maxLifetime < d) {
+		d = maxLifetime
+	}
+	return d / 2
+}
+
+// Acquire returns the current pool, which is not closed until the returned release function is called
+func (r *PoolRotator[P]) Acquire() (P, func()) {
+	r.mu.Lock()
+	g := r.current
+	g.inUse++
+	if g.inUse > g.open {
+		g.open = g.inUse
+	}
+	g.used = true
+	r.mu.Unlock()
+
+	var once sync.Once
+	return g.pool, func() {
+		once.Do(func() { r.release(g) })
+	}
+}
+
+func (r *PoolRotator[P]) release(g *poolGeneration[P]) {
+	r.mu.Lock()
+	g.inUse--
+	g.lastUsed = time.Now()
+	closing := g.retired && g.inUse == 0
+	if closing {
+		delete(r.retiring, g)
+	}
+	r.mu.Unlock()
+
+	if closing {
+		r.retire(g.pool)
+	}
+}
+
+// Open returns an estimate of the number of connections held by the pools: the highest number of connections that have
+// been in use at the same time in the current pool, and in the pools replaced that are not closed yet.
+func (r *PoolRotator[P]) Open() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	open := 0
+	if !r.closed {
+		open = r.current.open
+	}
+	for g := range r.retiring {
+		open += g.open
+	}
+	return open
+}
+
+func (r *PoolRotator[P]) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case now := <-ticker.C:
+			r.rotate(now)
+		}
+	}
+}
+
+// rotate replaces the current pool if it has been idle for the idle timeout or open for the max lifetime.
+// Unused pools are never replaced, as they do not hold any connection. The new pool is created without holding
+// the lock, so that connections keep being acquired and released meanwhile, and is closed if the current pool
+// no longer needs replacing once it is created.
+func (r *PoolRotator[P]) rotate(now time.Time) {
+	r.mu.Lock()
+	g := r.current
+	idle, expired := r.needsReplacing(g, now)
+	r.mu.Unlock()
+
+	if !idle && !expired {
+		return
+	}
+
+	pool, err := r.newPool()
+	if err != nil {
+		log.Error(context.Background(), "failed to replace the connection pool", err, log.Data{"idle": idle, "expired": expired})
+		return
+	}
+
+	r.mu.Lock()
+	if idle, expired = r.needsReplacing(g, now); r.current != g || (!idle && !expired) {
+		r.mu.Unlock()
+		r.retire(pool)
+		return
+	}
+
+	g.retired = true
+	r.current = &poolGeneration[P]{pool: pool, created: now}
+	closing := g.inUse == 0
+	if !closing {
+		r.retiring[g] = struct{}{}
+	}
+	r.mu.Unlock()
+
+	if closing {
+		r.retire(g.pool)
+	}
+}
+
+// needsReplacing returns whether the provided pool is idle or expired, and should be replaced. r.mu must be held.
+func (r *PoolRotator[P]) needsReplacing(g *poolGeneration[P], now time.Time) (idle, expired bool) {
+	if r.closed || !g.used {
+		return false, false
+	}
+	idle = r.idleTimeout > 0 && g.inUse == 0 && now.Sub(g.lastUsed) >= r.idleTimeout
+	expired = r.maxLifetime > 0 && now.Sub(g.created) >= r.maxLifetime
+	return idle, expired
+}
+
+// retire closes a pool that has been replaced
+func (r *PoolRotator[P]) retire(pool P) {
+	if err := r.closePool(pool); err != nil {
+		log.Error(context.Background(), "failed to close a replaced connection pool", err)
+	}
+}
+
+// Close closes the current pool, and the pools replaced once none of their connections are in use
+func (r *PoolRotator[P]) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	if r.stop != nil {
+		close(r.stop)
+	}
+	pool := r.current.pool
+	r.mu.Unlock()
+
+	return r.closePool(pool)
+}
@@ -0,0 +1,193 @@
+package driver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testPool struct {
+	id     int
+	closed int
+}
+
+func newTestRotator(idleTimeout, maxLifetime time.Duration) (*PoolRotator[*testPool], *[]*testPool) {
+	created := []*testPool{{id: 0}}
+	r := NewPoolRotator(created[0], func() (*testPool, error) {
+		p := &testPool{id: len(created)}
+		created = append(created, p)
+		return p, nil
+	}, func(p *testPool) error {
+		p.closed++
+		return nil
+	}, idleTimeout, maxLifetime)
+	return r, &created
+}
+
+func TestPoolRotator(t *testing.T) {
+	Convey("Given a rotator with an idle timeout", t, func() {
+		r, created := newTestRotator(time.Hour, 0)
+		defer r.Close()
+
+		Convey("When the pool has not been used", func() {
+			r.rotate(time.Now().Add(2 * time.Hour))
+
+			Convey("Then it is not replaced, as it holds no connection", func() {
+				pool, release := r.Acquire()
+				release()
+				So(pool.id, ShouldEqual, 0)
+				So(*created, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the pool has been used and is idle for the idle timeout", func() {
+			_, release := r.Acquire()
+			release()
+			r.rotate(time.Now().Add(2 * time.Hour))
+
+			Convey("Then it is replaced by a new pool and closed", func() {
+				pool, release := r.Acquire()
+				release()
+				So(pool.id, ShouldEqual, 1)
+				So((*created)[0].closed, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the pool has been idle for less than the idle timeout", func() {
+			_, release := r.Acquire()
+			release()
+			r.rotate(time.Now().Add(time.Minute))
+
+			Convey("Then it is not replaced", func() {
+				So(*created, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When a connection of the pool is in use", func() {
+			_, release := r.Acquire()
+			r.rotate(time.Now().Add(2 * time.Hour))
+
+			Convey("Then the pool is not idle and is not replaced", func() {
+				So(*created, ShouldHaveLength, 1)
+				release()
+			})
+		})
+	})
+
+	Convey("Given a rotator with a max lifetime", t, func() {
+		r, _ := newTestRotator(0, time.Hour)
+		defer r.Close()
+
+		Convey("When the pool has been open for the max lifetime while a connection is in use", func() {
+			old, release := r.Acquire()
+			r.rotate(time.Now().Add(2 * time.Hour))
+
+			Convey("Then new connections are acquired from a new pool", func() {
+				pool, releaseNew := r.Acquire()
+				releaseNew()
+				So(pool.id, ShouldEqual, 1)
+
+				Convey("And the connections of both pools are open", func() {
+					So(r.Open(), ShouldEqual, 2)
+				})
+
+				Convey("And the old pool is only closed once its connection is released", func() {
+					So(old.closed, ShouldEqual, 0)
+					release()
+					release()
+					So(old.closed, ShouldEqual, 1)
+					So(r.Open(), ShouldEqual, 1)
+				})
+			})
+		})
+	})
+
+	Convey("Given a rotator failing to create a new pool", t, func() {
+		pool := &testPool{}
+		r := NewPoolRotator(pool, func() (*testPool, error) {
+			return nil, errors.New("no more pools")
+		}, func(p *testPool) error {
+			p.closed++
+			return nil
+		}, time.Hour, 0)
+
+		Convey("When the pool is idle for the idle timeout", func() {
+			_, release := r.Acquire()
+			release()
+			r.rotate(time.Now().Add(2 * time.Hour))
+
+			Convey("Then the pool is kept", func() {
+				current, release := r.Acquire()
+				release()
+				So(current, ShouldEqual, pool)
+				So(pool.closed, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When the rotator is closed", func() {
+			So(r.Close(), ShouldBeNil)
+			So(r.Close(), ShouldBeNil)
+
+			Convey("Then the pool is closed once", func() {
+				So(pool.closed, ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given a rotator whose pool is used while its replacement is created", t, func() {
+		var r *PoolRotator[*testPool]
+		var release func()
+		first, spare := &testPool{id: 0}, &testPool{id: 1}
+		r = NewPoolRotator(first, func() (*testPool, error) {
+			// acquiring would block if the lock was held while the new pool is created
+			_, release = r.Acquire()
+			return spare, nil
+		}, func(p *testPool) error {
+			p.closed++
+			return nil
+		}, time.Hour, 0)
+		defer r.Close()
+
+		Convey("When the pool is idle for the idle timeout", func() {
+			_, releaseFirst := r.Acquire()
+			releaseFirst()
+			r.rotate(time.Now().Add(2 * time.Hour))
+
+			Convey("Then the pool in use is kept and the new pool is closed", func() {
+				current, releaseCurrent := r.Acquire()
+				releaseCurrent()
+				release()
+				So(current, ShouldEqual, first)
+				So(first.closed, ShouldEqual, 0)
+				So(spare.closed, ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given a rotator checking the pool periodically", t, func() {
+		r, created := newTestRotator(10*time.Millisecond, 0)
+
+		Convey("When the pool is used and left idle", func() {
+			_, release := r.Acquire()
+			release()
+
+			Convey("Then it is eventually replaced", func() {
+				So(func() bool {
+					for i := 0; i < 100; i++ {
+						r.mu.Lock()
+						n := len(*created)
+						r.mu.Unlock()
+						if n > 1 {
+							return true
+						}
+						time.Sleep(5 * time.Millisecond)
+					}
+					return false
+				}(), ShouldBeTrue)
+				So(r.Close(), ShouldBeNil)
+			})
+		})
+	})
+}
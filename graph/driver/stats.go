@@ -0,0 +1,126 @@
+package driver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// PoolStats holds statistics about the usage of the connection pool of a driver. The pools used by the drivers
+// do not expose how many connections they hold, so the statistics are recorded from the queries made through
+// them: a connection is in use while a query or a stream of results holds it, and open from its first use
+// until its pool is closed.
+type PoolStats struct {
+	// MaxOpenConnections is the maximum number of connections in use at the same time, 0 meaning unlimited
+	MaxOpenConnections int
+	// Open is the number of connections open, either in use or idle
+	Open int
+	// InUse is the number of connections currently in use
+	InUse int
+	// Idle is the number of connections open but not in use
+	Idle int
+	// WaitCount is the total number of times a connection had to be waited for, as all were in use
+	WaitCount int64
+	// WaitDuration is the total time spent waiting for a connection
+	WaitDuration time.Duration
+}
+
+// LogData returns the statistics as log data
+func (s PoolStats) LogData() log.Data {
+	return log.Data{
+		"max_open_connections": s.MaxOpenConnections,
+		"open":                 s.Open,
+		"in_use":               s.InUse,
+		"idle":                 s.Idle,
+		"wait_count":           s.WaitCount,
+		"wait_duration":        s.WaitDuration.String(),
+	}
+}
+
+// IsExhausted returns true if all the connections of a pool with a maximum number of connections are in use
+func (s PoolStats) IsExhausted() bool {
+	return s.MaxOpenConnections > 0 && s.InUse >= s.MaxOpenConnections
+}
+
+// PoolStatsRecorder records the usage of a connection pool that does not provide statistics itself
+type PoolStatsRecorder struct {
+	maxOpen int
+	open    func() int
+
+	mu           sync.Mutex
+	inUse        int
+	waitCount    int64
+	waitDuration time.Duration
+}
+
+// NewPoolStatsRecorder returns a recorder for a pool with the provided maximum number of connections, 0 meaning unlimited.
+// open returns the number of connections open, such as PoolRotator.Open, and may be nil if only those in use are known.
+func NewPoolStatsRecorder(maxOpen int, open func() int) *PoolStatsRecorder {
+	return &PoolStatsRecorder{maxOpen: maxOpen, open: open}
+}
+
+// Acquire calls the provided function to get a connection from the pool, recording the time spent waiting
+// for it if all the connections were in use. Release must be called once the connection is no longer in use
+// if no error is returned.
+func (r *PoolStatsRecorder) Acquire(acquire func() error) error {
+	start := time.Now()
+
+	r.mu.Lock()
+	wait := r.maxOpen > 0 && r.inUse >= r.maxOpen
+	r.mu.Unlock()
+
+	err := acquire()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if wait {
+		r.waitCount++
+		r.waitDuration += time.Since(start)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	r.inUse++
+	return nil
+}
+
+// Release records that a connection acquired from the pool is no longer in use
+func (r *PoolStatsRecorder) Release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.inUse > 0 {
+		r.inUse--
+	}
+}
+
+// Stats returns the statistics recorded so far. The number of connections open is capped by the maximum, as requests
+// waiting for a connection may have been counted, and is at least the number in use.
+func (r *PoolStatsRecorder) Stats() PoolStats {
+	open := 0
+	if r.open != nil {
+		open = r.open()
+	}
+	if r.maxOpen > 0 && open > r.maxOpen {
+		open = r.maxOpen
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if open < r.inUse {
+		open = r.inUse
+	}
+	return PoolStats{
+		MaxOpenConnections: r.maxOpen,
+		Open:               open,
+		InUse:              r.inUse,
+		Idle:               open - r.inUse,
+		WaitCount:          r.waitCount,
+		WaitDuration:       r.waitDuration,
+	}
+}
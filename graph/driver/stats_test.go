@@ -0,0 +1,74 @@
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPoolStatsRecorder(t *testing.T) {
+	Convey("Given a recorder for a pool of two connections", t, func() {
+		open := 2
+		r := NewPoolStatsRecorder(2, func() int { return open })
+		acquired := func() error { return nil }
+
+		Convey("When two connections are acquired and one is released", func() {
+			So(r.Acquire(acquired), ShouldBeNil)
+			So(r.Acquire(acquired), ShouldBeNil)
+			r.Release()
+
+			Convey("Then the statistics show two connections open, one in use and one idle", func() {
+				So(r.Stats(), ShouldResemble, PoolStats{
+					MaxOpenConnections: 2,
+					Open:               2,
+					InUse:              1,
+					Idle:               1,
+				})
+				So(r.Stats().IsExhausted(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a connection is acquired while all are in use", func() {
+			So(r.Acquire(acquired), ShouldBeNil)
+			So(r.Acquire(acquired), ShouldBeNil)
+			So(r.Stats().IsExhausted(), ShouldBeTrue)
+
+			err := r.Acquire(func() error { return errors.New("pool closed") })
+
+			Convey("Then the wait is recorded and the failed acquisition is not counted as in use", func() {
+				So(err, ShouldNotBeNil)
+				stats := r.Stats()
+				So(stats.WaitCount, ShouldEqual, 1)
+				So(stats.WaitDuration, ShouldBeGreaterThan, 0)
+				So(stats.InUse, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When more connections are counted as open than the maximum", func() {
+			open = 3
+
+			Convey("Then the number of connections open is capped by the maximum", func() {
+				So(r.Stats().Open, ShouldEqual, 2)
+				So(r.Stats().Idle, ShouldEqual, 2)
+			})
+		})
+	})
+
+	Convey("Given a recorder for an unlimited pool", t, func() {
+		r := NewPoolStatsRecorder(0, nil)
+
+		Convey("When many connections are acquired", func() {
+			for i := 0; i < 10; i++ {
+				So(r.Acquire(func() error { return nil }), ShouldBeNil)
+			}
+
+			Convey("Then no wait is recorded and the pool is never exhausted", func() {
+				So(r.Stats().WaitCount, ShouldEqual, 0)
+				So(r.Stats().Open, ShouldEqual, 10)
+				So(r.Stats().Idle, ShouldEqual, 0)
+				So(r.Stats().IsExhausted(), ShouldBeFalse)
+			})
+		})
+	})
+}
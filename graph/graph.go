@@ -21,6 +21,7 @@ type DB struct {
 	driver.Instance
 	driver.Observation
 	driver.Dimension
+	driver.Dataset
 
 	Errors chan error
 }
@@ -30,6 +31,25 @@ func (db DB) ErrorChan() chan error {
 	return db.Errors
 }
 
+// Capabilities returns the optional features supported by the configured driver
+func (db DB) Capabilities() driver.Capabilities {
+	return driver.CapabilitiesOf(db.Driver)
+}
+
+// Supports returns true if the configured driver supports the provided capability
+func (db DB) Supports(capability driver.Capability) bool {
+	return db.Capabilities().Has(capability)
+}
+
+// Require returns ErrNotSupported with the provided capabilities the configured driver does not support, if any,
+// allowing services to fail fast on startup
+func (db DB) Require(capabilities ...driver.Capability) error {
+	if missing := db.Capabilities().Missing(capabilities...); len(missing) > 0 {
+		return driver.ErrNotSupported{Capabilities: missing}
+	}
+	return nil
+}
+
 // Subsets allows a clear and concise way of requesting any combination of
 // functionality by groups of node types
 type Subsets struct {
@@ -38,6 +58,7 @@ type Subsets struct {
 	Instance    bool
 	Observation bool
 	Dimension   bool
+	Dataset     bool
 }
 
 // NewCodeListStore returns a configured DB containing the CodeList functionality
@@ -65,6 +86,11 @@ func NewDimensionStore(ctx context.Context) (*DB, error) {
 	return New(ctx, Subsets{Dimension: true})
 }
 
+// NewDatasetStore returns a configured DB containing the Dataset functionality
+func NewDatasetStore(ctx context.Context) (*DB, error) {
+	return New(ctx, Subsets{Dataset: true})
+}
+
 // New DB returned according to provided subsets and the environment config
 // satisfying the interfaces requested by the choice of subsets
 func New(ctx context.Context, choice Subsets) (*DB, error) {
@@ -75,6 +101,27 @@ func New(ctx context.Context, choice Subsets) (*DB, error) {
 		return nil, err
 	}
 
+	return newDB(ctx, cfg, choice, errs)
+}
+
+// NewWithConfig returns a DB according to provided subsets and configuration, independently of the
+// environment and of any other DB. The configured driver is used if cfg.Driver is set, in which case
+// its asynchronous errors are not sent to the error channel of the DB.
+func NewWithConfig(ctx context.Context, cfg config.Configuration, choice Subsets) (*DB, error) {
+	errs := make(chan error)
+
+	if cfg.Driver == nil {
+		d, err := cfg.NewDriver(errs)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Driver = d
+	}
+
+	return newDB(ctx, &cfg, choice, errs)
+}
+
+func newDB(ctx context.Context, cfg *config.Configuration, choice Subsets, errs chan error) (*DB, error) {
 	log.Info(ctx, "loaded graph database config", log.Data{"config": cfg})
 
 	var ok bool
@@ -113,6 +160,13 @@ func New(ctx context.Context, choice Subsets) (*DB, error) {
 		}
 	}
 
+	var dataset driver.Dataset
+	if choice.Dataset {
+		if dataset, ok = cfg.Driver.(driver.Dataset); !ok {
+			return nil, errors.New("configured driver does not implement dataset subset")
+		}
+	}
+
 	return &DB{
 		cfg.Driver,
 		codelist,
@@ -120,6 +174,7 @@ func New(ctx context.Context, choice Subsets) (*DB, error) {
 		instance,
 		observation,
 		dimension,
+		dataset,
 		errs,
 	}, nil
 }
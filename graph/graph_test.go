@@ -5,7 +5,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/ONSdigital/dp-graph/v2/config"
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/mock"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -15,7 +17,7 @@ func Test_New(t *testing.T) {
 
 	Convey("Given all subsets are requested", t, func() {
 		Convey("When New is called", func() {
-			db, err := New(context.Background(), Subsets{true, true, true, true, true})
+			db, err := New(context.Background(), Subsets{true, true, true, true, true, true})
 
 			Convey("Then the returned error should be nil and the returned db should satisfy all interfaces", func() {
 				So(err, ShouldBeNil)
@@ -26,13 +28,14 @@ func Test_New(t *testing.T) {
 				var _ driver.Instance = (*DB)(db)
 				var _ driver.Observation = (*DB)(db)
 				var _ driver.Dimension = (*DB)(db)
+				var _ driver.Dataset = (*DB)(db)
 			})
 		})
 	})
 
 	Convey("Given only 1 subset is requested", t, func() {
 		Convey("When New is called", func() {
-			db, err := New(context.Background(), Subsets{true, false, false, false, false})
+			db, err := New(context.Background(), Subsets{true, false, false, false, false, false})
 
 			Convey("Then the returned error should be nil and the returned db should satisfy only that interface", func() {
 				So(err, ShouldBeNil)
@@ -52,6 +55,93 @@ func Test_New(t *testing.T) {
 	})
 }
 
+func Test_NewWithConfig(t *testing.T) {
+	os.Setenv("GRAPH_DRIVER_TYPE", "")
+
+	Convey("Given a configuration choosing the mock driver, independently of the environment", t, func() {
+		cfg := config.Configuration{DriverChoice: "mock"}
+
+		Convey("When NewWithConfig is called", func() {
+			db, err := NewWithConfig(context.Background(), cfg, Subsets{CodeList: true})
+
+			Convey("Then a db with the configured driver and the requested subsets is returned", func() {
+				So(err, ShouldBeNil)
+				_, ok := db.Driver.(*mock.Mock)
+				So(ok, ShouldBeTrue)
+				So(db.CodeList, ShouldNotBeNil)
+				So(db.Hierarchy, ShouldBeNil)
+				So(db.Errors, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a configuration with an instantiated driver", t, func() {
+		d := Test(true, true, true)
+		cfg := config.Configuration{Driver: d}
+
+		Convey("When NewWithConfig is called", func() {
+			db, err := NewWithConfig(context.Background(), cfg, Subsets{Dataset: true})
+
+			Convey("Then the driver is used", func() {
+				So(err, ShouldBeNil)
+				So(db.Driver, ShouldEqual, d)
+				So(db.Dataset, ShouldEqual, d)
+			})
+		})
+	})
+
+	Convey("Given a configuration without a driver choice", t, func() {
+		cfg := config.Configuration{}
+
+		Convey("When NewWithConfig is called", func() {
+			db, err := NewWithConfig(context.Background(), cfg, Subsets{})
+
+			Convey("Then an error is returned", func() {
+				So(db, ShouldBeNil)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func Test_Capabilities(t *testing.T) {
+	Convey("Given a db with the mock driver", t, func() {
+		db, err := NewWithConfig(context.Background(), config.Configuration{DriverChoice: "mock"}, Subsets{})
+		So(err, ShouldBeNil)
+
+		Convey("Then the capabilities of the driver are reported", func() {
+			So(db.Capabilities(), ShouldContain, driver.CapabilitySparseHierarchy)
+			So(db.Supports(driver.CapabilityStreaming), ShouldBeTrue)
+			So(db.Supports(driver.CapabilityTransactions), ShouldBeFalse)
+		})
+
+		Convey("When Require is called with supported capabilities", func() {
+			err := db.Require(driver.CapabilityOrderedCodes, driver.CapabilityBulkLoad)
+
+			Convey("Then no error is returned", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When Require is called with a capability not supported", func() {
+			err := db.Require(driver.CapabilityStreaming, driver.CapabilityTransactions)
+
+			Convey("Then the missing capability is returned in the error", func() {
+				So(err, ShouldResemble, driver.ErrNotSupported{Capabilities: driver.Capabilities{driver.CapabilityTransactions}})
+			})
+		})
+	})
+
+	Convey("Given a db with a driver not reporting its capabilities", t, func() {
+		db := DB{}
+
+		Convey("Then no capability is supported", func() {
+			So(db.Capabilities(), ShouldBeEmpty)
+			So(db.Require(driver.CapabilityStreaming), ShouldNotBeNil)
+		})
+	})
+}
+
 func Test_NewCodeListStore(t *testing.T) {
 	os.Setenv("GRAPH_DRIVER_TYPE", "mock")
 
@@ -236,3 +326,32 @@ func Test_NewDimensionStore(t *testing.T) {
 		})
 	})
 }
+
+func Test_NewDatasetStore(t *testing.T) {
+	os.Setenv("GRAPH_DRIVER_TYPE", "mock")
+
+	Convey("Given only dataset subset is requested", t, func() {
+		Convey("When NewDatasetStore is called", func() {
+			db, err := NewDatasetStore(context.Background())
+
+			Convey("Then the returned error should be nil and the returned db should satisfy only that interface", func() {
+				So(err, ShouldBeNil)
+
+				var _ driver.Driver = (*DB)(db)
+				var _ driver.Dataset = (*DB)(db)
+
+				So(func() {
+					db.GetCodeList(context.Background(), "list_id")
+				}, ShouldPanic)
+
+				So(func() {
+					db.AddVersionDetailsToInstance(context.Background(), "instance_id", "dataset_id", "edition", 1)
+				}, ShouldPanic)
+
+				So(func() {
+					db.GetDatasetDimensions(context.Background(), "dataset_id", "edition", 1)
+				}, ShouldNotPanic)
+			})
+		})
+	})
+}
@@ -0,0 +1,109 @@
+/*
+Package bulkfile writes the CSV files and manifests generated for the offline import of an
+instance, shared by the neo4j-admin import and Neptune bulk loader writers.
+*/
+package bulkfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// FileCreator creates (or truncates) the named file and returns a writer for it
+type FileCreator func(name string) (io.WriteCloser, error)
+
+// DirFileCreator returns a FileCreator that creates files in the provided directory
+func DirFileCreator(dir string) FileCreator {
+	return func(name string) (io.WriteCloser, error) {
+		return os.Create(filepath.Join(dir, name))
+	}
+}
+
+// File is an open CSV file, keeping track of the number of records written to it
+type File struct {
+	Name  string
+	Count int64
+
+	closer io.Closer
+	csv    *csv.Writer
+}
+
+// Open creates the named file with the FileCreator and writes the provided header to it.
+// kind describes the file in errors, e.g. "bulk load file".
+func Open(create FileCreator, kind, name string, header []string) (*File, error) {
+	wc, err := create(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s %q", kind, name)
+	}
+
+	f := &File{
+		Name:   name,
+		closer: wc,
+		csv:    csv.NewWriter(wc),
+	}
+
+	// the header is not a record, so it is not counted
+	if err := f.csv.Write(header); err != nil {
+		wc.Close()
+		return nil, errors.Wrapf(err, "failed to write header to %s %q", kind, name)
+	}
+	return f, nil
+}
+
+// Write writes a record to the file
+func (f *File) Write(record []string) error {
+	if err := f.csv.Write(record); err != nil {
+		return err
+	}
+	f.Count++
+	return nil
+}
+
+// Close flushes and closes the file
+func (f *File) Close() error {
+	f.csv.Flush()
+	if err := f.csv.Error(); err != nil {
+		f.closer.Close()
+		return err
+	}
+	return f.closer.Close()
+}
+
+// CloseAll closes the provided files, skipping those that were never opened, and returns the first error encountered.
+// kind describes the files in errors, e.g. "bulk load file".
+func CloseAll(kind string, files ...*File) (err error) {
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = errors.Wrapf(closeErr, "failed to close %s %q", kind, f.Name)
+		}
+	}
+	return err
+}
+
+// WriteManifest writes the provided manifest as indented JSON to the named file.
+// kind describes the manifest in errors, e.g. "bulk load manifest".
+func WriteManifest(create FileCreator, kind, name string, manifest interface{}) error {
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", kind)
+	}
+
+	wc, err := create(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", kind)
+	}
+
+	if _, err := wc.Write(b); err != nil {
+		wc.Close()
+		return errors.Wrapf(err, "failed to write %s", kind)
+	}
+	return wc.Close()
+}
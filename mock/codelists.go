@@ -3,6 +3,7 @@ package mock
 import (
 	"context"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
 )
 
@@ -27,6 +28,20 @@ func (m *Mock) GetCodeLists(ctx context.Context, filterBy string) (*models.CodeL
 	}, nil
 }
 
+func (m *Mock) GetCodeListsByFlags(ctx context.Context, flags []string) (*models.CodeListResults, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	return &models.CodeListResults{
+		Items: []models.CodeList{
+			{
+				ID: "code-list-1",
+			},
+		},
+	}, nil
+}
+
 func (m *Mock) GetCodeList(ctx context.Context, codeListID string) (*models.CodeList, error) {
 	if err := m.checkForErrors(); err != nil {
 		return nil, err
@@ -37,6 +52,23 @@ func (m *Mock) GetCodeList(ctx context.Context, codeListID string) (*models.Code
 	}, nil
 }
 
+func (m *Mock) GetCodeListDetail(ctx context.Context, codeListID string) (*models.CodeListDetail, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	return models.NewCodeListDetail(codeListID, []models.CodeListNode{
+		{
+			Properties: map[string]interface{}{
+				"name":    "code-list-name",
+				"edition": "edition-1",
+				"label":   "edition-label-1",
+			},
+			CodeCount: 1,
+		},
+	}), nil
+}
+
 func (m *Mock) GetEditions(ctx context.Context, codeListID string) (*models.Editions, error) {
 	if err := m.checkForErrors(); err != nil {
 		return nil, err
@@ -75,7 +107,7 @@ func (m *Mock) CountCodes(ctx context.Context, codeListID string, edition string
 	return 1, nil
 }
 
-func (m *Mock) GetCodes(ctx context.Context, codeListID, edition string) (*models.CodeResults, error) {
+func (m *Mock) GetCodes(ctx context.Context, codeListID, edition string, opts ...driver.ReadOption) (*models.CodeResults, error) {
 	if err := m.checkForErrors(); err != nil {
 		return nil, err
 	}
@@ -85,7 +117,7 @@ func (m *Mock) GetCodes(ctx context.Context, codeListID, edition string) (*model
 	}, nil
 }
 
-func (m *Mock) GetCode(ctx context.Context, codeListID, edition string, code string) (*models.Code, error) {
+func (m *Mock) GetCode(ctx context.Context, codeListID, edition string, code string, opts ...driver.ReadOption) (*models.Code, error) {
 	if err := m.checkForErrors(); err != nil {
 		return nil, err
 	}
@@ -100,6 +132,18 @@ func (m *Mock) GetCodesOrder(ctx context.Context, codeListID string, codes []str
 	return nil, nil
 }
 
+func (m *Mock) GetCodesBatch(ctx context.Context, refs []models.CodeRef) (map[models.CodeRef]models.CodeLookup, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	codes := make(map[models.CodeRef]models.CodeLookup, len(refs))
+	for _, ref := range refs {
+		codes[ref] = models.CodeLookup{Label: "test-label"}
+	}
+	return codes, nil
+}
+
 func (m *Mock) GetCodeDatasets(ctx context.Context, codeListID, edition string, code string) (*models.Datasets, error) {
 	if err := m.checkForErrors(); err != nil {
 		return nil, err
@@ -131,3 +175,11 @@ func (m *Mock) GetCodeDatasets(ctx context.Context, codeListID, edition string,
 		},
 	}, nil
 }
+
+func (m *Mock) DiffEditions(ctx context.Context, codeListID, fromEdition, toEdition string) (*models.CodeListDiff, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	return models.NewCodeListDiff(codeListID, fromEdition, toEdition, nil, nil), nil
+}
@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
+)
+
+func (m *Mock) GetDatasetDimensions(ctx context.Context, datasetID, edition string, version int) (*models.DatasetDimensions, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	return &models.DatasetDimensions{
+		Items: []models.DatasetDimension{
+			{
+				CodeListID: "code-list-1",
+				Edition:    "edition-1",
+				CodeCount:  2,
+			},
+		},
+	}, nil
+}
+
+func (m *Mock) GetDatasetCodes(ctx context.Context, datasetID, edition string, version int, codeListID string) (*models.CodeResults, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	return &models.CodeResults{
+		Items: []models.Code{
+			{
+				ID:    "code-1",
+				Code:  "code-1",
+				Label: "first code",
+			},
+			{
+				ID:    "code-2",
+				Code:  "code-2",
+				Label: "second code",
+			},
+		},
+	}, nil
+}
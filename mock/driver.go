@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
 )
 
@@ -25,6 +26,21 @@ func (m *Mock) Checker(ctx context.Context, check *health.CheckState) error {
 	return nil
 }
 
+func (m *Mock) Stats() driver.PoolStats {
+	return driver.PoolStats{}
+}
+
+// Capabilities returns the capabilities of all the functions stubbed by the mock
+func (m *Mock) Capabilities() driver.Capabilities {
+	return driver.Capabilities{
+		driver.CapabilityOrderedCodes,
+		driver.CapabilityCodeCounts,
+		driver.CapabilitySparseHierarchy,
+		driver.CapabilityStreaming,
+		driver.CapabilityBulkLoad,
+	}
+}
+
 func (m *Mock) checkForErrors() error {
 	if !m.IsBackendReachable {
 		return errors.New("database unavailable - 500")
@@ -3,6 +3,7 @@ package mock
 import (
 	"context"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
 )
 
@@ -78,6 +79,10 @@ func (m *Mock) SetHasData(ctx context.Context, attempt int, instanceID, dimensio
 	return m.checkForErrors()
 }
 
+func (m *Mock) SetRollupCounts(ctx context.Context, attempt int, instanceID, dimensionName string) error {
+	return m.checkForErrors()
+}
+
 func (m *Mock) MarkNodesToRemain(ctx context.Context, attempt int, instanceID, dimensionName string) error {
 	return m.checkForErrors()
 }
@@ -94,7 +99,7 @@ func (m *Mock) GetHierarchyCodelist(ctx context.Context, instanceID, dimension s
 	return "codelistID", m.checkForErrors()
 }
 
-func (m *Mock) GetHierarchyRoot(ctx context.Context, instanceID, dimension string) (*models.HierarchyResponse, error) {
+func (m *Mock) GetHierarchyRoot(ctx context.Context, instanceID, dimension string, opts ...driver.ReadOption) (*models.HierarchyResponse, error) {
 	if err := m.checkForErrors(); err != nil {
 		return nil, err
 	}
@@ -113,7 +118,22 @@ func (m *Mock) GetHierarchyRoot(ctx context.Context, instanceID, dimension strin
 	}, nil
 }
 
-func (m *Mock) GetHierarchyElement(ctx context.Context, instanceID, dimension, code string) (*models.HierarchyResponse, error) {
+func (m *Mock) GetHierarchyRoots(ctx context.Context, instanceID, dimension string, opts ...driver.ReadOption) ([]*models.HierarchyElement, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	return []*models.HierarchyElement{
+		{
+			Label:        "h-lay-bull",
+			ID:           "h-eye-dee",
+			NoOfChildren: 1,
+			HasData:      true,
+		},
+	}, nil
+}
+
+func (m *Mock) GetHierarchyElement(ctx context.Context, instanceID, dimension, code string, opts ...driver.ReadOption) (*models.HierarchyResponse, error) {
 	if err := m.checkForErrors(); err != nil {
 		return nil, err
 	}
@@ -131,3 +151,60 @@ func (m *Mock) GetHierarchyElement(ctx context.Context, instanceID, dimension, c
 		},
 	}, nil
 }
+
+func (m *Mock) SearchHierarchy(ctx context.Context, instanceID, dimension, text string, limit int, opts ...driver.ReadOption) ([]*models.HierarchyElement, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	return []*models.HierarchyElement{
+		{
+			ID:           "h-eye-dee",
+			Label:        text,
+			NoOfChildren: 1,
+			HasData:      true,
+			Breadcrumbs: []*models.HierarchyElement{
+				{
+					Label:        "parent1",
+					NoOfChildren: 1,
+				},
+			},
+		},
+	}, nil
+}
+
+func (m *Mock) CheckGenericHierarchy(ctx context.Context, codeListID, edition string) (*models.HierarchyIntegrityReport, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	report := models.NewHierarchyIntegrityReport(nil, nil, nil)
+	report.CodeListID = codeListID
+	report.CodeListEdition = edition
+	return report, nil
+}
+
+func (m *Mock) CheckInstanceHierarchy(ctx context.Context, instanceID, dimension, edition string) (*models.HierarchyIntegrityReport, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	report := models.NewHierarchyIntegrityReport(nil, nil, nil)
+	report.CodeListID = "codelistID"
+	report.CodeListEdition = edition
+	report.InstanceID = instanceID
+	report.Dimension = dimension
+	return report, nil
+}
+
+func (m *Mock) SyncInstanceHierarchy(ctx context.Context, instanceID, dimension string) (*models.HierarchySyncReport, error) {
+	if err := m.checkForErrors(); err != nil {
+		return nil, err
+	}
+
+	report := models.NewHierarchySyncPlan(nil, nil).Report()
+	report.CodeListID = "codelistID"
+	report.InstanceID = instanceID
+	report.Dimension = dimension
+	return report, nil
+}
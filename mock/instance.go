@@ -2,6 +2,8 @@ package mock
 
 import (
 	"context"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
 )
 
 func (m *Mock) CountInsertedObservations(ctx context.Context, instanceID string) (count int64, err error) {
@@ -16,6 +18,10 @@ func (m *Mock) SetInstanceIsPublished(ctx context.Context, instanceID string) er
 	return m.checkForErrors()
 }
 
+func (m *Mock) SetInstancePublishState(ctx context.Context, instanceID string, state models.PublishState) error {
+	return m.checkForErrors()
+}
+
 func (m *Mock) CreateInstanceConstraint(ctx context.Context, instanceID string) error {
 	return m.checkForErrors()
 }
@@ -35,3 +41,15 @@ func (m *Mock) CreateCodeRelationship(ctx context.Context, instanceID, codeListI
 func (m *Mock) InstanceExists(ctx context.Context, instanceID string) (bool, error) {
 	return true, m.checkForErrors()
 }
+
+func (m *Mock) GetInstance(ctx context.Context, instanceID string) (*models.Instance, error) {
+	return &models.Instance{InstanceID: instanceID}, m.checkForErrors()
+}
+
+func (m *Mock) ListInstances(ctx context.Context, filter *models.InstanceFilter) ([]*models.Instance, error) {
+	return []*models.Instance{}, m.checkForErrors()
+}
+
+func (m *Mock) ValidateInstance(ctx context.Context, instanceID string) (*models.InstanceValidationReport, error) {
+	return &models.InstanceValidationReport{InstanceID: instanceID}, m.checkForErrors()
+}
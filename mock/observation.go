@@ -14,3 +14,11 @@ func (m *Mock) StreamCSVRows(ctx context.Context, instanceID, filterID string, f
 func (m *Mock) InsertObservationBatch(ctx context.Context, attempt int, instanceID string, observations []*models.Observation, dimensionIDs map[string]string) error {
 	return m.checkForErrors()
 }
+
+func (m *Mock) RecordImportCheckpoint(ctx context.Context, instanceID string, checkpoint *models.ImportCheckpoint) error {
+	return m.checkForErrors()
+}
+
+func (m *Mock) GetImportProgress(ctx context.Context, instanceID string) (*models.ImportProgress, error) {
+	return &models.ImportProgress{InstanceID: instanceID}, m.checkForErrors()
+}
@@ -0,0 +1,9 @@
+package mock
+
+import "github.com/ONSdigital/dp-graph/v2/graph/driver"
+
+func init() {
+	driver.Register("mock", func(cfg driver.Config, errs chan error) (driver.Driver, error) {
+		return &Mock{}, nil
+	})
+}
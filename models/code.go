@@ -10,4 +10,19 @@ type Code struct {
 	ID    string
 	Code  string
 	Label string
+	// Order is the position of the code in the code list edition, nil if the edition defines no order for it
+	Order *int
+}
+
+// CodeRef identifies a code in an edition of a code list
+type CodeRef struct {
+	CodeListID string
+	Edition    string
+	Code       string
+}
+
+// CodeLookup holds the label and order of the 'usedBy' relationship between a code and an edition of a code list
+type CodeLookup struct {
+	Label string
+	Order *int
 }
@@ -1,5 +1,7 @@
 package models
 
+import "sort"
+
 // CodeListResults contains an array of code lists
 type CodeListResults struct {
 	Items []CodeList
@@ -9,3 +11,92 @@ type CodeListResults struct {
 type CodeList struct {
 	ID string
 }
+
+// CodeListDetail contains the metadata held by the nodes of a code list, one for each of its editions
+type CodeListDetail struct {
+	ID   string
+	Name string
+	// Flags holds the boolean type flags of the code list, e.g. "geography"
+	Flags    map[string]bool
+	Editions []EditionDetail
+	// Properties holds any other properties of the code list nodes
+	Properties map[string]interface{}
+}
+
+// EditionDetail contains the metadata of a single edition of a code list
+type EditionDetail struct {
+	ID          string
+	Label       string
+	ReleaseDate string
+	CodeCount   int64
+}
+
+// properties of a code list node which are not returned in CodeListDetail.Properties
+var codeListNodeProperties = map[string]bool{
+	"listID":       true,
+	"name":         true,
+	"edition":      true,
+	"label":        true,
+	"release_date": true,
+}
+
+// CodeListNode holds the properties of the node of a code list edition, and the number of codes it uses
+type CodeListNode struct {
+	Properties map[string]interface{}
+	CodeCount  int64
+}
+
+// NewCodeListDetail creates the detail of a code list from the nodes of its editions. Boolean properties
+// are type flags. The name, flags and other properties of the code list are merged from all its editions
+// in edition order, so values from later editions take precedence.
+func NewCodeListDetail(codeListID string, nodes []CodeListNode) *CodeListDetail {
+	d := &CodeListDetail{
+		ID:         codeListID,
+		Flags:      map[string]bool{},
+		Editions:   []EditionDetail{},
+		Properties: map[string]interface{}{},
+	}
+
+	sorted := make([]CodeListNode, len(nodes))
+	copy(sorted, nodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return editionID(sorted[i]) < editionID(sorted[j])
+	})
+
+	for _, node := range sorted {
+		d.Editions = append(d.Editions, EditionDetail{
+			ID:          editionID(node),
+			Label:       stringProperty(node, "label"),
+			ReleaseDate: stringProperty(node, "release_date"),
+			CodeCount:   node.CodeCount,
+		})
+		d.merge(node.Properties)
+	}
+	return d
+}
+
+func editionID(node CodeListNode) string {
+	return stringProperty(node, "edition")
+}
+
+func stringProperty(node CodeListNode, key string) string {
+	value, _ := node.Properties[key].(string)
+	return value
+}
+
+func (d *CodeListDetail) merge(properties map[string]interface{}) {
+	if name, ok := properties["name"].(string); ok {
+		d.Name = name
+	}
+
+	for key, value := range properties {
+		if codeListNodeProperties[key] {
+			continue
+		}
+		if flag, ok := value.(bool); ok {
+			d.Flags[key] = flag
+			continue
+		}
+		d.Properties[key] = value
+	}
+}
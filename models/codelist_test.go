@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewCodeListDetail(t *testing.T) {
+	Convey("Given the nodes of the editions of a code list, in any order", t, func() {
+		nodes := []CodeListNode{
+			{
+				Properties: map[string]interface{}{
+					"listID":       "mmm",
+					"edition":      "2020",
+					"label":        "Months 2020",
+					"name":         "Months",
+					"release_date": "2020-01-01",
+					"geography":    false,
+					"source":       "ONS",
+				},
+				CodeCount: 12,
+			},
+			{
+				Properties: map[string]interface{}{
+					"listID":    "mmm",
+					"edition":   "2019",
+					"label":     "Months 2019",
+					"name":      "Old months",
+					"geography": true,
+					"legacy":    true,
+					"version":   int64(3),
+				},
+				CodeCount: 11,
+			},
+		}
+
+		Convey("When NewCodeListDetail is called", func() {
+			d := NewCodeListDetail("mmm", nodes)
+
+			Convey("Then the editions are returned in order", func() {
+				So(d.ID, ShouldEqual, "mmm")
+				So(d.Editions, ShouldResemble, []EditionDetail{
+					{ID: "2019", Label: "Months 2019", CodeCount: 11},
+					{ID: "2020", Label: "Months 2020", ReleaseDate: "2020-01-01", CodeCount: 12},
+				})
+			})
+
+			Convey("Then the metadata of later editions takes precedence", func() {
+				So(d.Name, ShouldEqual, "Months")
+				So(d.Flags, ShouldResemble, map[string]bool{"geography": false, "legacy": true})
+				So(d.Properties, ShouldResemble, map[string]interface{}{"source": "ONS", "version": int64(3)})
+			})
+		})
+	})
+
+	Convey("Given no nodes", t, func() {
+		Convey("When NewCodeListDetail is called", func() {
+			d := NewCodeListDetail("mmm", nil)
+
+			Convey("Then empty metadata is returned", func() {
+				So(d.Editions, ShouldBeEmpty)
+				So(d.Flags, ShouldBeEmpty)
+				So(d.Properties, ShouldBeEmpty)
+			})
+		})
+	})
+}
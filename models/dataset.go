@@ -18,3 +18,16 @@ type DatasetEdition struct {
 	CodeListID    string
 	LatestVersion int
 }
+
+// DatasetDimensions represents the code lists used by a version of a dataset
+type DatasetDimensions struct {
+	Items []DatasetDimension
+}
+
+// DatasetDimension represents an edition of a code list used by a version of a dataset,
+// and the number of its codes that are used by the dataset
+type DatasetDimension struct {
+	CodeListID string
+	Edition    string
+	CodeCount  int64
+}
@@ -0,0 +1,111 @@
+package models
+
+import "sort"
+
+// CodeListEntry represents a code used by an edition of a code list, with the label
+// and order of its 'usedBy' relationship to the code list
+type CodeListEntry struct {
+	Code  string
+	Label string
+	Order *int
+}
+
+// CodeChange represents a code used by two editions of a code list whose label or order differs
+type CodeChange struct {
+	Code      string
+	FromLabel string
+	ToLabel   string
+	FromOrder *int
+	ToOrder   *int
+}
+
+// CodeListDiff represents the changes to the codes of a code list from one edition to another.
+// All lists are ordered by code.
+type CodeListDiff struct {
+	CodeListID  string
+	FromEdition string
+	ToEdition   string
+	Added       []CodeListEntry
+	Removed     []CodeListEntry
+	Relabelled  []CodeChange
+	Reordered   []CodeChange
+}
+
+// NewCodeListDiff compares the codes used by two editions of a code list
+func NewCodeListDiff(codeListID, fromEdition, toEdition string, from, to []CodeListEntry) *CodeListDiff {
+	diff := &CodeListDiff{
+		CodeListID:  codeListID,
+		FromEdition: fromEdition,
+		ToEdition:   toEdition,
+		Added:       []CodeListEntry{},
+		Removed:     []CodeListEntry{},
+		Relabelled:  []CodeChange{},
+		Reordered:   []CodeChange{},
+	}
+
+	fromCodes := make(map[string]CodeListEntry, len(from))
+	for _, entry := range from {
+		fromCodes[entry.Code] = entry
+	}
+
+	toCodes := make(map[string]bool, len(to))
+	for _, entry := range to {
+		toCodes[entry.Code] = true
+
+		previous, ok := fromCodes[entry.Code]
+		if !ok {
+			diff.Added = append(diff.Added, entry)
+			continue
+		}
+
+		change := CodeChange{
+			Code:      entry.Code,
+			FromLabel: previous.Label,
+			ToLabel:   entry.Label,
+			FromOrder: previous.Order,
+			ToOrder:   entry.Order,
+		}
+		if previous.Label != entry.Label {
+			diff.Relabelled = append(diff.Relabelled, change)
+		}
+		if !equalOrder(previous.Order, entry.Order) {
+			diff.Reordered = append(diff.Reordered, change)
+		}
+	}
+
+	for _, entry := range from {
+		if !toCodes[entry.Code] {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+
+	sortEntries(diff.Added)
+	sortEntries(diff.Removed)
+	sortChanges(diff.Relabelled)
+	sortChanges(diff.Reordered)
+	return diff
+}
+
+// IsEmpty returns true if the two editions use the same codes with the same labels and order
+func (d *CodeListDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Relabelled) == 0 && len(d.Reordered) == 0
+}
+
+func equalOrder(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sortEntries(entries []CodeListEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+}
+
+func sortChanges(changes []CodeChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Code < changes[j].Code
+	})
+}
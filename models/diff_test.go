@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewCodeListDiff(t *testing.T) {
+	one, two, three := 1, 2, 3
+
+	Convey("Given two editions of a code list", t, func() {
+		from := []CodeListEntry{
+			{Code: "mar", Label: "March", Order: &three},
+			{Code: "jan", Label: "January", Order: &one},
+			{Code: "feb", Label: "Feb", Order: &two},
+			{Code: "dec", Label: "December"},
+		}
+		to := []CodeListEntry{
+			{Code: "jan", Label: "January", Order: &one},
+			{Code: "feb", Label: "February", Order: &two},
+			{Code: "apr", Label: "April", Order: &three},
+			{Code: "mar", Label: "March"},
+		}
+
+		Convey("When NewCodeListDiff is called", func() {
+			diff := NewCodeListDiff("mmm", "2019", "2020", from, to)
+
+			Convey("Then the editions are recorded", func() {
+				So(diff.CodeListID, ShouldEqual, "mmm")
+				So(diff.FromEdition, ShouldEqual, "2019")
+				So(diff.ToEdition, ShouldEqual, "2020")
+				So(diff.IsEmpty(), ShouldBeFalse)
+			})
+
+			Convey("Then added and removed codes are returned", func() {
+				So(diff.Added, ShouldResemble, []CodeListEntry{{Code: "apr", Label: "April", Order: &three}})
+				So(diff.Removed, ShouldResemble, []CodeListEntry{{Code: "dec", Label: "December"}})
+			})
+
+			Convey("Then relabelled codes are returned", func() {
+				So(diff.Relabelled, ShouldResemble, []CodeChange{
+					{Code: "feb", FromLabel: "Feb", ToLabel: "February", FromOrder: &two, ToOrder: &two},
+				})
+			})
+
+			Convey("Then codes whose order was changed or removed are returned", func() {
+				So(diff.Reordered, ShouldResemble, []CodeChange{
+					{Code: "mar", FromLabel: "March", ToLabel: "March", FromOrder: &three},
+				})
+			})
+		})
+	})
+
+	Convey("Given two identical editions of a code list", t, func() {
+		entries := []CodeListEntry{{Code: "jan", Label: "January", Order: &one}, {Code: "dec", Label: "December"}}
+
+		Convey("When NewCodeListDiff is called", func() {
+			diff := NewCodeListDiff("mmm", "2019", "2020", entries, entries)
+
+			Convey("Then the diff is empty", func() {
+				So(diff.IsEmpty(), ShouldBeTrue)
+				So(diff.Added, ShouldBeEmpty)
+				So(diff.Removed, ShouldBeEmpty)
+			})
+		})
+	})
+}
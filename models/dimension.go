@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"fmt"
 )
 
 // Dimension struct encapsulating Dimension details.
@@ -30,9 +31,22 @@ func (d *Dimension) Validate() error {
 
 // Instance struct to hold instance information.
 type Instance struct {
-	InstanceID string
-	CSVHeader  []string
-	Dimensions []interface{}
+	InstanceID   string
+	CSVHeader    []string
+	Dimensions   []interface{}
+	DatasetID    string
+	Edition      string
+	Version      int
+	IsPublished  bool
+	PublishState PublishState
+}
+
+// InstanceFilter restricts the instances returned when listing instances. Empty
+// fields are not filtered on.
+type InstanceFilter struct {
+	DatasetID   string
+	Edition     string
+	IsPublished *bool
 }
 
 // Validate checks that the instance ID is not empty
@@ -46,3 +60,30 @@ func (i *Instance) Validate() error {
 	}
 	return nil
 }
+
+// PublishState is the publication state of an instance
+type PublishState string
+
+// Possible publication states of an instance. An instance that has never been published has no state.
+const (
+	// PublishStatePublished is the state of the current release of a dataset edition
+	PublishStatePublished PublishState = "published"
+	// PublishStateUnpublished is the state of a release that has been withdrawn. Its observations can no longer be read.
+	PublishStateUnpublished PublishState = "unpublished"
+	// PublishStateSuperseded is the state of a release that remains published but is no longer the latest version
+	PublishStateSuperseded PublishState = "superseded"
+)
+
+// Validate checks the publish state is one of the known states
+func (s PublishState) Validate() error {
+	switch s {
+	case PublishStatePublished, PublishStateUnpublished, PublishStateSuperseded:
+		return nil
+	}
+	return fmt.Errorf("invalid publish state %q", string(s))
+}
+
+// IsPublished returns true if instances in this state are publicly available
+func (s PublishState) IsPublished() bool {
+	return s == PublishStatePublished || s == PublishStateSuperseded
+}
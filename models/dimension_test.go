@@ -82,3 +82,31 @@ func TestDimension_Validate(t *testing.T) {
 		})
 	})
 }
+
+func TestPublishState(t *testing.T) {
+	Convey("Given the known publish states", t, func() {
+		Convey("Then they are valid", func() {
+			So(PublishStatePublished.Validate(), ShouldBeNil)
+			So(PublishStateUnpublished.Validate(), ShouldBeNil)
+			So(PublishStateSuperseded.Validate(), ShouldBeNil)
+		})
+
+		Convey("Then only published and superseded instances are publicly available", func() {
+			So(PublishStatePublished.IsPublished(), ShouldBeTrue)
+			So(PublishStateSuperseded.IsPublished(), ShouldBeTrue)
+			So(PublishStateUnpublished.IsPublished(), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an unknown publish state", t, func() {
+		s := PublishState("withdrawn")
+
+		Convey("When Validate is invoked", func() {
+			err := s.Validate()
+
+			Convey("Then the expected error is returned", func() {
+				So(err.Error(), ShouldEqual, `invalid publish state "withdrawn"`)
+			})
+		})
+	})
+}
@@ -2,20 +2,25 @@ package models
 
 // HierarchyResponse models a node in the hierarchy
 type HierarchyResponse struct {
-	ID           string
-	Label        string
-	Children     []*HierarchyElement
-	NoOfChildren int64
-	Order        *int64 // nil if order property not present
-	HasData      bool
-	Breadcrumbs  []*HierarchyElement
+	ID               string
+	Label            string
+	Children         []*HierarchyElement
+	NoOfChildren     int64
+	Order            *int64 // nil if order property not present
+	HasData          bool
+	NoOfOptions      *int64 // nil if rollup counts not set, includes descendants
+	NoOfObservations *int64 // nil if rollup counts not set, includes descendants
+	Breadcrumbs      []*HierarchyElement
 }
 
 // HierarchyElement is a item in a list within a HierarchyResponse
 type HierarchyElement struct {
-	ID           string
-	Label        string
-	NoOfChildren int64
-	Order        *int64 // nil if order property not present
-	HasData      bool
+	ID               string
+	Label            string
+	NoOfChildren     int64
+	Order            *int64 // nil if order property not present
+	HasData          bool
+	NoOfOptions      *int64              // nil if rollup counts not set, includes descendants
+	NoOfObservations *int64              // nil if rollup counts not set, includes descendants
+	Breadcrumbs      []*HierarchyElement // only populated by SearchHierarchy
 }
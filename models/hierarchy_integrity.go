@@ -0,0 +1,205 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HierarchyIntegrityReport describes the consistency of a generic or instance hierarchy
+type HierarchyIntegrityReport struct {
+	CodeListID      string `json:"code_list_id"`
+	CodeListEdition string `json:"code_list_edition"`
+	InstanceID      string `json:"instance_id,omitempty"`
+	Dimension       string `json:"dimension,omitempty"`
+	// Cycles lists the codes of the nodes that are their own ancestor
+	Cycles []string `json:"cycles"`
+	// MultipleParents lists the codes of the nodes that have more than one parent
+	MultipleParents []string `json:"multiple_parents"`
+	// Orphans lists the codes of the nodes that have neither a parent nor children
+	Orphans []string `json:"orphans"`
+	// MissingCodes lists the codes of the nodes that are not codes of the code list
+	MissingCodes []string `json:"missing_codes"`
+	// ChildCountMismatches lists the codes of the nodes whose numberOfChildren property
+	// does not match their number of children
+	ChildCountMismatches []string `json:"child_count_mismatches"`
+}
+
+// HierarchyIntegrityNode is a hierarchy node as needed to check the integrity of a hierarchy
+type HierarchyIntegrityNode struct {
+	Code             string
+	NumberOfChildren *int64 // nil if numberOfChildren property not present
+}
+
+// HierarchyEdge is a 'hasParent' relationship between two hierarchy nodes, identified by their codes
+type HierarchyEdge struct {
+	Code       string
+	ParentCode string
+}
+
+// HierarchyIntegrityReader reads the hierarchy nodes with the provided label, the 'hasParent' edges between them
+// and the codes of the provided code list edition
+type HierarchyIntegrityReader func(nodeLabel, codeListID, edition string) (nodes []HierarchyIntegrityNode, edges []HierarchyEdge, codeListCodes []string, err error)
+
+// CheckGenericHierarchy checks the integrity of the generic hierarchy of a code list, read with the provided reader,
+// against the codes of the provided edition of the code list
+func CheckGenericHierarchy(read HierarchyIntegrityReader, codeListID, edition string) (*HierarchyIntegrityReport, error) {
+	nodes, edges, codes, err := read(fmt.Sprintf("_generic_hierarchy_node_%s", codeListID), codeListID, edition)
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewHierarchyIntegrityReport(nodes, edges, codes)
+	r.CodeListID = codeListID
+	r.CodeListEdition = edition
+	return r, nil
+}
+
+// CheckInstanceHierarchy checks the integrity of the hierarchy of an instance dimension, read with the provided reader,
+// against the codes of the provided edition of its code list
+func CheckInstanceHierarchy(read HierarchyIntegrityReader, instanceID, dimension, codeListID, edition string) (*HierarchyIntegrityReport, error) {
+	nodes, edges, codes, err := read(fmt.Sprintf("_hierarchy_node_%s_%s", instanceID, dimension), codeListID, edition)
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewHierarchyIntegrityReport(nodes, edges, codes)
+	r.CodeListID = codeListID
+	r.CodeListEdition = edition
+	r.InstanceID = instanceID
+	r.Dimension = dimension
+	return r, nil
+}
+
+// NewHierarchyIntegrityReport checks the integrity of a hierarchy made of the provided nodes and edges,
+// against the codes of its code list. The identifiers of the hierarchy are left for the caller to set.
+func NewHierarchyIntegrityReport(nodes []HierarchyIntegrityNode, edges []HierarchyEdge, codeListCodes []string) *HierarchyIntegrityReport {
+	r := &HierarchyIntegrityReport{
+		Cycles:               []string{},
+		MultipleParents:      []string{},
+		Orphans:              []string{},
+		MissingCodes:         []string{},
+		ChildCountMismatches: []string{},
+	}
+
+	parents := make(map[string][]string)
+	children := make(map[string]int64)
+	for _, e := range edges {
+		parents[e.Code] = append(parents[e.Code], e.ParentCode)
+		children[e.ParentCode]++
+	}
+
+	codes := make(map[string]bool, len(codeListCodes))
+	for _, c := range codeListCodes {
+		codes[c] = true
+	}
+
+	for _, n := range nodes {
+		if len(parents[n.Code]) > 1 {
+			r.MultipleParents = append(r.MultipleParents, n.Code)
+		}
+		if len(nodes) > 1 && len(parents[n.Code]) == 0 && children[n.Code] == 0 {
+			r.Orphans = append(r.Orphans, n.Code)
+		}
+		if !codes[n.Code] {
+			r.MissingCodes = append(r.MissingCodes, n.Code)
+		}
+		if n.NumberOfChildren != nil && *n.NumberOfChildren != children[n.Code] {
+			r.ChildCountMismatches = append(r.ChildCountMismatches, n.Code)
+		}
+	}
+
+	r.Cycles = findCycles(parents)
+
+	sort.Strings(r.MultipleParents)
+	sort.Strings(r.Orphans)
+	sort.Strings(r.MissingCodes)
+	sort.Strings(r.ChildCountMismatches)
+	return r
+}
+
+// IsValid returns true if no inconsistencies were found in the hierarchy
+func (r *HierarchyIntegrityReport) IsValid() bool {
+	return len(r.Cycles) == 0 &&
+		len(r.MultipleParents) == 0 &&
+		len(r.Orphans) == 0 &&
+		len(r.MissingCodes) == 0 &&
+		len(r.ChildCountMismatches) == 0
+}
+
+// findCycles returns the sorted codes of the nodes that are part of a cycle of the provided parent
+// relationships, as the strongly connected components of more than one node, or with a self loop
+// (Tarjan's algorithm)
+func findCycles(parents map[string][]string) []string {
+	var (
+		index   = 0
+		indexes = make(map[string]int)
+		lowLink = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   []string
+		cycles  = []string{}
+	)
+
+	var connect func(code string)
+	connect = func(code string) {
+		indexes[code] = index
+		lowLink[code] = index
+		index++
+		stack = append(stack, code)
+		onStack[code] = true
+
+		for _, parent := range parents[code] {
+			if _, visited := indexes[parent]; !visited {
+				connect(parent)
+				if lowLink[parent] < lowLink[code] {
+					lowLink[code] = lowLink[parent]
+				}
+			} else if onStack[parent] && indexes[parent] < lowLink[code] {
+				lowLink[code] = indexes[parent]
+			}
+		}
+
+		if lowLink[code] != indexes[code] {
+			return
+		}
+
+		// code is the root of a strongly connected component, which is popped from the stack
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == code {
+				break
+			}
+		}
+		if len(component) > 1 || isSelfLoop(code, parents[code]) {
+			cycles = append(cycles, component...)
+		}
+	}
+
+	// iterate in a deterministic order
+	codes := make([]string, 0, len(parents))
+	for code := range parents {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if _, visited := indexes[code]; !visited {
+			connect(code)
+		}
+	}
+
+	sort.Strings(cycles)
+	return cycles
+}
+
+func isSelfLoop(code string, parents []string) bool {
+	for _, parent := range parents {
+		if parent == code {
+			return true
+		}
+	}
+	return false
+}
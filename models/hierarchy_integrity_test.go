@@ -0,0 +1,114 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewHierarchyIntegrityReport(t *testing.T) {
+	two := int64(2)
+	three := int64(3)
+
+	Convey("Given a consistent hierarchy", t, func() {
+		nodes := []HierarchyIntegrityNode{
+			{Code: "K04000001", NumberOfChildren: &two},
+			{Code: "E92000001"},
+			{Code: "W92000004"},
+		}
+		edges := []HierarchyEdge{
+			{Code: "E92000001", ParentCode: "K04000001"},
+			{Code: "W92000004", ParentCode: "K04000001"},
+		}
+
+		Convey("When NewHierarchyIntegrityReport is called", func() {
+			r := NewHierarchyIntegrityReport(nodes, edges, []string{"K04000001", "E92000001", "W92000004"})
+
+			Convey("Then the report is valid", func() {
+				So(r.IsValid(), ShouldBeTrue)
+				So(r.Cycles, ShouldBeEmpty)
+				So(r.MultipleParents, ShouldBeEmpty)
+				So(r.Orphans, ShouldBeEmpty)
+				So(r.MissingCodes, ShouldBeEmpty)
+				So(r.ChildCountMismatches, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an inconsistent hierarchy", t, func() {
+		nodes := []HierarchyIntegrityNode{
+			{Code: "root", NumberOfChildren: &three},
+			{Code: "a"},
+			{Code: "b"},
+			{Code: "c"},
+			{Code: "d"},
+			{Code: "self"},
+			{Code: "orphan"},
+		}
+		edges := []HierarchyEdge{
+			{Code: "a", ParentCode: "root"},
+			// a -> b -> c -> a is a cycle hanging from root
+			{Code: "b", ParentCode: "a"},
+			{Code: "c", ParentCode: "b"},
+			{Code: "a", ParentCode: "c"},
+			// d has two parents
+			{Code: "d", ParentCode: "root"},
+			{Code: "d", ParentCode: "b"},
+			{Code: "self", ParentCode: "self"},
+		}
+
+		Convey("When NewHierarchyIntegrityReport is called", func() {
+			r := NewHierarchyIntegrityReport(nodes, edges, []string{"root", "a", "b", "c", "d", "self"})
+
+			Convey("Then the inconsistencies are reported", func() {
+				So(r.IsValid(), ShouldBeFalse)
+				So(r.Cycles, ShouldResemble, []string{"a", "b", "c", "self"})
+				So(r.MultipleParents, ShouldResemble, []string{"a", "d"})
+				So(r.Orphans, ShouldResemble, []string{"orphan"})
+				So(r.MissingCodes, ShouldResemble, []string{"orphan"})
+				So(r.ChildCountMismatches, ShouldResemble, []string{"root"})
+			})
+		})
+	})
+
+	Convey("Given a hierarchy with a single node", t, func() {
+		nodes := []HierarchyIntegrityNode{{Code: "root"}}
+
+		Convey("When NewHierarchyIntegrityReport is called", func() {
+			r := NewHierarchyIntegrityReport(nodes, nil, []string{"root"})
+
+			Convey("Then the node is not reported as an orphan", func() {
+				So(r.IsValid(), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestCheckInstanceHierarchy(t *testing.T) {
+	Convey("Given a reader that returns an instance hierarchy", t, func() {
+		var readLabel, readCodeListID, readEdition string
+		read := func(nodeLabel, codeListID, edition string) ([]HierarchyIntegrityNode, []HierarchyEdge, []string, error) {
+			readLabel, readCodeListID, readEdition = nodeLabel, codeListID, edition
+			return []HierarchyIntegrityNode{{Code: "root"}}, nil, []string{}, nil
+		}
+
+		Convey("When CheckInstanceHierarchy is called", func() {
+			r, err := CheckInstanceHierarchy(read, "123", "geography", "mmm", "one-off")
+
+			Convey("Then the hierarchy is read for the code list edition", func() {
+				So(err, ShouldBeNil)
+				So(readLabel, ShouldEqual, "_hierarchy_node_123_geography")
+				So(readCodeListID, ShouldEqual, "mmm")
+				So(readEdition, ShouldEqual, "one-off")
+			})
+
+			Convey("Then the report identifies the hierarchy", func() {
+				So(r.CodeListID, ShouldEqual, "mmm")
+				So(r.CodeListEdition, ShouldEqual, "one-off")
+				So(r.InstanceID, ShouldEqual, "123")
+				So(r.Dimension, ShouldEqual, "geography")
+				So(r.MissingCodes, ShouldResemble, []string{"root"})
+			})
+		})
+	})
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// HierarchySyncNode is a hierarchy node as needed to synchronise an instance hierarchy with its generic hierarchy
+type HierarchySyncNode struct {
+	Code string
+	// Labels holds the label properties of the node, keyed by property name ('label', 'label_cy', ...)
+	Labels      map[string]string
+	ParentCodes []string
+	Order       *int64 // nil if order not present
+}
+
+// HierarchySyncPlan holds the changes needed to bring an instance hierarchy in line with its generic hierarchy.
+// Each node holds the code of the instance hierarchy node to change, and the values it should be changed to.
+type HierarchySyncPlan struct {
+	// Relabel holds the nodes with label properties to set
+	Relabel []HierarchySyncNode
+	// Reparent holds the nodes whose 'hasParent' edges are to be replaced by edges to the parent codes
+	Reparent []HierarchySyncNode
+	// Reorder holds the nodes with an order to set, or remove if nil
+	Reorder []HierarchySyncNode
+	// Unresolved lists the codes of the instance hierarchy nodes that cannot be synchronised, as they are not
+	// in the generic hierarchy, or their new parents are not in the instance hierarchy
+	Unresolved []string
+}
+
+// HierarchySyncReport describes the changes applied to an instance hierarchy by a synchronisation with its generic hierarchy
+type HierarchySyncReport struct {
+	CodeListID string   `json:"code_list_id"`
+	InstanceID string   `json:"instance_id"`
+	Dimension  string   `json:"dimension"`
+	Relabelled []string `json:"relabelled"`
+	Reparented []string `json:"reparented"`
+	Reordered  []string `json:"reordered"`
+	Unresolved []string `json:"unresolved"`
+}
+
+// NewHierarchySyncPlan compares the nodes of an instance hierarchy with the nodes of the generic hierarchy
+// it was cloned from, by code. Nodes of the generic hierarchy that are not in the instance hierarchy are
+// ignored, as instance hierarchies only keep the nodes with data and their ancestors. Labels that are only
+// present on the instance hierarchy nodes are left in place.
+func NewHierarchySyncPlan(generic, instance []HierarchySyncNode) *HierarchySyncPlan {
+	p := &HierarchySyncPlan{
+		Relabel:    []HierarchySyncNode{},
+		Reparent:   []HierarchySyncNode{},
+		Reorder:    []HierarchySyncNode{},
+		Unresolved: []string{},
+	}
+
+	genericNodes := make(map[string]HierarchySyncNode, len(generic))
+	for _, node := range generic {
+		genericNodes[node.Code] = node
+	}
+
+	instanceCodes := make(map[string]bool, len(instance))
+	for _, node := range instance {
+		instanceCodes[node.Code] = true
+	}
+
+	for _, node := range instance {
+		source, ok := genericNodes[node.Code]
+		if !ok {
+			p.Unresolved = append(p.Unresolved, node.Code)
+			continue
+		}
+
+		if labels := changedLabels(source.Labels, node.Labels); len(labels) > 0 {
+			p.Relabel = append(p.Relabel, HierarchySyncNode{Code: node.Code, Labels: labels})
+		}
+
+		if !sameOrder(source.Order, node.Order) {
+			p.Reorder = append(p.Reorder, HierarchySyncNode{Code: node.Code, Order: source.Order})
+		}
+
+		parents := sortedCopy(source.ParentCodes)
+		if strings.Join(parents, ",") == strings.Join(sortedCopy(node.ParentCodes), ",") {
+			continue
+		}
+		if !allIn(parents, instanceCodes) {
+			p.Unresolved = append(p.Unresolved, node.Code)
+			continue
+		}
+		p.Reparent = append(p.Reparent, HierarchySyncNode{Code: node.Code, ParentCodes: parents})
+	}
+
+	sortSyncNodes(p.Relabel)
+	sortSyncNodes(p.Reparent)
+	sortSyncNodes(p.Reorder)
+	sort.Strings(p.Unresolved)
+	return p
+}
+
+// IsEmpty returns true if the plan holds no changes to apply
+func (p *HierarchySyncPlan) IsEmpty() bool {
+	return len(p.Relabel) == 0 && len(p.Reparent) == 0 && len(p.Reorder) == 0
+}
+
+// Report returns the report of the changes in the plan. The identifiers of the hierarchy are left for the caller to set.
+func (p *HierarchySyncPlan) Report() *HierarchySyncReport {
+	return &HierarchySyncReport{
+		Relabelled: syncNodeCodes(p.Relabel),
+		Reparented: syncNodeCodes(p.Reparent),
+		Reordered:  syncNodeCodes(p.Reorder),
+		Unresolved: append([]string{}, p.Unresolved...),
+	}
+}
+
+// changedLabels returns the labels of the generic hierarchy node that differ from the instance hierarchy node
+func changedLabels(generic, instance map[string]string) map[string]string {
+	changed := make(map[string]string)
+	for key, label := range generic {
+		if current, ok := instance[key]; !ok || current != label {
+			changed[key] = label
+		}
+	}
+	return changed
+}
+
+func sameOrder(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sortedCopy(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func allIn(values []string, set map[string]bool) bool {
+	for _, v := range values {
+		if !set[v] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortSyncNodes(nodes []HierarchySyncNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Code < nodes[j].Code
+	})
+}
+
+func syncNodeCodes(nodes []HierarchySyncNode) []string {
+	codes := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		codes = append(codes, node.Code)
+	}
+	return codes
+}
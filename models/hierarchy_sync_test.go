@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewHierarchySyncPlan(t *testing.T) {
+	one := int64(1)
+	two := int64(2)
+
+	Convey("Given an instance hierarchy in line with its generic hierarchy", t, func() {
+		generic := []HierarchySyncNode{
+			{Code: "root", Labels: map[string]string{"label": "Root"}},
+			{Code: "a", Labels: map[string]string{"label": "A", "label_cy": "A cy"}, ParentCodes: []string{"root"}, Order: &one},
+			{Code: "unused", Labels: map[string]string{"label": "Unused"}, ParentCodes: []string{"root"}},
+		}
+		instance := []HierarchySyncNode{
+			{Code: "root", Labels: map[string]string{"label": "Root"}},
+			{Code: "a", Labels: map[string]string{"label": "A", "label_cy": "A cy"}, ParentCodes: []string{"root"}, Order: &one},
+		}
+
+		Convey("When NewHierarchySyncPlan is called", func() {
+			p := NewHierarchySyncPlan(generic, instance)
+
+			Convey("Then the plan is empty", func() {
+				So(p.IsEmpty(), ShouldBeTrue)
+				So(p.Unresolved, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a generic hierarchy that has been corrected since it was cloned", t, func() {
+		generic := []HierarchySyncNode{
+			{Code: "root", Labels: map[string]string{"label": "Root"}},
+			{Code: "x", Labels: map[string]string{"label": "X"}, ParentCodes: []string{"root"}},
+			{Code: "a", Labels: map[string]string{"label": "A renamed", "label_cy": "A cy"}, ParentCodes: []string{"x"}, Order: &two},
+			{Code: "b", Labels: map[string]string{"label": "B"}, ParentCodes: []string{"root"}},
+			{Code: "c", Labels: map[string]string{"label": "C"}, ParentCodes: []string{"not-cloned"}},
+		}
+		instance := []HierarchySyncNode{
+			{Code: "root", Labels: map[string]string{"label": "Root"}},
+			{Code: "x", Labels: map[string]string{"label": "X"}, ParentCodes: []string{"root"}},
+			{Code: "a", Labels: map[string]string{"label": "A", "label_cy": "A cy"}, ParentCodes: []string{"root"}, Order: &one},
+			{Code: "b", Labels: map[string]string{"label": "B"}, ParentCodes: []string{"root"}, Order: &one},
+			{Code: "c", Labels: map[string]string{"label": "C"}, ParentCodes: []string{"root"}},
+			{Code: "removed", Labels: map[string]string{"label": "Removed"}, ParentCodes: []string{"root"}},
+		}
+
+		Convey("When NewHierarchySyncPlan is called", func() {
+			p := NewHierarchySyncPlan(generic, instance)
+
+			Convey("Then the plan holds the changes to apply", func() {
+				So(p.IsEmpty(), ShouldBeFalse)
+				So(p.Relabel, ShouldResemble, []HierarchySyncNode{
+					{Code: "a", Labels: map[string]string{"label": "A renamed"}},
+				})
+				So(p.Reparent, ShouldResemble, []HierarchySyncNode{
+					{Code: "a", ParentCodes: []string{"x"}},
+				})
+				So(p.Reorder, ShouldResemble, []HierarchySyncNode{
+					{Code: "a", Order: &two},
+					{Code: "b"},
+				})
+				So(p.Unresolved, ShouldResemble, []string{"c", "removed"})
+			})
+
+			Convey("Then the report lists the codes of the changed nodes", func() {
+				So(p.Report(), ShouldResemble, &HierarchySyncReport{
+					Relabelled: []string{"a"},
+					Reparented: []string{"a"},
+					Reordered:  []string{"a", "b"},
+					Unresolved: []string{"c", "removed"},
+				})
+			})
+		})
+	})
+}
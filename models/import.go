@@ -0,0 +1,168 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportCheckpoint records a batch of observations that has been confirmed as inserted for an instance
+type ImportCheckpoint struct {
+	MinRowIndex int64
+	MaxRowIndex int64
+	Count       int64
+	Checksum    uint32
+}
+
+// NewImportCheckpoint creates a checkpoint for the provided batch of observations. The checksum
+// is calculated from the row index and value of each observation, ordered by row index, so it
+// does not depend on the order of the batch. It should be created before the batch is inserted,
+// as some implementations escape the observation values as they are inserted.
+func NewImportCheckpoint(observations []*Observation) (*ImportCheckpoint, error) {
+	if len(observations) == 0 {
+		return nil, errors.New("cannot create an import checkpoint for an empty batch")
+	}
+
+	sorted := make([]*Observation, len(observations))
+	copy(sorted, observations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].RowIndex < sorted[j].RowIndex
+	})
+
+	hash := crc32.NewIEEE()
+	for _, obs := range sorted {
+		fmt.Fprintf(hash, "%d:%s\n", obs.RowIndex, obs.Row)
+	}
+
+	return &ImportCheckpoint{
+		MinRowIndex: sorted[0].RowIndex,
+		MaxRowIndex: sorted[len(sorted)-1].RowIndex,
+		Count:       int64(len(sorted)),
+		Checksum:    hash.Sum32(),
+	}, nil
+}
+
+// Validate checks the checkpoint describes a valid range of rows
+func (c *ImportCheckpoint) Validate() error {
+	if c == nil {
+		return errors.New("import checkpoint is required but was nil")
+	}
+	if c.MinRowIndex < 0 {
+		return errors.New("import checkpoint min row index cannot be negative")
+	}
+	if c.MaxRowIndex < c.MinRowIndex {
+		return errors.New("import checkpoint max row index cannot be less than min row index")
+	}
+	if c.Count < 1 || c.Count > c.MaxRowIndex-c.MinRowIndex+1 {
+		return errors.New("import checkpoint count does not fit the row index range")
+	}
+	return nil
+}
+
+// String returns the representation of the checkpoint stored on the instance node
+func (c *ImportCheckpoint) String() string {
+	return fmt.Sprintf("%d:%d:%d:%d", c.MinRowIndex, c.MaxRowIndex, c.Count, c.Checksum)
+}
+
+// ParseImportCheckpoint parses a checkpoint stored on an instance node
+func ParseImportCheckpoint(s string) (*ImportCheckpoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid import checkpoint %q", s)
+	}
+
+	values := make([]int64, 3)
+	for i := range values {
+		v, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid import checkpoint %q", s)
+		}
+		values[i] = v
+	}
+
+	checksum, err := strconv.ParseUint(parts[3], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import checkpoint %q", s)
+	}
+
+	c := &ImportCheckpoint{
+		MinRowIndex: values[0],
+		MaxRowIndex: values[1],
+		Count:       values[2],
+		Checksum:    uint32(checksum),
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid import checkpoint %q: %s", s, err)
+	}
+	return c, nil
+}
+
+// ImportProgress holds the checkpoints recorded for an instance, ordered by row index
+type ImportProgress struct {
+	InstanceID  string
+	Checkpoints []*ImportCheckpoint
+}
+
+// NewImportProgress creates the import progress of an instance from its stored checkpoints
+func NewImportProgress(instanceID string, checkpoints []string) (*ImportProgress, error) {
+	p := &ImportProgress{
+		InstanceID:  instanceID,
+		Checkpoints: make([]*ImportCheckpoint, 0, len(checkpoints)),
+	}
+
+	for _, s := range checkpoints {
+		c, err := ParseImportCheckpoint(s)
+		if err != nil {
+			return nil, err
+		}
+		p.Checkpoints = append(p.Checkpoints, c)
+	}
+
+	sort.Slice(p.Checkpoints, func(i, j int) bool {
+		if p.Checkpoints[i].MinRowIndex == p.Checkpoints[j].MinRowIndex {
+			return p.Checkpoints[i].MaxRowIndex < p.Checkpoints[j].MaxRowIndex
+		}
+		return p.Checkpoints[i].MinRowIndex < p.Checkpoints[j].MinRowIndex
+	})
+
+	return p, nil
+}
+
+// Count returns the number of observations confirmed by the checkpoints
+func (p *ImportProgress) Count() int64 {
+	var count int64
+	for _, c := range p.Checkpoints {
+		count += c.Count
+	}
+	return count
+}
+
+// Covers returns true if the row index falls within a confirmed batch
+func (p *ImportProgress) Covers(rowIndex int64) bool {
+	for _, c := range p.Checkpoints {
+		if rowIndex >= c.MinRowIndex && rowIndex <= c.MaxRowIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// ResumeFrom returns the row index an import should restart from, given the index of the
+// first row of the import. This is the first row after the contiguous run of confirmed
+// batches starting at firstRowIndex. Batches confirmed beyond a gap are not skipped, as
+// re-inserting observations is idempotent.
+func (p *ImportProgress) ResumeFrom(firstRowIndex int64) int64 {
+	next := firstRowIndex
+	for _, c := range p.Checkpoints {
+		if c.MinRowIndex > next {
+			break
+		}
+		if c.MaxRowIndex >= next {
+			next = c.MaxRowIndex + 1
+		}
+	}
+	return next
+}
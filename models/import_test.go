@@ -0,0 +1,170 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewImportCheckpoint(t *testing.T) {
+	Convey("Given an empty batch of observations", t, func() {
+		Convey("When NewImportCheckpoint is called", func() {
+			c, err := NewImportCheckpoint([]*Observation{})
+
+			Convey("Then an error is returned", func() {
+				So(c, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "cannot create an import checkpoint for an empty batch")
+			})
+		})
+	})
+
+	Convey("Given a batch of observations", t, func() {
+		obs := []*Observation{
+			{Row: "3,c", RowIndex: 12},
+			{Row: "1,a", RowIndex: 10},
+			{Row: "2,b", RowIndex: 11},
+		}
+
+		Convey("When NewImportCheckpoint is called", func() {
+			c, err := NewImportCheckpoint(obs)
+
+			Convey("Then the checkpoint describes the batch", func() {
+				So(err, ShouldBeNil)
+				So(c.MinRowIndex, ShouldEqual, 10)
+				So(c.MaxRowIndex, ShouldEqual, 12)
+				So(c.Count, ShouldEqual, 3)
+				So(c.Validate(), ShouldBeNil)
+			})
+
+			Convey("Then the checksum does not depend on the order of the batch", func() {
+				reordered, err := NewImportCheckpoint([]*Observation{obs[1], obs[2], obs[0]})
+				So(err, ShouldBeNil)
+				So(reordered, ShouldResemble, c)
+			})
+
+			Convey("Then the checksum changes if a value changes", func() {
+				changed, err := NewImportCheckpoint([]*Observation{obs[1], obs[2], {Row: "3,d", RowIndex: 12}})
+				So(err, ShouldBeNil)
+				So(changed.Checksum, ShouldNotEqual, c.Checksum)
+			})
+
+			Convey("Then the checkpoint can be parsed from its string representation", func() {
+				parsed, err := ParseImportCheckpoint(c.String())
+				So(err, ShouldBeNil)
+				So(parsed, ShouldResemble, c)
+			})
+		})
+	})
+}
+
+func TestImportCheckpoint_Validate(t *testing.T) {
+	Convey("Given a nil checkpoint", t, func() {
+		var c *ImportCheckpoint
+
+		Convey("When Validate is called", func() {
+			err := c.Validate()
+
+			Convey("Then the expected error is returned", func() {
+				So(err.Error(), ShouldEqual, "import checkpoint is required but was nil")
+			})
+		})
+	})
+
+	Convey("Given a checkpoint with a count larger than its row index range", t, func() {
+		c := &ImportCheckpoint{MinRowIndex: 1, MaxRowIndex: 2, Count: 3}
+
+		Convey("When Validate is called", func() {
+			err := c.Validate()
+
+			Convey("Then the expected error is returned", func() {
+				So(err.Error(), ShouldEqual, "import checkpoint count does not fit the row index range")
+			})
+		})
+	})
+
+	Convey("Given a checkpoint with a max row index lower than its min row index", t, func() {
+		c := &ImportCheckpoint{MinRowIndex: 5, MaxRowIndex: 2, Count: 1}
+
+		Convey("When Validate is called", func() {
+			err := c.Validate()
+
+			Convey("Then the expected error is returned", func() {
+				So(err.Error(), ShouldEqual, "import checkpoint max row index cannot be less than min row index")
+			})
+		})
+	})
+}
+
+func TestParseImportCheckpoint(t *testing.T) {
+	Convey("Given invalid stored checkpoints", t, func() {
+		for _, s := range []string{"", "1:2:3", "a:2:1:4", "1:2:1:-4", "3:2:1:4"} {
+			Convey("When ParseImportCheckpoint is called with "+s, func() {
+				c, err := ParseImportCheckpoint(s)
+
+				Convey("Then an error is returned", func() {
+					So(c, ShouldBeNil)
+					So(err, ShouldNotBeNil)
+				})
+			})
+		}
+	})
+}
+
+func TestImportProgress(t *testing.T) {
+	Convey("Given an instance without checkpoints", t, func() {
+		p, err := NewImportProgress("123", nil)
+		So(err, ShouldBeNil)
+
+		Convey("Then the import resumes from the first row", func() {
+			So(p.ResumeFrom(1), ShouldEqual, 1)
+			So(p.Count(), ShouldEqual, 0)
+			So(p.Covers(1), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an instance with unordered, overlapping checkpoints and a gap", t, func() {
+		p, err := NewImportProgress("123", []string{
+			"201:300:100:4",
+			"1:100:100:1",
+			"401:500:100:3",
+			"101:200:100:2",
+			"151:200:50:5",
+		})
+		So(err, ShouldBeNil)
+
+		Convey("Then the checkpoints are ordered by row index", func() {
+			So(p.InstanceID, ShouldEqual, "123")
+			So(p.Checkpoints, ShouldHaveLength, 5)
+			So(p.Checkpoints[0].Checksum, ShouldEqual, 1)
+			So(p.Checkpoints[1].Checksum, ShouldEqual, 2)
+			So(p.Checkpoints[2].Checksum, ShouldEqual, 5)
+			So(p.Checkpoints[3].Checksum, ShouldEqual, 4)
+			So(p.Checkpoints[4].Checksum, ShouldEqual, 3)
+		})
+
+		Convey("Then the import resumes after the last contiguous checkpoint", func() {
+			So(p.ResumeFrom(1), ShouldEqual, 301)
+		})
+
+		Convey("Then an import starting after the recorded checkpoints resumes from its first row", func() {
+			So(p.ResumeFrom(1000), ShouldEqual, 1000)
+		})
+
+		Convey("Then rows are covered only if they fall in a checkpoint", func() {
+			So(p.Covers(150), ShouldBeTrue)
+			So(p.Covers(450), ShouldBeTrue)
+			So(p.Covers(350), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an invalid stored checkpoint", t, func() {
+		Convey("When NewImportProgress is called", func() {
+			p, err := NewImportProgress("123", []string{"1:100:100:1", "invalid"})
+
+			Convey("Then an error is returned", func() {
+				So(p, ShouldBeNil)
+				So(err.Error(), ShouldEqual, `invalid import checkpoint "invalid"`)
+			})
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package models
+
+// InstanceValidationReport describes the consistency of an imported instance
+type InstanceValidationReport struct {
+	InstanceID       string                       `json:"instance_id"`
+	ObservationCount int64                        `json:"observation_count"`
+	Dimensions       []*DimensionValidationReport `json:"dimensions"`
+}
+
+// DimensionValidationReport describes the consistency of a single dimension of an imported instance
+type DimensionValidationReport struct {
+	Name string `json:"name"`
+	// ObservationsMissingEdges is the number of observations without an 'isValueOf' edge to an option of the dimension
+	ObservationsMissingEdges int64 `json:"observations_missing_edges"`
+	// OptionsWithoutObservations lists the options that no observation is a value of
+	OptionsWithoutObservations []string `json:"options_without_observations"`
+	// OrphanOptions lists the options that are not linked to the instance node
+	OrphanOptions []string `json:"orphan_options"`
+	// HasHierarchy is true if an instance hierarchy has been built for the dimension
+	HasHierarchy bool `json:"has_hierarchy"`
+	// HasDataMismatches lists the codes of hierarchy nodes whose hasData flag does not match
+	// the presence of the code as a dimension option
+	HasDataMismatches []string `json:"has_data_mismatches"`
+}
+
+// IsValid returns true if no inconsistencies were found in any dimension
+func (r *InstanceValidationReport) IsValid() bool {
+	for _, d := range r.Dimensions {
+		if !d.IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValid returns true if no inconsistencies were found in the dimension
+func (d *DimensionValidationReport) IsValid() bool {
+	return d.ObservationsMissingEdges == 0 &&
+		len(d.OptionsWithoutObservations) == 0 &&
+		len(d.OrphanOptions) == 0 &&
+		len(d.HasDataMismatches) == 0
+}
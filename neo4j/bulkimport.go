@@ -0,0 +1,280 @@
+package neo4j
+
+/*
+This module provides an offline alternative to InsertObservationBatch for the initial
+load of large datasets. Rather than sending statements to the database, the nodes and
+relationships of a whole instance are written as `neo4j-admin import` CSV files, along
+with a manifest describing them.
+
+neo4j-admin import does not create constraints, so once the files have been imported the
+instance and dimension constraints should be created as they would be by CreateInstance
+and InsertDimension.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-graph/v2/internal/bulkfile"
+	"github.com/ONSdigital/dp-graph/v2/models"
+)
+
+// Names of the files generated by a BulkImportWriter
+const (
+	BulkImportInstanceFile     = "instance.csv"
+	BulkImportDimensionsFile   = "dimensions.csv"
+	BulkImportObservationsFile = "observations.csv"
+	BulkImportHasDimensionFile = "has_dimension.csv"
+	BulkImportIsValueOfFile    = "is_value_of.csv"
+	BulkImportManifestFile     = "manifest.json"
+)
+
+// neo4j-admin import CSV headers
+var (
+	bulkImportInstanceHeader      = []string{":ID", "header", ":LABEL"}
+	bulkImportDimensionsHeader    = []string{":ID", "value", ":LABEL"}
+	bulkImportObservationsHeader  = []string{":ID", "value", "rowIndex:long", ":LABEL"}
+	bulkImportRelationshipsHeader = []string{":START_ID", ":END_ID", ":TYPE"}
+)
+
+// ErrBulkImportWriterClosed is returned when writing to a BulkImportWriter that has already been closed
+var ErrBulkImportWriterClosed = errors.New("bulk import writer is closed")
+
+// FileCreator creates (or truncates) the named file and returns a writer for it
+type FileCreator = bulkfile.FileCreator
+
+// DirFileCreator returns a FileCreator that creates files in the provided directory
+func DirFileCreator(dir string) FileCreator {
+	return bulkfile.DirFileCreator(dir)
+}
+
+// BulkImportManifest describes the files generated for an instance
+type BulkImportManifest struct {
+	InstanceID    string           `json:"instance_id"`
+	Nodes         []BulkImportFile `json:"nodes"`
+	Relationships []BulkImportFile `json:"relationships"`
+}
+
+// BulkImportFile describes a single generated file and the number of records it holds
+type BulkImportFile struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// Args returns the neo4j-admin import arguments for the files in the manifest,
+// relative to the directory the files were written to
+func (m *BulkImportManifest) Args() []string {
+	args := make([]string, 0, len(m.Nodes)+len(m.Relationships))
+	for _, f := range m.Nodes {
+		args = append(args, "--nodes="+f.Name)
+	}
+	for _, f := range m.Relationships {
+		args = append(args, "--relationships="+f.Name)
+	}
+	return args
+}
+
+// bulkImportFile is an open CSV file with the label of the nodes or relationships it holds
+type bulkImportFile struct {
+	*bulkfile.File
+	label string
+}
+
+// manifest returns the description of the file in the manifest
+func (f *bulkImportFile) manifest() BulkImportFile {
+	return BulkImportFile{Name: f.Name, Label: f.label, Count: f.Count}
+}
+
+// BulkImportWriter writes the instance, dimension option and observation nodes of an
+// instance, and the relationships between them, as neo4j-admin import files.
+// It is safe for concurrent use.
+type BulkImportWriter struct {
+	instanceID string
+	create     FileCreator
+
+	mu           sync.Mutex
+	closed       bool
+	instance     *bulkImportFile
+	dimensions   *bulkImportFile
+	observations *bulkImportFile
+	hasDimension *bulkImportFile
+	isValueOf    *bulkImportFile
+	nodeIDs      map[string]bool
+}
+
+// NewBulkImportWriter creates the bulk import files for the provided instance using the
+// FileCreator, and writes the instance node with the provided CSV header
+func NewBulkImportWriter(instanceID, header string, create FileCreator) (*BulkImportWriter, error) {
+	if len(instanceID) == 0 {
+		return nil, errors.New("instance id is required but was empty")
+	}
+	if create == nil {
+		return nil, errors.New("a file creator is required but was nil")
+	}
+
+	w := &BulkImportWriter{
+		instanceID: instanceID,
+		create:     create,
+		nodeIDs:    make(map[string]bool),
+	}
+
+	files := []struct {
+		dest   **bulkImportFile
+		name   string
+		label  string
+		header []string
+	}{
+		{&w.instance, BulkImportInstanceFile, w.instanceLabel(), bulkImportInstanceHeader},
+		{&w.dimensions, BulkImportDimensionsFile, "", bulkImportDimensionsHeader},
+		{&w.observations, BulkImportObservationsFile, fmt.Sprintf("_%s_observation", instanceID), bulkImportObservationsHeader},
+		{&w.hasDimension, BulkImportHasDimensionFile, "HAS_DIMENSION", bulkImportRelationshipsHeader},
+		{&w.isValueOf, BulkImportIsValueOfFile, "isValueOf", bulkImportRelationshipsHeader},
+	}
+
+	for _, f := range files {
+		opened, err := w.open(f.name, f.label, f.header)
+		if err != nil {
+			// close any files opened before the failure
+			w.closeFiles()
+			return nil, err
+		}
+		*f.dest = opened
+	}
+
+	if err := w.instance.Write([]string{w.instanceLabel(), header, w.instanceLabel()}); err != nil {
+		w.closeFiles()
+		return nil, errors.Wrap(err, "failed to write instance node")
+	}
+
+	return w, nil
+}
+
+func (w *BulkImportWriter) open(name, label string, header []string) (*bulkImportFile, error) {
+	f, err := bulkfile.Open(w.create, "bulk import file", name, header)
+	if err != nil {
+		return nil, err
+	}
+	return &bulkImportFile{File: f, label: label}, nil
+}
+
+func (w *BulkImportWriter) instanceLabel() string {
+	return fmt.Sprintf("_%s_Instance", w.instanceID)
+}
+
+// WriteDimension writes a dimension option node and the 'HAS_DIMENSION' relationship from the
+// instance node. NodeID is set on the provided dimension to the import ID of the node.
+func (w *BulkImportWriter) WriteDimension(d *models.Dimension) (*models.Dimension, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, ErrBulkImportWriterClosed
+	}
+
+	dimensionLabel := fmt.Sprintf("_%s_%s", w.instanceID, d.DimensionID)
+	nodeID := fmt.Sprintf("%s_%s", dimensionLabel, d.Option)
+
+	if w.nodeIDs[nodeID] {
+		return nil, errors.Errorf("dimension option %q has already been written", nodeID)
+	}
+
+	if err := w.dimensions.Write([]string{nodeID, d.Option, dimensionLabel}); err != nil {
+		return nil, errors.Wrap(err, "failed to write dimension node")
+	}
+
+	if err := w.hasDimension.Write([]string{w.instanceLabel(), nodeID, "HAS_DIMENSION"}); err != nil {
+		return nil, errors.Wrap(err, "failed to write dimension relationship")
+	}
+
+	w.nodeIDs[nodeID] = true
+	d.NodeID = nodeID
+	return d, nil
+}
+
+// WriteObservationBatch writes observation nodes and their 'isValueOf' relationships to the
+// dimension option nodes, which must have been written with WriteDimension beforehand
+func (w *BulkImportWriter) WriteObservationBatch(observations []*models.Observation) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrBulkImportWriterClosed
+	}
+
+	for _, obs := range observations {
+		obsID := fmt.Sprintf("_%s_observation_%d", w.instanceID, obs.RowIndex)
+
+		// resolve the dimension options first, so an unknown option does not leave a partial observation
+		dimensionNodeIDs := make([]string, 0, len(obs.DimensionOptions))
+		for _, option := range obs.DimensionOptions {
+			lookup := fmt.Sprintf("_%s_%s_%s", w.instanceID, strings.ToLower(option.DimensionName), option.Name)
+			if !w.nodeIDs[lookup] {
+				return fmt.Errorf("No nodeId found for %s", lookup)
+			}
+			dimensionNodeIDs = append(dimensionNodeIDs, lookup)
+		}
+
+		if err := w.observations.Write([]string{obsID, obs.Row, strconv.FormatInt(obs.RowIndex, 10), w.observations.label}); err != nil {
+			return errors.Wrap(err, "failed to write observation node")
+		}
+
+		for _, nodeID := range dimensionNodeIDs {
+			if err := w.isValueOf.Write([]string{obsID, nodeID, "isValueOf"}); err != nil {
+				return errors.Wrap(err, "failed to write observation relationship")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close flushes and closes all bulk import files, then writes and returns the manifest.
+// The writer cannot be used after it has been closed.
+func (w *BulkImportWriter) Close() (*BulkImportManifest, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, ErrBulkImportWriterClosed
+	}
+
+	if err := w.closeFiles(); err != nil {
+		return nil, err
+	}
+
+	manifest := &BulkImportManifest{
+		InstanceID: w.instanceID,
+		Nodes: []BulkImportFile{
+			w.instance.manifest(),
+			w.dimensions.manifest(),
+			w.observations.manifest(),
+		},
+		Relationships: []BulkImportFile{
+			w.hasDimension.manifest(),
+			w.isValueOf.manifest(),
+		},
+	}
+
+	if err := bulkfile.WriteManifest(w.create, "bulk import manifest", BulkImportManifestFile, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// closeFiles closes any open file, returning the first error encountered
+func (w *BulkImportWriter) closeFiles() error {
+	w.closed = true
+	var files []*bulkfile.File
+	for _, f := range []*bulkImportFile{w.instance, w.dimensions, w.observations, w.hasDimension, w.isValueOf} {
+		if f != nil {
+			files = append(files, f.File)
+		}
+	}
+	return bulkfile.CloseAll("bulk import file", files...)
+}
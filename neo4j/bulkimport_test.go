@@ -0,0 +1,166 @@
+package neo4j
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// memFile is an in-memory io.WriteCloser used to capture bulk import files
+type memFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *memFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func memFileCreator(files map[string]*memFile) FileCreator {
+	return func(name string) (io.WriteCloser, error) {
+		f := &memFile{}
+		files[name] = f
+		return f, nil
+	}
+}
+
+func TestNewBulkImportWriter(t *testing.T) {
+
+	Convey("Given an empty instance ID", t, func() {
+		files := map[string]*memFile{}
+
+		Convey("When NewBulkImportWriter is called", func() {
+			w, err := NewBulkImportWriter("", "V4_0,age", memFileCreator(files))
+
+			Convey("Then the expected error is returned and no files are created", func() {
+				So(w, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "instance id is required but was empty")
+				So(files, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a file creator that fails for the observations file", t, func() {
+		files := map[string]*memFile{}
+		create := func(name string) (io.WriteCloser, error) {
+			if name == BulkImportObservationsFile {
+				return nil, errors.New("disk full")
+			}
+			return memFileCreator(files)(name)
+		}
+
+		Convey("When NewBulkImportWriter is called", func() {
+			w, err := NewBulkImportWriter("123", "V4_0,age", create)
+
+			Convey("Then the error is returned and the files opened so far are closed", func() {
+				So(w, ShouldBeNil)
+				So(err.Error(), ShouldEqual, `failed to create bulk import file "observations.csv": disk full`)
+				So(files, ShouldHaveLength, 2)
+				So(files[BulkImportInstanceFile].closed, ShouldBeTrue)
+				So(files[BulkImportDimensionsFile].closed, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestBulkImportWriter(t *testing.T) {
+
+	Convey("Given a bulk import writer for an instance", t, func() {
+		files := map[string]*memFile{}
+		w, err := NewBulkImportWriter("123", "V4_0,age,sex", memFileCreator(files))
+		So(err, ShouldBeNil)
+
+		_, err = w.WriteDimension(&models.Dimension{DimensionID: "sex", Option: "Male"})
+		So(err, ShouldBeNil)
+
+		Convey("When dimensions and observations are written and the writer is closed", func() {
+			d, err := w.WriteDimension(&models.Dimension{DimensionID: "age", Option: "45"})
+			So(err, ShouldBeNil)
+			So(d.NodeID, ShouldEqual, "_123_age_45")
+
+			err = w.WriteObservationBatch([]*models.Observation{validObservation})
+			So(err, ShouldBeNil)
+
+			manifest, err := w.Close()
+			So(err, ShouldBeNil)
+
+			Convey("Then the nodes are written in neo4j-admin import format", func() {
+				So(files[BulkImportInstanceFile].String(), ShouldEqual,
+					":ID,header,:LABEL\n"+
+						`_123_Instance,"V4_0,age,sex",_123_Instance`+"\n")
+				So(files[BulkImportDimensionsFile].String(), ShouldEqual,
+					":ID,value,:LABEL\n"+
+						"_123_sex_Male,Male,_123_sex\n"+
+						"_123_age_45,45,_123_age\n")
+				So(files[BulkImportObservationsFile].String(), ShouldEqual,
+					":ID,value,rowIndex:long,:LABEL\n"+
+						`_123_observation_5678,"the,row,content",5678,_123_observation`+"\n")
+			})
+
+			Convey("Then the relationships are written in neo4j-admin import format", func() {
+				So(files[BulkImportHasDimensionFile].String(), ShouldEqual,
+					":START_ID,:END_ID,:TYPE\n"+
+						"_123_Instance,_123_sex_Male,HAS_DIMENSION\n"+
+						"_123_Instance,_123_age_45,HAS_DIMENSION\n")
+				So(files[BulkImportIsValueOfFile].String(), ShouldEqual,
+					":START_ID,:END_ID,:TYPE\n"+
+						"_123_observation_5678,_123_sex_Male,isValueOf\n"+
+						"_123_observation_5678,_123_age_45,isValueOf\n")
+			})
+
+			Convey("Then the manifest describes the generated files", func() {
+				So(manifest.InstanceID, ShouldEqual, "123")
+				So(manifest.Nodes, ShouldResemble, []BulkImportFile{
+					{Name: BulkImportInstanceFile, Label: "_123_Instance", Count: 1},
+					{Name: BulkImportDimensionsFile, Label: "", Count: 2},
+					{Name: BulkImportObservationsFile, Label: "_123_observation", Count: 1},
+				})
+				So(manifest.Relationships, ShouldResemble, []BulkImportFile{
+					{Name: BulkImportHasDimensionFile, Label: "HAS_DIMENSION", Count: 2},
+					{Name: BulkImportIsValueOfFile, Label: "isValueOf", Count: 2},
+				})
+				So(manifest.Args(), ShouldResemble, []string{
+					"--nodes=instance.csv",
+					"--nodes=dimensions.csv",
+					"--nodes=observations.csv",
+					"--relationships=has_dimension.csv",
+					"--relationships=is_value_of.csv",
+				})
+
+				written := &BulkImportManifest{}
+				So(json.Unmarshal(files[BulkImportManifestFile].Bytes(), written), ShouldBeNil)
+				So(written, ShouldResemble, manifest)
+			})
+
+			Convey("Then the writer cannot be used any more", func() {
+				So(w.WriteObservationBatch(nil), ShouldEqual, ErrBulkImportWriterClosed)
+				_, err = w.Close()
+				So(err, ShouldEqual, ErrBulkImportWriterClosed)
+			})
+		})
+
+		Convey("When the same dimension option is written twice", func() {
+			_, err := w.WriteDimension(&models.Dimension{DimensionID: "sex", Option: "Male"})
+
+			Convey("Then an error is returned", func() {
+				So(err.Error(), ShouldEqual, `dimension option "_123_sex_Male" has already been written`)
+			})
+		})
+
+		Convey("When an observation refers to a dimension option that has not been written", func() {
+			err := w.WriteObservationBatch([]*models.Observation{validObservation})
+
+			Convey("Then an error is returned and the observation is not written", func() {
+				So(err.Error(), ShouldEqual, "No nodeId found for _123_age_45")
+				So(w.observations.Count, ShouldEqual, 0)
+				So(w.isValueOf.Count, ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -3,6 +3,7 @@ package neo4j
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
@@ -16,10 +17,20 @@ var _ driver.CodeList = (*Neo4j)(nil)
 
 // GetCodeLists returns a list of code lists
 func (n *Neo4j) GetCodeLists(ctx context.Context, filterBy string) (*models.CodeListResults, error) {
-	logData := log.Data{}
+	var flags []string
 	if len(filterBy) > 0 {
-		logData["filter_by"] = filterBy
-		filterBy = ":_" + filterBy
+		flags = append(flags, filterBy)
+	}
+	return n.GetCodeListsByFlags(ctx, flags)
+}
+
+// GetCodeListsByFlags returns a list of the code lists labelled with all the provided type flags
+func (n *Neo4j) GetCodeListsByFlags(ctx context.Context, flags []string) (*models.CodeListResults, error) {
+	logData := log.Data{}
+	var filterBy string
+	if len(flags) > 0 {
+		logData["flags"] = flags
+		filterBy = ":_" + strings.Join(flags, ":_")
 	}
 	log.Info(ctx, "about to query neo4j for code lists", logData)
 
@@ -49,6 +60,21 @@ func (n *Neo4j) GetCodeList(ctx context.Context, code string) (*models.CodeList,
 	return codeListResult, nil
 }
 
+// GetCodeListDetail returns the metadata of the specified code list and its editions
+func (n *Neo4j) GetCodeListDetail(ctx context.Context, codeListID string) (*models.CodeListDetail, error) {
+	log.Info(ctx, "about to query neo4j for code list detail", log.Data{"code_list_id": codeListID})
+
+	query := fmt.Sprintf(query.GetCodeListDetail, codeListID)
+	var nodes []models.CodeListNode
+
+	if err := n.Read(query, mapper.CodeListNodes(&nodes), false); err != nil {
+		//includes not found/404 responses
+		return nil, err
+	}
+
+	return models.NewCodeListDetail(codeListID, nodes), nil
+}
+
 // GetEditions returns a list of editions for a specified code list
 func (n *Neo4j) GetEditions(ctx context.Context, codeListID string) (*models.Editions, error) {
 	log.Info(ctx, "about to query neo4j for code list editions", log.Data{"code_list_id": codeListID})
@@ -81,9 +107,15 @@ func (n *Neo4j) CountCodes(ctx context.Context, codeListID, edition string) (int
 	return 0, driver.ErrNotImplemented
 }
 
-// GetCodes returns a list of codes for a specified edition of a code list
-func (n *Neo4j) GetCodes(ctx context.Context, codeListID, editionID string) (*models.CodeResults, error) {
-	log.Info(ctx, "about to query neo4j for codes", log.Data{"code_list_id": codeListID, "edition": editionID})
+// GetCodes returns a list of codes for a specified edition of a code list, sorted by label or code if requested,
+// or by the order of the codes in the edition and then by code otherwise
+func (n *Neo4j) GetCodes(ctx context.Context, codeListID, editionID string, opts ...driver.ReadOption) (*models.CodeResults, error) {
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "about to query neo4j for codes", log.Data{"code_list_id": codeListID, "edition": editionID, "language": options.Language, "sort": options.Sort})
 
 	exists, err := n.GetEdition(ctx, codeListID, editionID)
 	if err != nil || exists == nil {
@@ -91,8 +123,19 @@ func (n *Neo4j) GetCodes(ctx context.Context, codeListID, editionID string) (*mo
 	}
 
 	codes := &models.CodeResults{}
-	query := fmt.Sprintf(query.GetCodes, codeListID, editionID)
-	if err := n.Read(query, mapper.Codes(codes, codeListID, editionID), false); err != nil {
+	orderBy := query.CodesByOrder
+	switch options.Sort {
+	case driver.SortByLabel:
+		orderBy = query.CodesByLabel
+		if !options.IsDefaultLanguage() {
+			orderBy = fmt.Sprintf(query.CodesByLocalisedLabel, options.LabelProperty())
+		}
+	case driver.SortByCode:
+		orderBy = query.CodesByCode
+	}
+
+	query := fmt.Sprintf(query.GetCodes, codeListID, editionID, orderBy)
+	if err := n.Read(query, mapper.Codes(codes, codeListID, editionID, options.Language), false); err != nil {
 		return nil, err
 	}
 
@@ -100,8 +143,13 @@ func (n *Neo4j) GetCodes(ctx context.Context, codeListID, editionID string) (*mo
 }
 
 // GetCode returns the specified code for an edition of a code list
-func (n *Neo4j) GetCode(ctx context.Context, codeListID, editionID string, codeID string) (*models.Code, error) {
-	log.Info(ctx, "about to query neo4j for specific code", log.Data{"code_list_id": codeListID, "edition": editionID, "code": codeID})
+func (n *Neo4j) GetCode(ctx context.Context, codeListID, editionID string, codeID string, opts ...driver.ReadOption) (*models.Code, error) {
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "about to query neo4j for specific code", log.Data{"code_list_id": codeListID, "edition": editionID, "code": codeID, "language": options.Language})
 
 	exists, err := n.GetEdition(ctx, codeListID, editionID)
 	if err != nil || exists == nil {
@@ -110,16 +158,96 @@ func (n *Neo4j) GetCode(ctx context.Context, codeListID, editionID string, codeI
 
 	code := &models.Code{}
 	query := fmt.Sprintf(query.GetCode, codeListID, editionID, codeID)
-	if err := n.Read(query, mapper.Code(code, codeListID, editionID), true); err != nil {
+	if err := n.Read(query, mapper.Code(code, codeListID, editionID, options.Language), true); err != nil {
 		return nil, err
 	}
 
 	return code, nil
 }
 
-// GetCodesOrder is not implemented
+// GetCodesOrder obtains the numerical order value defined in the 'usedBy' relationship between the provided codes and
+// the code list. The order of a code without one defined is nil. If not all the codes are found, the orders of the
+// codes found are returned with driver.ErrNotFound.
 func (n *Neo4j) GetCodesOrder(ctx context.Context, codeListID string, codes []string) (codeOrders map[string]*int, err error) {
-	return nil, driver.ErrNotImplemented
+	codeOrders = make(map[string]*int)
+
+	// if no codes are provided, nothing needs to be done
+	if len(codes) == 0 {
+		return codeOrders, nil
+	}
+
+	log.Info(ctx, "about to query neo4j for codes order", log.Data{"code_list_id": codeListID, "num_codes": len(codes)})
+
+	query := fmt.Sprintf(query.GetCodesOrder, codeListID)
+	params := map[string]interface{}{"codes": codes}
+	if err := n.ReadWithParams(query, params, mapper.CodesOrder(codeOrders), false); err != nil && err != driver.ErrNotFound {
+		return make(map[string]*int), err
+	}
+
+	// if not all 'usedBy' relationships were found, we need to return ErrNotFound
+	if len(codeOrders) < len(codes) {
+		return codeOrders, driver.ErrNotFound
+	}
+
+	return codeOrders, nil
+}
+
+// GetCodesBatch returns the label and order of the provided codes, read in a single query.
+// Codes that are not found are omitted from the result.
+func (n *Neo4j) GetCodesBatch(ctx context.Context, refs []models.CodeRef) (map[models.CodeRef]models.CodeLookup, error) {
+	codes := make(map[models.CodeRef]models.CodeLookup)
+
+	// if no codes are provided, nothing needs to be done
+	if len(refs) == 0 {
+		return codes, nil
+	}
+
+	log.Info(ctx, "about to query neo4j for codes batch", log.Data{"num_codes": len(refs)})
+
+	params := map[string]interface{}{
+		"refs": codeRefsParam(refs),
+	}
+	if err := n.ReadWithParams(query.GetCodesBatch, params, mapper.CodesBatch(codes), false); err != nil && err != driver.ErrNotFound {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func codeRefsParam(refs []models.CodeRef) []interface{} {
+	param := make([]interface{}, 0, len(refs))
+	for _, ref := range refs {
+		param = append(param, map[string]interface{}{
+			"code_list_id": ref.CodeListID,
+			"edition":      ref.Edition,
+			"code":         ref.Code,
+		})
+	}
+	return param
+}
+
+// DiffEditions returns the codes added to, removed from, relabelled or reordered in an edition of a code list
+// compared to another edition. driver.ErrNotFound is returned if either edition does not exist.
+func (n *Neo4j) DiffEditions(ctx context.Context, codeListID, fromEdition, toEdition string) (*models.CodeListDiff, error) {
+	log.Info(ctx, "about to query neo4j for code list editions diff", log.Data{"code_list_id": codeListID, "from_edition": fromEdition, "to_edition": toEdition})
+
+	for _, edition := range []string{fromEdition, toEdition} {
+		if _, err := n.GetEdition(ctx, codeListID, edition); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make(map[string][]models.CodeListEntry)
+	query := fmt.Sprintf(query.GetEditionsCodes, codeListID)
+	params := map[string]interface{}{
+		"from_edition": fromEdition,
+		"to_edition":   toEdition,
+	}
+	if err := n.ReadWithParams(query, params, mapper.CodeListEntries(entries), false); err != nil && err != driver.ErrNotFound {
+		return nil, err
+	}
+
+	return models.NewCodeListDiff(codeListID, fromEdition, toEdition, entries[fromEdition], entries[toEdition]), nil
 }
 
 // GetCodeDatasets returns a list of datasets where the code is used
@@ -0,0 +1,458 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	graph "github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/internal"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	boltstructures "github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNeo4j_GetCodeListsByFlags(t *testing.T) {
+	Convey("Given a database containing code lists", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{[]interface{}{"_code_list", "_code_list_mmm", "_geography"}}})
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetCodeListsByFlags is called with several flags", func() {
+			codeLists, err := db.GetCodeListsByFlags(context.Background(), []string{"geography", "hierarchy"})
+
+			Convey("Then the code lists labelled with all flags are queried", func() {
+				So(neoMock.ReadCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadCalls()[0].Query, ShouldEqual, "MATCH (i) WHERE i:_code_list:_geography:_hierarchy RETURN distinct labels(i) as labels")
+			})
+
+			Convey("Then the code lists are returned", func() {
+				So(err, ShouldBeNil)
+				So(codeLists.Items, ShouldResemble, []models.CodeList{{ID: "mmm"}})
+			})
+		})
+
+		Convey("When GetCodeLists is called without a filter", func() {
+			_, err := db.GetCodeLists(context.Background(), "")
+
+			Convey("Then all code lists are queried", func() {
+				So(err, ShouldBeNil)
+				So(neoMock.ReadCalls()[0].Query, ShouldEqual, "MATCH (i) WHERE i:_code_list RETURN distinct labels(i) as labels")
+			})
+		})
+	})
+}
+
+func TestNeo4j_GetCodeListDetail(t *testing.T) {
+	Convey("Given two editions of a code list", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				rows := [][]interface{}{
+					{
+						boltstructures.Node{
+							Labels:     []string{"_code_list", "_code_list_mmm", "_geography"},
+							Properties: map[string]interface{}{"edition": "2020", "label": "Months 2020", "name": "Months"},
+						},
+						int64(12),
+					},
+					{
+						boltstructures.Node{
+							Labels:     []string{"_code_list", "_code_list_mmm"},
+							Properties: map[string]interface{}{"edition": "2019", "label": "Months 2019", "source": "ONS"},
+						},
+						int64(11),
+					},
+				}
+				for _, row := range rows {
+					if err := mapp(&mapper.Result{Data: row}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetCodeListDetail is called", func() {
+			detail, err := db.GetCodeListDetail(context.Background(), "mmm")
+
+			Convey("Then the code list nodes and their code counts are queried", func() {
+				So(neoMock.ReadCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadCalls()[0].Query, ShouldEqual, "MATCH (cl:_code_list:`_code_list_mmm`) OPTIONAL MATCH (cl)<-[:usedBy]-(c:_code) RETURN cl, count(c)")
+			})
+
+			Convey("Then the expected metadata is returned, with flags read from the node labels", func() {
+				So(err, ShouldBeNil)
+				So(detail, ShouldResemble, &models.CodeListDetail{
+					ID:    "mmm",
+					Name:  "Months",
+					Flags: map[string]bool{"geography": true},
+					Editions: []models.EditionDetail{
+						{ID: "2019", Label: "Months 2019", CodeCount: 11},
+						{ID: "2020", Label: "Months 2020", CodeCount: 12},
+					},
+					Properties: map[string]interface{}{"source": "ONS"},
+				})
+			})
+		})
+	})
+
+	Convey("Given a code list that does not exist", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetCodeListDetail is called", func() {
+			detail, err := db.GetCodeListDetail(context.Background(), "mmm")
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(detail, ShouldBeNil)
+				So(err, ShouldEqual, graph.ErrNotFound)
+			})
+		})
+	})
+}
+
+func TestNeo4j_GetCodesBatch(t *testing.T) {
+	mar := models.CodeRef{CodeListID: "mmm", Edition: "2019", Code: "mar"}
+	female := models.CodeRef{CodeListID: "sex", Edition: "one-off", Code: "female"}
+
+	Convey("Given codes of several code lists", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{"mmm", "2019", "mar", "March", int64(3)}})
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetCodesBatch is called", func() {
+			codes, err := db.GetCodesBatch(context.Background(), []models.CodeRef{mar, female})
+
+			Convey("Then all codes are read in a single query", func() {
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadWithParamsCalls()[0].Query, ShouldEqual,
+					"UNWIND {refs} AS ref MATCH (c:_code {value: ref.code})-[r:usedBy]->(cl:_code_list {edition: ref.edition}) WHERE ('_code_list_' + ref.code_list_id) IN labels(cl) RETURN ref.code_list_id, ref.edition, c.value, r.label, r.order")
+				So(neoMock.ReadWithParamsCalls()[0].Params, ShouldResemble, map[string]interface{}{
+					"refs": []interface{}{
+						map[string]interface{}{"code_list_id": "mmm", "edition": "2019", "code": "mar"},
+						map[string]interface{}{"code_list_id": "sex", "edition": "one-off", "code": "female"},
+					},
+				})
+			})
+
+			Convey("Then the codes that were found are returned", func() {
+				three := 3
+				So(err, ShouldBeNil)
+				So(codes, ShouldResemble, map[models.CodeRef]models.CodeLookup{
+					mar: {Label: "March", Order: &three},
+				})
+			})
+		})
+
+		Convey("When GetCodesBatch is called with no codes", func() {
+			codes, err := db.GetCodesBatch(context.Background(), nil)
+
+			Convey("Then an empty map is returned without querying the database", func() {
+				So(err, ShouldBeNil)
+				So(codes, ShouldBeEmpty)
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given none of the codes exist", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetCodesBatch is called", func() {
+			codes, err := db.GetCodesBatch(context.Background(), []models.CodeRef{mar})
+
+			Convey("Then an empty map is returned", func() {
+				So(err, ShouldBeNil)
+				So(codes, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestNeo4j_DiffEditions(t *testing.T) {
+	Convey("Given two editions of a code list", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return nil
+			},
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				rows := [][]interface{}{
+					{"2019", "jan", "January", int64(1)},
+					{"2019", "dec", "December", nil},
+					{"2020", "jan", "Jan", int64(1)},
+					{"2020", "feb", "February", int64(2)},
+				}
+				for _, row := range rows {
+					if err := mapp(&mapper.Result{Data: row}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When DiffEditions is called", func() {
+			diff, err := db.DiffEditions(context.Background(), "mmm", "2019", "2020")
+
+			Convey("Then both editions are checked to exist", func() {
+				So(neoMock.ReadCalls(), ShouldHaveLength, 2)
+				So(neoMock.ReadCalls()[0].Query, ShouldEqual, "MATCH (i:_code_list:`_code_list_mmm` {edition:\"2019\"}) RETURN i")
+				So(neoMock.ReadCalls()[1].Query, ShouldEqual, "MATCH (i:_code_list:`_code_list_mmm` {edition:\"2020\"}) RETURN i")
+			})
+
+			Convey("Then the codes of both editions are read in a single query", func() {
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadWithParamsCalls()[0].Query, ShouldEqual,
+					"MATCH (c:_code)-[r:usedBy]->(cl:_code_list:`_code_list_mmm`) WHERE cl.edition = {from_edition} OR cl.edition = {to_edition} RETURN cl.edition, c.value, r.label, r.order")
+				So(neoMock.ReadWithParamsCalls()[0].Params, ShouldResemble, map[string]interface{}{
+					"from_edition": "2019",
+					"to_edition":   "2020",
+				})
+			})
+
+			Convey("Then the expected differences are returned", func() {
+				one, two := 1, 2
+				So(err, ShouldBeNil)
+				So(diff.Added, ShouldResemble, []models.CodeListEntry{{Code: "feb", Label: "February", Order: &two}})
+				So(diff.Removed, ShouldResemble, []models.CodeListEntry{{Code: "dec", Label: "December"}})
+				So(diff.Relabelled, ShouldResemble, []models.CodeChange{
+					{Code: "jan", FromLabel: "January", ToLabel: "Jan", FromOrder: &one, ToOrder: &one},
+				})
+				So(diff.Reordered, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an edition that does not exist", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When DiffEditions is called", func() {
+			diff, err := db.DiffEditions(context.Background(), "mmm", "2019", "2020")
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(diff, ShouldBeNil)
+				So(err, ShouldEqual, graph.ErrNotFound)
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given the editions cannot be read", t, func() {
+		errRead := errors.New("connection refused")
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return errRead
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When DiffEditions is called", func() {
+			diff, err := db.DiffEditions(context.Background(), "mmm", "2019", "2020")
+
+			Convey("Then the error is returned", func() {
+				So(diff, ShouldBeNil)
+				So(err, ShouldEqual, errRead)
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestNeo4j_GetCode(t *testing.T) {
+	Convey("Given a database containing a code with a label in another language", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				if query == "MATCH (i:_code_list:`_code_list_mmm` {edition:\"one-off\"}) RETURN i" {
+					return mapp(&mapper.Result{Data: []interface{}{boltstructures.Node{Properties: map[string]interface{}{"edition": "one-off"}}}})
+				}
+				return mapp(&mapper.Result{Data: []interface{}{
+					boltstructures.Node{NodeIdentity: 1, Properties: map[string]interface{}{"value": "W92000004"}},
+					boltstructures.Relationship{Properties: map[string]interface{}{"label": "Wales", "label_cy": "Cymru"}},
+				}})
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetCode is called without a language", func() {
+			code, err := db.GetCode(context.Background(), "mmm", "one-off", "W92000004")
+
+			Convey("Then the code is returned with the default label", func() {
+				So(err, ShouldBeNil)
+				So(code.Label, ShouldEqual, "Wales")
+			})
+		})
+
+		Convey("When GetCode is called with the language of the label", func() {
+			code, err := db.GetCode(context.Background(), "mmm", "one-off", "W92000004", graph.WithLanguage("cy"))
+
+			Convey("Then the code is returned with the label in that language", func() {
+				So(err, ShouldBeNil)
+				So(code.Label, ShouldEqual, "Cymru")
+			})
+		})
+
+		Convey("When GetCode is called with a language the code has no label in", func() {
+			code, err := db.GetCode(context.Background(), "mmm", "one-off", "W92000004", graph.WithLanguage("gd"))
+
+			Convey("Then the code is returned with the default label", func() {
+				So(err, ShouldBeNil)
+				So(code.Label, ShouldEqual, "Wales")
+			})
+		})
+
+		Convey("When GetCode is called with an invalid language", func() {
+			code, err := db.GetCode(context.Background(), "mmm", "one-off", "W92000004", graph.WithLanguage("Welsh"))
+
+			Convey("Then the expected error is returned and the database is not queried", func() {
+				So(code, ShouldBeNil)
+				So(err.Error(), ShouldEqual, `invalid language "Welsh"`)
+				So(neoMock.ReadCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestNeo4j_GetCodes(t *testing.T) {
+	Convey("Given a database containing an edition of a code list with ordered codes", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				if query == "MATCH (i:_code_list:`_code_list_mmm` {edition:\"one-off\"}) RETURN i" {
+					return mapp(&mapper.Result{Data: []interface{}{boltstructures.Node{Properties: map[string]interface{}{"edition": "one-off"}}}})
+				}
+				mapp(&mapper.Result{Data: []interface{}{
+					boltstructures.Node{NodeIdentity: 2, Properties: map[string]interface{}{"value": "feb"}},
+					boltstructures.Relationship{Properties: map[string]interface{}{"label": "February", "order": int64(1)}},
+				}})
+				return mapp(&mapper.Result{Data: []interface{}{
+					boltstructures.Node{NodeIdentity: 1, Properties: map[string]interface{}{"value": "jan"}},
+					boltstructures.Relationship{Properties: map[string]interface{}{"label": "January"}},
+				}})
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetCodes is called", func() {
+			codes, err := db.GetCodes(context.Background(), "mmm", "one-off")
+
+			Convey("Then the codes are queried sorted by order and returned with their order", func() {
+				So(err, ShouldBeNil)
+				one := 1
+				So(codes.Items, ShouldResemble, []models.Code{
+					{ID: "2", Code: "feb", Label: "February", Order: &one},
+					{ID: "1", Code: "jan", Label: "January"},
+				})
+				So(neoMock.ReadCalls()[1].Query, ShouldEqual, "MATCH (c:_code) -[r:usedBy]->(cl:_code_list: `_code_list_mmm`) "+
+					"WHERE cl.edition = \"one-off\" RETURN c, r ORDER BY r.order, c.value")
+			})
+		})
+
+		Convey("When GetCodes is called sorted by label in another language", func() {
+			_, err := db.GetCodes(context.Background(), "mmm", "one-off", graph.WithSort(graph.SortByLabel), graph.WithLanguage("cy"))
+
+			Convey("Then the codes are queried sorted by the label in that language, falling back to the default label", func() {
+				So(err, ShouldBeNil)
+				So(neoMock.ReadCalls()[1].Query, ShouldEqual, "MATCH (c:_code) -[r:usedBy]->(cl:_code_list: `_code_list_mmm`) "+
+					"WHERE cl.edition = \"one-off\" RETURN c, r ORDER BY coalesce(r.`label_cy`, r.label), c.value")
+			})
+		})
+
+		Convey("When GetCodes is called sorted by code", func() {
+			_, err := db.GetCodes(context.Background(), "mmm", "one-off", graph.WithSort(graph.SortByCode))
+
+			Convey("Then the codes are queried sorted by code", func() {
+				So(err, ShouldBeNil)
+				So(neoMock.ReadCalls()[1].Query, ShouldEqual, "MATCH (c:_code) -[r:usedBy]->(cl:_code_list: `_code_list_mmm`) "+
+					"WHERE cl.edition = \"one-off\" RETURN c, r ORDER BY c.value")
+			})
+		})
+
+		Convey("When GetCodes is called with an invalid sort", func() {
+			_, err := db.GetCodes(context.Background(), "mmm", "one-off", graph.WithSort("value"))
+
+			Convey("Then the expected error is returned and the database is not queried", func() {
+				So(err.Error(), ShouldEqual, `invalid sort "value"`)
+				So(neoMock.ReadCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestNeo4j_GetCodesOrder(t *testing.T) {
+	Convey("Given a database containing codes with and without an order", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				mapp(&mapper.Result{Data: []interface{}{"jan", int64(1)}})
+				return mapp(&mapper.Result{Data: []interface{}{"feb", nil}})
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetCodesOrder is called with the codes", func() {
+			codeOrders, err := db.GetCodesOrder(context.Background(), "mmm", []string{"jan", "feb"})
+
+			Convey("Then the orders are returned by code", func() {
+				So(err, ShouldBeNil)
+				one := 1
+				So(codeOrders, ShouldResemble, map[string]*int{"jan": &one, "feb": nil})
+
+				calls := neoMock.ReadWithParamsCalls()
+				So(calls, ShouldHaveLength, 1)
+				So(calls[0].Query, ShouldEqual, "MATCH (c:_code)-[r:usedBy]->(:`_code_list_mmm`) WHERE c.value IN {codes} RETURN c.value, min(r.order)")
+				So(calls[0].Params, ShouldResemble, map[string]interface{}{"codes": []string{"jan", "feb"}})
+			})
+		})
+
+		Convey("When GetCodesOrder is called with a code that is not in the code list", func() {
+			codeOrders, err := db.GetCodesOrder(context.Background(), "mmm", []string{"jan", "feb", "mar"})
+
+			Convey("Then the orders of the codes found are returned with ErrNotFound", func() {
+				So(err, ShouldEqual, graph.ErrNotFound)
+				So(codeOrders, ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When GetCodesOrder is called without codes", func() {
+			codeOrders, err := db.GetCodesOrder(context.Background(), "mmm", []string{})
+
+			Convey("Then an empty map is returned without querying the database", func() {
+				So(err, ShouldBeNil)
+				So(codeOrders, ShouldBeEmpty)
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
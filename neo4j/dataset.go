@@ -0,0 +1,63 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Type check to ensure that Neo4j implements the driver.Dataset interface
+var _ driver.Dataset = (*Neo4j)(nil)
+
+// GetDatasetDimensions returns the code list editions used by a version of a dataset, ordered by
+// code list ID. driver.ErrNotFound is returned if the version does not exist or uses no code lists.
+func (n *Neo4j) GetDatasetDimensions(ctx context.Context, datasetID, edition string, version int) (*models.DatasetDimensions, error) {
+	log.Info(ctx, "about to query neo4j for dataset dimensions", log.Data{"dataset_id": datasetID, "edition": edition, "version": version})
+
+	dimensions := &models.DatasetDimensions{
+		Items: []models.DatasetDimension{},
+	}
+	params := versionParams(datasetID, edition, version)
+	if err := n.ReadWithParams(query.GetDatasetDimensions, params, mapper.DatasetDimensions(dimensions), false); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(dimensions.Items, func(i, j int) bool {
+		a, b := dimensions.Items[i], dimensions.Items[j]
+		if a.CodeListID == b.CodeListID {
+			return a.Edition < b.Edition
+		}
+		return a.CodeListID < b.CodeListID
+	})
+
+	return dimensions, nil
+}
+
+// GetDatasetCodes returns the codes of a code list used by a version of a dataset, ordered by code.
+// driver.ErrNotFound is returned if the version does not exist or uses no codes of the code list.
+func (n *Neo4j) GetDatasetCodes(ctx context.Context, datasetID, edition string, version int, codeListID string) (*models.CodeResults, error) {
+	log.Info(ctx, "about to query neo4j for dataset codes", log.Data{"dataset_id": datasetID, "edition": edition, "version": version, "code_list_id": codeListID})
+
+	codes := &models.CodeResults{}
+	stmt := fmt.Sprintf(query.GetDatasetCodes, codeListID)
+	params := versionParams(datasetID, edition, version)
+	if err := n.ReadWithParams(stmt, params, mapper.Codes(codes, codeListID, "", driver.DefaultLanguage), false); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func versionParams(datasetID, edition string, version int) map[string]interface{} {
+	return map[string]interface{}{
+		"dataset_id": datasetID,
+		"edition":    edition,
+		"version":    version,
+	}
+}
@@ -0,0 +1,108 @@
+package neo4j
+
+import (
+	"context"
+	"testing"
+
+	graph "github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/internal"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	boltstructures "github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNeo4j_GetDatasetDimensions(t *testing.T) {
+	Convey("Given a dataset version using codes of several code lists", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				rows := [][]interface{}{
+					{[]interface{}{"_code_list", "_code_list_sex"}, "one-off", int64(3)},
+					{[]interface{}{"_code_list_age", "_code_list"}, "2018", int64(1)},
+				}
+				for _, row := range rows {
+					if err := mapp(&mapper.Result{Data: row}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetDatasetDimensions is called", func() {
+			dimensions, err := db.GetDatasetDimensions(context.Background(), testDatasetId, testEdition, testVersion)
+
+			Convey("Then the version is looked up by its details", func() {
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadWithParamsCalls()[0].Query, ShouldEqual,
+					"MATCH (i)<-[:inDataset]-(c:_code)-[:usedBy]->(cl:_code_list) WHERE i.dataset_id = {dataset_id} AND i.edition = {edition} AND i.version = {version} RETURN labels(cl), cl.edition, count(c)")
+				So(neoMock.ReadWithParamsCalls()[0].Params, ShouldResemble, map[string]interface{}{
+					"dataset_id": testDatasetId,
+					"edition":    testEdition,
+					"version":    testVersion,
+				})
+			})
+
+			Convey("Then the code list editions are returned in order", func() {
+				So(err, ShouldBeNil)
+				So(dimensions.Items, ShouldResemble, []models.DatasetDimension{
+					{CodeListID: "age", Edition: "2018", CodeCount: 1},
+					{CodeListID: "sex", Edition: "one-off", CodeCount: 3},
+				})
+			})
+		})
+	})
+
+	Convey("Given a dataset version that does not exist", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetDatasetDimensions is called", func() {
+			dimensions, err := db.GetDatasetDimensions(context.Background(), testDatasetId, testEdition, testVersion)
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(dimensions, ShouldBeNil)
+				So(err, ShouldEqual, graph.ErrNotFound)
+			})
+		})
+	})
+}
+
+func TestNeo4j_GetDatasetCodes(t *testing.T) {
+	Convey("Given a dataset version using codes of a code list", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{
+					boltstructures.Node{NodeIdentity: 7, Properties: map[string]interface{}{"value": "male"}},
+					boltstructures.Relationship{Properties: map[string]interface{}{"label": "Male"}},
+				}})
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetDatasetCodes is called", func() {
+			codes, err := db.GetDatasetCodes(context.Background(), testDatasetId, testEdition, testVersion, "sex")
+
+			Convey("Then the codes of the code list are queried", func() {
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadWithParamsCalls()[0].Query, ShouldEqual,
+					"MATCH (i)<-[:inDataset]-(c:_code)-[r:usedBy]->(cl:_code_list:`_code_list_sex`) WHERE i.dataset_id = {dataset_id} AND i.edition = {edition} AND i.version = {version} WITH c, head(collect(r)) AS r RETURN c, r ORDER BY c.value")
+			})
+
+			Convey("Then the codes are returned", func() {
+				So(err, ShouldBeNil)
+				So(codes.Items, ShouldResemble, []models.Code{
+					{ID: "7", Code: "male", Label: "Male"},
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,211 @@
+package neo4j
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONSdigital/dp-graph/v2/neo4j/internal"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	bolt "github.com/ONSdigital/golang-neo4j-bolt-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStore_GetGenericHierarchyNodeIDs(t *testing.T) {
+	Convey("Given a database containing generic hierarchy nodes", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				mapp(&mapper.Result{Data: []interface{}{int64(12), "K02000001"}})
+				return mapp(&mapper.Result{Data: []interface{}{int64(7), "E92000001"}})
+			},
+		}
+
+		db := &Neo4j{neoMock, 5, 30}
+
+		Convey("When GetGenericHierarchyNodeIDs is called", func() {
+			ids, err := db.GetGenericHierarchyNodeIDs(context.Background(), 1, "cl", []string{"K02000001", "E92000001"})
+
+			Convey("Then the codes are returned by node ID", func() {
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, map[string]string{"12": "K02000001", "7": "E92000001"})
+
+				calls := neoMock.ReadWithParamsCalls()
+				So(calls, ShouldHaveLength, 1)
+				So(calls[0].Query, ShouldEqual, "MATCH (n:`_generic_hierarchy_node_cl`) WHERE n.code IN {codes} RETURN id(n), n.code")
+				So(calls[0].Params, ShouldResemble, map[string]interface{}{"codes": []string{"K02000001", "E92000001"}})
+			})
+		})
+
+		Convey("When GetGenericHierarchyAncestriesIDs is called", func() {
+			ids, err := db.GetGenericHierarchyAncestriesIDs(context.Background(), 1, "cl", []string{"E08000001"})
+
+			Convey("Then the codes of the ancestors are returned by node ID", func() {
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, map[string]string{"12": "K02000001", "7": "E92000001"})
+
+				calls := neoMock.ReadWithParamsCalls()
+				So(calls, ShouldHaveLength, 1)
+				So(calls[0].Query, ShouldEqual, "MATCH (n:`_generic_hierarchy_node_cl`)-[:hasParent*]->(p) WHERE n.code IN {codes} RETURN DISTINCT id(p), p.code")
+			})
+		})
+
+		Convey("When GetGenericHierarchyNodeIDs is called without codes", func() {
+			ids, err := db.GetGenericHierarchyNodeIDs(context.Background(), 1, "cl", []string{})
+
+			Convey("Then an empty map is returned without querying the database", func() {
+				So(err, ShouldBeNil)
+				So(ids, ShouldBeEmpty)
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestStore_GetHierarchyNodeIDs(t *testing.T) {
+	Convey("Given a database containing an instance hierarchy", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{[]interface{}{int64(3), int64(5)}}})
+			},
+		}
+
+		db := &Neo4j{neoMock, 5, 30}
+
+		Convey("When GetHierarchyNodeIDs is called", func() {
+			ids, err := db.GetHierarchyNodeIDs(context.Background(), 1, instanceID, dimensionName)
+
+			Convey("Then the node IDs are returned as the keys of the map", func() {
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, map[string]string{"3": "", "5": ""})
+				So(neoMock.ReadCalls()[0].Query, ShouldEqual, "MATCH (n:`_hierarchy_node_instanceID_dimensionName`) RETURN collect(id(n))")
+			})
+		})
+	})
+}
+
+func TestStore_CloneFromIDs(t *testing.T) {
+	Convey("Given a database with a generic hierarchy", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{[]interface{}{"label_cy"}}})
+			},
+			ExecFunc: func(query string, params map[string]interface{}) (bolt.Result, error) {
+				return nil, nil
+			},
+		}
+
+		db := &Neo4j{neoMock, 5, 30}
+		ids := map[string]string{"12": "K02000001", "7": "E92000001"}
+
+		Convey("When CloneNodesFromIDs is called", func() {
+			err := db.CloneNodesFromIDs(context.Background(), 1, instanceID, "cl", dimensionName, ids, true)
+
+			Convey("Then the nodes with the provided IDs are cloned with their localised labels", func() {
+				So(err, ShouldBeNil)
+				calls := neoMock.ExecCalls()
+				So(calls, ShouldHaveLength, 1)
+				So(calls[0].Query, ShouldEqual, "MATCH (n:`_generic_hierarchy_node_cl`) WHERE id(n) IN {ids} "+
+					"MERGE (h:`_hierarchy_node_instanceID_dimensionName` { code:n.code }) "+
+					"ON CREATE SET h.label = n.label, h.code_list = {code_list}, h.hasData = {has_data} "+
+					"ON MATCH SET h.hasData = h.hasData OR {has_data} SET h.`label_cy` = n.`label_cy` MERGE (h)-[:clone_of]->(n)")
+				So(calls[0].Params, ShouldResemble, map[string]interface{}{
+					"ids":       []int64{7, 12},
+					"code_list": "cl",
+					"has_data":  true,
+				})
+			})
+		})
+
+		Convey("When CloneRelationshipsFromIDs is called", func() {
+			err := db.CloneRelationshipsFromIDs(context.Background(), 1, instanceID, dimensionName, ids)
+
+			Convey("Then the relationships of the nodes with the provided IDs are cloned", func() {
+				So(err, ShouldBeNil)
+				calls := neoMock.ExecCalls()
+				So(calls, ShouldHaveLength, 1)
+				So(calls[0].Query, ShouldEqual, "MATCH (n)-[:hasParent]->(p) WHERE id(n) IN {ids} "+
+					"MATCH (h:`_hierarchy_node_instanceID_dimensionName`)-[:clone_of]->(n), "+
+					"(hp:`_hierarchy_node_instanceID_dimensionName`)-[:clone_of]->(p) MERGE (h)-[:hasParent]->(hp)")
+				So(calls[0].Params, ShouldResemble, map[string]interface{}{"ids": []int64{7, 12}})
+			})
+		})
+
+		Convey("When CreateHasCodeEdges is called", func() {
+			err := db.CreateHasCodeEdges(context.Background(), 1, "cl", ids)
+
+			Convey("Then each node is linked to its code", func() {
+				So(err, ShouldBeNil)
+				calls := neoMock.ExecCalls()
+				So(calls, ShouldHaveLength, 1)
+				So(calls[0].Params, ShouldResemble, map[string]interface{}{"nodes": []interface{}{
+					map[string]interface{}{"id": int64(7), "code": "E92000001"},
+					map[string]interface{}{"id": int64(12), "code": "K02000001"},
+				}})
+			})
+		})
+
+		Convey("When CloneOrderFromIDs is called", func() {
+			err := db.CloneOrderFromIDs(context.Background(), "cl", ids)
+
+			Convey("Then the order of the codes in the code list is copied to the clones", func() {
+				So(err, ShouldBeNil)
+				calls := neoMock.ExecCalls()
+				So(calls, ShouldHaveLength, 1)
+				So(calls[0].Query, ShouldEqual, "MATCH (n)-[:hasCode]->(:_code)-[r:usedBy]->(:`_code_list_cl`) "+
+					"WHERE id(n) IN {ids} AND exists(r.order) WITH n, min(r.order) AS order MATCH (h)-[:clone_of]->(n) SET h.order = order")
+			})
+		})
+
+		Convey("When RemoveCloneEdgesFromSourceIDs and SetNumberOfChildrenFromIDs are called", func() {
+			err := db.RemoveCloneEdgesFromSourceIDs(context.Background(), 1, ids)
+			So(err, ShouldBeNil)
+			err = db.SetNumberOfChildrenFromIDs(context.Background(), 1, ids)
+			So(err, ShouldBeNil)
+
+			Convey("Then the queries are executed for the provided IDs", func() {
+				calls := neoMock.ExecCalls()
+				So(calls, ShouldHaveLength, 2)
+				So(calls[0].Query, ShouldEqual, "MATCH (n)-[r:clone_of]->() WHERE id(n) IN {ids} DELETE r")
+				So(calls[0].Params, ShouldResemble, map[string]interface{}{"ids": []int64{7, 12}})
+				So(calls[1].Query, ShouldEqual, "MATCH (n) WHERE id(n) IN {ids} SET n.numberOfChildren = size((n)<-[:hasParent]-())")
+			})
+		})
+
+		Convey("When the functions are called without IDs", func() {
+			So(db.CloneNodesFromIDs(context.Background(), 1, instanceID, "cl", dimensionName, nil, true), ShouldBeNil)
+			So(db.CloneRelationshipsFromIDs(context.Background(), 1, instanceID, dimensionName, nil), ShouldBeNil)
+			So(db.SetNumberOfChildrenFromIDs(context.Background(), 1, nil), ShouldBeNil)
+
+			Convey("Then the database is not called", func() {
+				So(neoMock.ExecCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When a function is called with an invalid ID", func() {
+			err := db.SetNumberOfChildrenFromIDs(context.Background(), 1, map[string]string{"abc": ""})
+
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(neoMock.ExecCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given a database that fails to execute queries", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ExecFunc: func(query string, params map[string]interface{}) (bolt.Result, error) {
+				return nil, errExec
+			},
+		}
+
+		db := &Neo4j{neoMock, 5, 30}
+
+		Convey("When RemoveCloneEdges is called", func() {
+			err := db.RemoveCloneEdges(context.Background(), 1, instanceID, dimensionName)
+
+			Convey("Then the error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(neoMock.ExecCalls()[0].Query, ShouldEqual, "MATCH (:`_hierarchy_node_instanceID_dimensionName`)-[r:clone_of]->() DELETE r")
+			})
+		})
+	})
+}
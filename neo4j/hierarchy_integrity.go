@@ -0,0 +1,56 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// CheckGenericHierarchy checks the generic hierarchy of a code list for cycles, nodes with multiple parents,
+// orphan nodes, codes missing from the code list edition and numberOfChildren mismatches
+func (n *Neo4j) CheckGenericHierarchy(ctx context.Context, codeListID, edition string) (*models.HierarchyIntegrityReport, error) {
+	log.Info(ctx, "checking generic hierarchy integrity", log.Data{"code_list_id": codeListID, "edition": edition})
+
+	return models.CheckGenericHierarchy(n.readHierarchyIntegrity, codeListID, edition)
+}
+
+// CheckInstanceHierarchy checks the hierarchy of an instance dimension for cycles, nodes with multiple parents,
+// orphan nodes, codes missing from the code list edition and numberOfChildren mismatches
+func (n *Neo4j) CheckInstanceHierarchy(ctx context.Context, instanceID, dimension, edition string) (*models.HierarchyIntegrityReport, error) {
+	log.Info(ctx, "checking instance hierarchy integrity", log.Data{"instance_id": instanceID, "dimension_name": dimension, "edition": edition})
+
+	codeListID, err := n.GetHierarchyCodelist(ctx, instanceID, dimension)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.CheckInstanceHierarchy(n.readHierarchyIntegrity, instanceID, dimension, codeListID, edition)
+}
+
+// readHierarchyIntegrity reads the hierarchy nodes with the provided label, the relationships between them and
+// the codes of the code list edition
+func (n *Neo4j) readHierarchyIntegrity(nodeLabel, codeListID, edition string) ([]models.HierarchyIntegrityNode, []models.HierarchyEdge, []string, error) {
+	nodes := make([]models.HierarchyIntegrityNode, 0)
+	stmt := fmt.Sprintf(query.GetHierarchyIntegrityNodes, nodeLabel)
+	if err := n.Read(stmt, mapper.HierarchyIntegrityNodes(&nodes), false); err != nil {
+		return nil, nil, nil, err
+	}
+
+	edges := make([]models.HierarchyEdge, 0)
+	stmt = fmt.Sprintf(query.GetHierarchyEdges, nodeLabel, nodeLabel)
+	if err := n.Read(stmt, mapper.HierarchyEdges(&edges), false); err != nil && err != driver.ErrNotFound {
+		return nil, nil, nil, err
+	}
+
+	codes, err := n.readSortedStringList(fmt.Sprintf(query.GetCodeListCodes, codeListID, edition))
+	if err != nil && err != driver.ErrNotFound {
+		return nil, nil, nil, err
+	}
+
+	return nodes, edges, codes, nil
+}
@@ -0,0 +1,107 @@
+package neo4j
+
+import (
+	"context"
+	"testing"
+
+	graph "github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/internal"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	boltstructures "github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNeo4j_CheckInstanceHierarchy(t *testing.T) {
+	Convey("Given a database containing an instance hierarchy with a cycle", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				switch query {
+				case "MATCH (i:`_hierarchy_node_123_geography`) RETURN i LIMIT 1":
+					return mapp(&mapper.Result{Data: []interface{}{boltstructures.Node{
+						Properties: map[string]interface{}{"code_list": "mmm"},
+					}}})
+				case "MATCH (n:`_hierarchy_node_123_geography`) RETURN n.code, n.numberOfChildren":
+					mapp(&mapper.Result{Data: []interface{}{"a", int64(1)}})
+					mapp(&mapper.Result{Data: []interface{}{"b", int64(1)}})
+					return mapp(&mapper.Result{Data: []interface{}{"c", nil}})
+				case "MATCH (n:`_hierarchy_node_123_geography`)-[:hasParent]->(p:`_hierarchy_node_123_geography`) RETURN n.code, p.code":
+					mapp(&mapper.Result{Data: []interface{}{"a", "b"}})
+					return mapp(&mapper.Result{Data: []interface{}{"b", "a"}})
+				case "MATCH (c:_code)-[:usedBy]->(cl:`_code_list_mmm`) WHERE cl.edition = \"one-off\" RETURN collect(DISTINCT c.value)":
+					return mapp(&mapper.Result{Data: []interface{}{[]interface{}{"a", "b"}}})
+				}
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When CheckInstanceHierarchy is called", func() {
+			report, err := db.CheckInstanceHierarchy(context.Background(), "123", "geography", "one-off")
+
+			Convey("Then the expected report is returned", func() {
+				So(err, ShouldBeNil)
+				So(report, ShouldResemble, &models.HierarchyIntegrityReport{
+					CodeListID:           "mmm",
+					CodeListEdition:      "one-off",
+					InstanceID:           "123",
+					Dimension:            "geography",
+					Cycles:               []string{"a", "b"},
+					MultipleParents:      []string{},
+					Orphans:              []string{"c"},
+					MissingCodes:         []string{"c"},
+					ChildCountMismatches: []string{},
+				})
+			})
+		})
+	})
+
+	Convey("Given a database without the instance hierarchy", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When CheckInstanceHierarchy is called", func() {
+			report, err := db.CheckInstanceHierarchy(context.Background(), "123", "geography", "one-off")
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(report, ShouldBeNil)
+				So(err, ShouldEqual, graph.ErrNotFound)
+			})
+		})
+	})
+}
+
+func TestNeo4j_CheckGenericHierarchy(t *testing.T) {
+	Convey("Given a database containing a generic hierarchy without relationships", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				switch query {
+				case "MATCH (n:`_generic_hierarchy_node_mmm`) RETURN n.code, n.numberOfChildren":
+					return mapp(&mapper.Result{Data: []interface{}{"a", nil}})
+				case "MATCH (c:_code)-[:usedBy]->(cl:`_code_list_mmm`) WHERE cl.edition = \"one-off\" RETURN collect(DISTINCT c.value)":
+					return mapp(&mapper.Result{Data: []interface{}{[]interface{}{"a"}}})
+				}
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When CheckGenericHierarchy is called", func() {
+			report, err := db.CheckGenericHierarchy(context.Background(), "mmm", "one-off")
+
+			Convey("Then a valid report is returned", func() {
+				So(err, ShouldBeNil)
+				So(report.CodeListID, ShouldEqual, "mmm")
+				So(report.CodeListEdition, ShouldEqual, "one-off")
+				So(report.IsValid(), ShouldBeTrue)
+			})
+		})
+	})
+}
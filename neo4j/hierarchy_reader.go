@@ -9,6 +9,7 @@ import (
 	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
 	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/pkg/errors"
 )
 
 // Type check to ensure that Neo4j implements the driver.Hierarchy interface
@@ -33,26 +34,108 @@ func (n *Neo4j) GetHierarchyCodelist(ctx context.Context, instanceID, dimension
 }
 
 // GetHierarchyRoot returns the upper-most node for a given hierarchy
-func (n *Neo4j) GetHierarchyRoot(ctx context.Context, instanceID, dimension string) (*models.HierarchyResponse, error) {
+func (n *Neo4j) GetHierarchyRoot(ctx context.Context, instanceID, dimension string, opts ...driver.ReadOption) (*models.HierarchyResponse, error) {
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.SingleRoot {
+		roots, err := n.getHierarchyRoots(ctx, instanceID, dimension, options.Language)
+		if err != nil {
+			return nil, err
+		}
+		if len(roots) > 1 {
+			return nil, newErrMultipleRoots(roots)
+		}
+	}
+
 	neoStmt := fmt.Sprintf(query.GetHierarchyRoot, instanceID, dimension)
-	return n.queryResponse(ctx, instanceID, dimension, neoStmt, nil)
+	return n.queryResponse(ctx, instanceID, dimension, neoStmt, nil, options)
+}
+
+// GetHierarchyRoots returns all the nodes without a parent for a given hierarchy, ordered by label
+func (n *Neo4j) GetHierarchyRoots(ctx context.Context, instanceID, dimension string, opts ...driver.ReadOption) ([]*models.HierarchyElement, error) {
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.getHierarchyRoots(ctx, instanceID, dimension, options.Language)
+}
+
+func (n *Neo4j) getHierarchyRoots(ctx context.Context, instanceID, dimension, language string) ([]*models.HierarchyElement, error) {
+	neoStmt := fmt.Sprintf(query.GetHierarchyRoots, instanceID, dimension)
+	return n.queryElements(ctx, instanceID, dimension, neoStmt, neoArgMap{}, language)
+}
+
+// newErrMultipleRoots returns an ErrMultipleRoots for the provided roots
+func newErrMultipleRoots(roots []*models.HierarchyElement) driver.ErrMultipleRoots {
+	codes := make([]string, 0, len(roots))
+	for _, root := range roots {
+		codes = append(codes, root.ID)
+	}
+	return driver.ErrMultipleRoots{Codes: codes}
 }
 
 // GetHierarchyElement gets a node in a given hierarchy for a given code
-func (n *Neo4j) GetHierarchyElement(ctx context.Context, instanceID, dimension, code string) (res *models.HierarchyResponse, err error) {
+func (n *Neo4j) GetHierarchyElement(ctx context.Context, instanceID, dimension, code string, opts ...driver.ReadOption) (res *models.HierarchyResponse, err error) {
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	neoStmt := fmt.Sprintf(query.GetHierarchyElement, instanceID, dimension)
 
-	if res, err = n.queryResponse(ctx, instanceID, dimension, neoStmt, neoArgMap{"code": code}); err != nil {
+	if res, err = n.queryResponse(ctx, instanceID, dimension, neoStmt, neoArgMap{"code": code}, options); err != nil {
 		return
 	}
 
-	if res.Breadcrumbs, err = n.getAncestry(ctx, instanceID, dimension, code); err != nil {
+	if res.Breadcrumbs, err = n.getAncestry(ctx, instanceID, dimension, code, options.Language); err != nil {
 		return
 	}
 
 	return
 }
 
+// SearchHierarchy returns up to limit nodes of a given hierarchy whose label in the requested language contains
+// the provided text, ignoring case, with their breadcrumbs. Nodes whose label starts with the text are returned first.
+// Labels not available in the language fall back to the default label.
+func (n *Neo4j) SearchHierarchy(ctx context.Context, instanceID, dimension, text string, limit int, opts ...driver.ReadOption) ([]*models.HierarchyElement, error) {
+	if len(text) == 0 {
+		return nil, errors.New("search text is required but was empty")
+	}
+	if limit < 1 {
+		return nil, errors.New("search limit must be greater than zero")
+	}
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	label := query.SearchLabel
+	if !options.IsDefaultLanguage() {
+		label = fmt.Sprintf(query.SearchLocalisedLabel, options.LabelProperty())
+	}
+	neoStmt := fmt.Sprintf(query.SearchHierarchy, instanceID, dimension, label)
+
+	elements, err := n.queryElements(ctx, instanceID, dimension, neoStmt, neoArgMap{"text": text, "limit": limit}, options.Language)
+	if err != nil {
+		if err == driver.ErrNotFound {
+			return []*models.HierarchyElement{}, nil
+		}
+		return nil, err
+	}
+
+	for _, element := range elements {
+		if element.Breadcrumbs, err = n.getAncestry(ctx, instanceID, dimension, element.ID, options.Language); err != nil && err != driver.ErrNotFound {
+			return nil, err
+		}
+	}
+
+	return elements, nil
+}
+
 // HierarchyExists returns true if the hierarchy exists
 func (n *Neo4j) HierarchyExists(ctx context.Context, instanceID, dimension string) (hierarchyExists bool, err error) {
 	neoStmt := fmt.Sprintf(query.HierarchyExists, instanceID, dimension)
@@ -65,7 +148,7 @@ func (n *Neo4j) HierarchyExists(ctx context.Context, instanceID, dimension strin
 
 	var vertices []*models.HierarchyElement
 
-	if vertices, err = n.queryElements(ctx, instanceID, dimension, neoStmt, neoArgMap{}); err != nil {
+	if vertices, err = n.queryElements(ctx, instanceID, dimension, neoStmt, neoArgMap{}, driver.DefaultLanguage); err != nil {
 		if err == driver.ErrNotFound {
 			hierarchyExists = false
 			return hierarchyExists, nil
@@ -90,47 +173,73 @@ func (n *Neo4j) HierarchyExists(ctx context.Context, instanceID, dimension strin
 	return hierarchyExists, nil
 }
 
-// queryResponse performs DB query (neoStmt, neoArgs) returning Response (should be singular)
-func (n *Neo4j) queryResponse(ctx context.Context, instanceID, dimension string, neoStmt string, neoArgs neoArgMap) (*models.HierarchyResponse, error) {
+// queryResponse performs DB query (neoStmt, neoArgs) returning Response (should be singular), with labels in the language
+// and the page of children requested by the provided options
+func (n *Neo4j) queryResponse(ctx context.Context, instanceID, dimension string, neoStmt string, neoArgs neoArgMap, options *driver.ReadOptions) (*models.HierarchyResponse, error) {
 	logData := log.Data{"statement": neoStmt, "neo_args": neoArgs}
 	log.Info(ctx, "QueryResponse executing get query", logData)
 
 	res := &models.HierarchyResponse{}
 	var err error
 
-	if err = n.ReadWithParams(neoStmt, neoArgs, mapper.Hierarchy(res), false); err != nil {
+	if err = n.ReadWithParams(neoStmt, neoArgs, mapper.Hierarchy(res, options.Language), false); err != nil {
 		return nil, err
 	}
 
-	if res.Children, err = n.getChildren(ctx, instanceID, dimension, res.ID); err != nil && err != driver.ErrNotFound {
+	if res.Children, err = n.getChildren(ctx, instanceID, dimension, res.ID, options); err != nil && err != driver.ErrNotFound {
 		return nil, err
 	}
 
 	return res, nil
 }
 
-func (n *Neo4j) getChildren(ctx context.Context, instanceID, dimension, code string) ([]*models.HierarchyElement, error) {
-	log.Info(ctx, "get children", log.Data{"instance": instanceID, "dimension": dimension, "code": code})
-	neoStmt := fmt.Sprintf(query.GetChildren, instanceID, dimension)
+// getChildren retrieves the children of this code, sorted and paginated according to the provided options
+func (n *Neo4j) getChildren(ctx context.Context, instanceID, dimension, code string, options *driver.ReadOptions) ([]*models.HierarchyElement, error) {
+	log.Info(ctx, "get children", log.Data{"instance": instanceID, "dimension": dimension, "code": code,
+		"offset": options.Offset, "limit": options.Limit, "sort": options.Sort})
+
+	orderBy := query.ChildrenByOrder
+	switch options.Sort {
+	case driver.SortByLabel:
+		orderBy = query.ChildrenByLabel
+		if !options.IsDefaultLanguage() {
+			orderBy = fmt.Sprintf(query.ChildrenByLocalisedLabel, options.LabelProperty())
+		}
+	case driver.SortByCode:
+		orderBy = query.ChildrenByCode
+	}
 
-	return n.queryElements(ctx, instanceID, dimension, neoStmt, neoArgMap{"code": code})
+	var page string
+	neoArgs := neoArgMap{"code": code}
+	if options.Offset > 0 {
+		page += query.ChildrenSkip
+		neoArgs["offset"] = options.Offset
+	}
+	if options.Limit > 0 {
+		page += query.ChildrenLimit
+		neoArgs["limit"] = options.Limit
+	}
+
+	neoStmt := fmt.Sprintf(query.GetChildren, instanceID, dimension, orderBy, page)
+
+	return n.queryElements(ctx, instanceID, dimension, neoStmt, neoArgs, options.Language)
 }
 
 // getAncestry retrieves a list of ancestors for this code - as breadcrumbs (ordered, nearest first)
-func (n *Neo4j) getAncestry(ctx context.Context, instanceID, dimension, code string) ([]*models.HierarchyElement, error) {
+func (n *Neo4j) getAncestry(ctx context.Context, instanceID, dimension, code, language string) ([]*models.HierarchyElement, error) {
 	log.Info(ctx, "get ancestry", log.Data{"instance_id": instanceID, "dimension": dimension, "code": code})
 	neoStmt := fmt.Sprintf(query.GetAncestry, instanceID, dimension)
 
-	return n.queryElements(ctx, instanceID, dimension, neoStmt, neoArgMap{"code": code})
+	return n.queryElements(ctx, instanceID, dimension, neoStmt, neoArgMap{"code": code}, language)
 }
 
-// queryElements returns a list of models.Elements from the database
-func (n *Neo4j) queryElements(ctx context.Context, instanceID, dimension, neoStmt string, neoArgs neoArgMap) ([]*models.HierarchyElement, error) {
+// queryElements returns a list of models.Elements from the database, with labels in the provided language
+func (n *Neo4j) queryElements(ctx context.Context, instanceID, dimension, neoStmt string, neoArgs neoArgMap, language string) ([]*models.HierarchyElement, error) {
 	logData := log.Data{"db_statement": neoStmt, "db_args": neoArgs}
 	log.Info(ctx, "QueryElements: executing get query", logData)
 
 	res := &mapper.HierarchyElements{}
-	if err := n.ReadWithParams(neoStmt, neoArgs, mapper.HierarchyElement(res), false); err != nil {
+	if err := n.ReadWithParams(neoStmt, neoArgs, mapper.HierarchyElement(res, language), false); err != nil {
 		return nil, err
 	}
 
@@ -156,22 +265,87 @@ func (n *Neo4j) CountNodes(ctx context.Context, instanceID, dimensionName string
 	return n.Count(q)
 }
 
-// GetCodesWithData not implemented by Neo4j (new hierarchy build algorithm)
+// GetCodesWithData returns the codes of the options of an instance dimension, which are the hierarchy nodes with data
 func (n *Neo4j) GetCodesWithData(ctx context.Context, attempt int, instanceID, dimensionName string) (codes []string, err error) {
-	return []string{}, driver.ErrNotImplemented
+	q := fmt.Sprintf(query.GetCodesWithData, instanceID, dimensionName)
+
+	logData := log.Data{
+		"instance_id":    instanceID,
+		"dimension_name": dimensionName,
+		"query":          q,
+	}
+
+	log.Info(ctx, "getting instance dimension codes that have data", logData)
+
+	if codes, err = n.readSortedStringList(q); err != nil {
+		if finalErr := n.checkAttempts(err, q, attempt); finalErr != nil {
+			return nil, finalErr
+		}
+
+		return n.GetCodesWithData(ctx, attempt+1, instanceID, dimensionName)
+	}
+
+	return codes, nil
 }
 
-// GetGenericHierarchyNodeIDs not implemented by Neo4j (new hierarchy build algorithm)
+// GetGenericHierarchyNodeIDs returns the codes of the generic hierarchy nodes which have a code in the provided list, by node ID
 func (n *Neo4j) GetGenericHierarchyNodeIDs(ctx context.Context, attempt int, codeListID string, codes []string) (nodeIDs map[string]string, err error) {
-	return map[string]string{}, driver.ErrNotImplemented
+	return n.getGenericHierarchyNodeIDs(ctx, attempt, fmt.Sprintf(query.GetGenericHierarchyNodeIDs, codeListID), codes)
 }
 
-// GetGenericHierarchyAncestriesIDs not implemented by Neo4j (new hierarchy build algorithm)
+// GetGenericHierarchyAncestriesIDs returns the codes of the ancestors (parents, grandparents, etc) of the generic hierarchy
+// nodes which have a code in the provided list, by node ID
 func (n *Neo4j) GetGenericHierarchyAncestriesIDs(ctx context.Context, attempt int, codeListID string, codes []string) (nodeIDs map[string]string, err error) {
-	return map[string]string{}, driver.ErrNotImplemented
+	return n.getGenericHierarchyNodeIDs(ctx, attempt, fmt.Sprintf(query.GetGenericHierarchyAncestryIDs, codeListID), codes)
+}
+
+func (n *Neo4j) getGenericHierarchyNodeIDs(ctx context.Context, attempt int, q string, codes []string) (map[string]string, error) {
+	logData := log.Data{
+		"num_codes": len(codes),
+		"query":     q,
+	}
+
+	log.Info(ctx, "getting generic hierarchy node ids for the provided codes", logData)
+
+	nodeIDs := make(map[string]string)
+	if len(codes) == 0 {
+		return nodeIDs, nil
+	}
+
+	if err := n.ReadWithParams(q, map[string]interface{}{"codes": codes}, mapper.NodeIDCodes(nodeIDs), false); err != nil {
+		if err == driver.ErrNotFound {
+			return nodeIDs, nil
+		}
+		if finalErr := n.checkAttempts(err, q, attempt); finalErr != nil {
+			return nil, finalErr
+		}
+
+		return n.getGenericHierarchyNodeIDs(ctx, attempt+1, q, codes)
+	}
+
+	return nodeIDs, nil
 }
 
-// GetHierarchyNodeIDs not implemented by Neo4j (new hierarchy build algorithm)
+// GetHierarchyNodeIDs returns the IDs of the nodes of an instance hierarchy, as the keys of the returned map
 func (n *Neo4j) GetHierarchyNodeIDs(ctx context.Context, attempt int, instanceID, dimensionName string) (ids map[string]string, err error) {
-	return map[string]string{}, driver.ErrNotImplemented
+	q := fmt.Sprintf(query.GetHierarchyNodeIDs, instanceID, dimensionName)
+
+	logData := log.Data{
+		"instance_id":    instanceID,
+		"dimension_name": dimensionName,
+		"query":          q,
+	}
+
+	log.Info(ctx, "getting ids of cloned hierarchy nodes", logData)
+
+	ids = make(map[string]string)
+	if err := n.Read(q, mapper.NodeIDs(ids), true); err != nil {
+		if finalErr := n.checkAttempts(err, q, attempt); finalErr != nil {
+			return nil, finalErr
+		}
+
+		return n.GetHierarchyNodeIDs(ctx, attempt+1, instanceID, dimensionName)
+	}
+
+	return ids, nil
 }
@@ -0,0 +1,126 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// SyncInstanceHierarchy compares the hierarchy of an instance dimension with the generic hierarchy it was cloned
+// from, by code, and applies the label, parent and order changes in place. hasData is preserved and
+// numberOfChildren is recomputed if any node has been reparented.
+func (n *Neo4j) SyncInstanceHierarchy(ctx context.Context, instanceID, dimension string) (*models.HierarchySyncReport, error) {
+	logData := log.Data{"instance_id": instanceID, "dimension_name": dimension}
+
+	codeListID, err := n.GetHierarchyCodelist(ctx, instanceID, dimension)
+	if err != nil {
+		return nil, err
+	}
+	logData["code_list_id"] = codeListID
+
+	generic := make([]models.HierarchySyncNode, 0)
+	stmt := fmt.Sprintf(query.GetGenericHierarchySyncNodes, codeListID, codeListID, codeListID)
+	if err := n.Read(stmt, mapper.HierarchySyncNodes(&generic), false); err != nil && err != driver.ErrNotFound {
+		return nil, err
+	}
+
+	instance := make([]models.HierarchySyncNode, 0)
+	stmt = fmt.Sprintf(query.GetInstanceHierarchySyncNodes, instanceID, dimension, instanceID, dimension)
+	if err := n.Read(stmt, mapper.HierarchySyncNodes(&instance), false); err != nil {
+		return nil, err
+	}
+
+	plan := models.NewHierarchySyncPlan(generic, instance)
+	report := plan.Report()
+	report.CodeListID = codeListID
+	report.InstanceID = instanceID
+	report.Dimension = dimension
+
+	logData["report"] = report
+	if plan.IsEmpty() {
+		log.Info(ctx, "instance hierarchy already in line with the generic hierarchy", logData)
+		return report, nil
+	}
+	log.Info(ctx, "syncing instance hierarchy with the generic hierarchy", logData)
+
+	if len(plan.Relabel) > 0 {
+		stmt = fmt.Sprintf(query.SyncHierarchyLabels, instanceID, dimension)
+		if _, err := n.Exec(stmt, map[string]interface{}{"nodes": syncLabelsParam(plan.Relabel)}); err != nil {
+			log.Error(ctx, "failed to relabel instance hierarchy nodes", err, logData)
+			return nil, err
+		}
+	}
+
+	if len(plan.Reorder) > 0 {
+		stmt = fmt.Sprintf(query.SyncHierarchyOrders, instanceID, dimension)
+		if _, err := n.Exec(stmt, map[string]interface{}{"nodes": syncOrdersParam(plan.Reorder)}); err != nil {
+			log.Error(ctx, "failed to reorder instance hierarchy nodes", err, logData)
+			return nil, err
+		}
+	}
+
+	if len(plan.Reparent) > 0 {
+		stmt = fmt.Sprintf(query.SyncHierarchyParents, instanceID, dimension, instanceID, dimension)
+		if _, err := n.Exec(stmt, map[string]interface{}{"nodes": syncParentsParam(plan.Reparent)}); err != nil {
+			log.Error(ctx, "failed to reparent instance hierarchy nodes", err, logData)
+			return nil, err
+		}
+
+		if err := n.SetNumberOfChildren(ctx, 1, instanceID, dimension); err != nil {
+			log.Error(ctx, "failed to set number of children of instance hierarchy nodes", err, logData)
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func syncLabelsParam(nodes []models.HierarchySyncNode) []interface{} {
+	param := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		labels := make(map[string]interface{}, len(node.Labels))
+		for key, label := range node.Labels {
+			labels[key] = label
+		}
+		param = append(param, map[string]interface{}{
+			"code":   node.Code,
+			"labels": labels,
+		})
+	}
+	return param
+}
+
+func syncOrdersParam(nodes []models.HierarchySyncNode) []interface{} {
+	param := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		var order interface{}
+		if node.Order != nil {
+			order = *node.Order
+		}
+		param = append(param, map[string]interface{}{
+			"code":  node.Code,
+			"order": order,
+		})
+	}
+	return param
+}
+
+func syncParentsParam(nodes []models.HierarchySyncNode) []interface{} {
+	param := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		parents := make([]interface{}, 0, len(node.ParentCodes))
+		for _, parent := range node.ParentCodes {
+			parents = append(parents, parent)
+		}
+		param = append(param, map[string]interface{}{
+			"code":    node.Code,
+			"parents": parents,
+		})
+	}
+	return param
+}
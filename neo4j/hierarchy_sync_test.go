@@ -0,0 +1,107 @@
+package neo4j
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	graph "github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/internal"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	bolt "github.com/ONSdigital/golang-neo4j-bolt-driver"
+	boltstructures "github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNeo4j_SyncInstanceHierarchy(t *testing.T) {
+	node := func(code, label string) boltstructures.Node {
+		return boltstructures.Node{Properties: map[string]interface{}{"code": code, "label": label, "hasData": true}}
+	}
+
+	readFunc := func(query string, mapp mapper.ResultMapper, single bool) error {
+		switch query {
+		case "MATCH (i:`_hierarchy_node_123_geography`) RETURN i LIMIT 1":
+			return mapp(&mapper.Result{Data: []interface{}{boltstructures.Node{
+				Properties: map[string]interface{}{"code_list": "mmm"},
+			}}})
+		case "MATCH (n:`_generic_hierarchy_node_mmm`) OPTIONAL MATCH (n)-[:hasParent]->(p:`_generic_hierarchy_node_mmm`) " +
+			"OPTIONAL MATCH (:_code {value: n.code})-[r:usedBy]->(:`_code_list_mmm`) RETURN n, collect(DISTINCT p.code), min(r.order)":
+			mapp(&mapper.Result{Data: []interface{}{node("root", "Root"), []interface{}{}, nil}})
+			mapp(&mapper.Result{Data: []interface{}{node("x", "X"), []interface{}{"root"}, int64(1)}})
+			return mapp(&mapper.Result{Data: []interface{}{node("a", "A renamed"), []interface{}{"x"}, int64(2)}})
+		case "MATCH (n:`_hierarchy_node_123_geography`) OPTIONAL MATCH (n)-[:hasParent]->(p:`_hierarchy_node_123_geography`) RETURN n, collect(p.code), n.order":
+			mapp(&mapper.Result{Data: []interface{}{node("root", "Root"), []interface{}{}, nil}})
+			mapp(&mapper.Result{Data: []interface{}{node("x", "X"), []interface{}{"root"}, int64(1)}})
+			return mapp(&mapper.Result{Data: []interface{}{node("a", "A"), []interface{}{"root"}, int64(2)}})
+		}
+		return graph.ErrNotFound
+	}
+
+	Convey("Given a database containing an instance hierarchy with a node relabelled and reparented in the generic hierarchy", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: readFunc,
+			ExecFunc: func(query string, params map[string]interface{}) (bolt.Result, error) {
+				return nil, nil
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When SyncInstanceHierarchy is called", func() {
+			report, err := db.SyncInstanceHierarchy(context.Background(), "123", "geography")
+
+			Convey("Then the expected report is returned", func() {
+				So(err, ShouldBeNil)
+				So(report, ShouldResemble, &models.HierarchySyncReport{
+					CodeListID: "mmm",
+					InstanceID: "123",
+					Dimension:  "geography",
+					Relabelled: []string{"a"},
+					Reparented: []string{"a"},
+					Reordered:  []string{},
+					Unresolved: []string{},
+				})
+			})
+
+			Convey("Then the node is relabelled, reparented and the number of children recomputed", func() {
+				calls := neoMock.ExecCalls()
+				So(calls, ShouldHaveLength, 3)
+				So(calls[0].Query, ShouldEqual, "UNWIND {nodes} AS node MATCH (n:`_hierarchy_node_123_geography` {code: node.code}) SET n += node.labels")
+				So(calls[0].Params, ShouldResemble, map[string]interface{}{"nodes": []interface{}{
+					map[string]interface{}{"code": "a", "labels": map[string]interface{}{"label": "A renamed"}},
+				}})
+				So(calls[1].Query, ShouldEqual, "UNWIND {nodes} AS node MATCH (n:`_hierarchy_node_123_geography` {code: node.code}) "+
+					"OPTIONAL MATCH (n)-[r:hasParent]->() DELETE r WITH DISTINCT n, node UNWIND node.parents AS parentCode "+
+					"MATCH (p:`_hierarchy_node_123_geography` {code: parentCode}) MERGE (n)-[:hasParent]->(p)")
+				So(calls[1].Params, ShouldResemble, map[string]interface{}{"nodes": []interface{}{
+					map[string]interface{}{"code": "a", "parents": []interface{}{"x"}},
+				}})
+				So(calls[2].Query, ShouldEqual, "MATCH (n:`_hierarchy_node_123_geography`) with n SET n.numberOfChildren = "+
+					"size((n)<-[:hasParent]-(:`_hierarchy_node_123_geography`))")
+			})
+		})
+	})
+
+	Convey("Given a database that fails to update the instance hierarchy", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: readFunc,
+			ExecFunc: func(query string, params map[string]interface{}) (bolt.Result, error) {
+				return nil, errors.New("exec failed")
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When SyncInstanceHierarchy is called", func() {
+			report, err := db.SyncInstanceHierarchy(context.Background(), "123", "geography")
+
+			Convey("Then the error is returned", func() {
+				So(report, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "exec failed")
+				So(neoMock.ExecCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
 
@@ -113,9 +115,14 @@ func TestStore_CreateInstanceHierarchyConstraints_NeoExecRetry(t *testing.T) {
 
 func TestStore_CloneNodes(t *testing.T) {
 
+	expectedKeysQuery := fmt.Sprintf(
+		"MATCH (n:`_generic_hierarchy_node_%s`) UNWIND keys(n) AS key WITH key WHERE key STARTS WITH 'label_' RETURN collect(DISTINCT key)",
+		codeListID,
+	)
+
 	expectedQuery := fmt.Sprintf(
 		"MATCH (n:`_generic_hierarchy_node_%s`) WITH n "+
-			"MERGE (:`_hierarchy_node_%s_%s` { code:n.code,label:n.label,code_list:{code_list}, hasData:false });",
+			"MERGE (h:`_hierarchy_node_%s_%s` { code:n.code,label:n.label,code_list:{code_list}, hasData:false });",
 		codeListID,
 		instanceID,
 		dimensionName,
@@ -123,6 +130,9 @@ func TestStore_CloneNodes(t *testing.T) {
 
 	Convey("Given a bolt connection", t, func() {
 		driver := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{[]interface{}{}}})
+			},
 			ExecFunc: func(q string, params map[string]interface{}) (bolt.Result, error) {
 				return &internal.ResultMock{}, nil
 			},
@@ -137,18 +147,59 @@ func TestStore_CloneNodes(t *testing.T) {
 				So(err, ShouldBeNil)
 			})
 
+			Convey("Then the labels in other languages are read from the generic hierarchy", func() {
+				So(len(driver.ReadCalls()), ShouldEqual, 1)
+				So(driver.ReadCalls()[0].Query, ShouldEqual, expectedKeysQuery)
+			})
+
 			Convey("Then db.Exec should be called once for the expected query", func() {
 				So(len(driver.ExecCalls()), ShouldEqual, 1)
 				So(driver.ExecCalls()[0].Query, ShouldEqual, expectedQuery)
 			})
 		})
 	})
+
+	Convey("Given a generic hierarchy with labels in other languages", t, func() {
+		driver := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{[]interface{}{"label_gd", "label_cy"}}})
+			},
+			ExecFunc: func(q string, params map[string]interface{}) (bolt.Result, error) {
+				return &internal.ResultMock{}, nil
+			},
+		}
+
+		db := &Neo4j{driver, 5, 30}
+
+		Convey("When CloneNodes is called", func() {
+			err := db.CloneNodes(context.Background(), 1, instanceID, codeListID, dimensionName)
+
+			Convey("Then the returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the labels in other languages are copied to the cloned nodes", func() {
+				So(len(driver.ExecCalls()), ShouldEqual, 1)
+				So(driver.ExecCalls()[0].Query, ShouldEqual, fmt.Sprintf(
+					"MATCH (n:`_generic_hierarchy_node_%s`) WITH n "+
+						"MERGE (h:`_hierarchy_node_%s_%s` { code:n.code,label:n.label,code_list:{code_list}, hasData:false })"+
+						" SET h.`label_cy` = n.`label_cy` SET h.`label_gd` = n.`label_gd`;",
+					codeListID,
+					instanceID,
+					dimensionName,
+				))
+			})
+		})
+	})
 }
 
 func TestStore_CloneNodes_NeoerrExec(t *testing.T) {
 
 	Convey("Given a bolt connection that returns an error", t, func() {
 		driver := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{[]interface{}{}}})
+			},
 			ExecFunc: func(q string, params map[string]interface{}) (bolt.Result, error) {
 				return nil, errExec
 			},
@@ -355,6 +406,56 @@ func TestStore_SetHasData_NeoErrExec(t *testing.T) {
 	})
 }
 
+func TestStore_SetRollupCounts(t *testing.T) {
+
+	expectedQuery := "MATCH (n:`_hierarchy_node_instanceID_dimensionName`) " +
+		"OPTIONAL MATCH (c:`_hierarchy_node_instanceID_dimensionName`)-[:hasParent*0..]->(n) " +
+		"OPTIONAL MATCH (d:`_instanceID_dimensionName` {value: c.code}) WITH n, collect(DISTINCT d) AS options " +
+		"SET n.numberOfOptions = size(options), n.numberOfObservations = reduce(total = 0, d IN options | total + size(()-[:isValueOf]->(d)))"
+
+	Convey("Given a bolt connection", t, func() {
+		driver := &internal.Neo4jDriverMock{
+			ExecFunc: func(q string, params map[string]interface{}) (bolt.Result, error) {
+				return &internal.ResultMock{}, nil
+			},
+		}
+
+		db := &Neo4j{driver, 5, 30}
+
+		Convey("When SetRollupCounts is called", func() {
+			err := db.SetRollupCounts(context.Background(), 1, instanceID, dimensionName)
+
+			Convey("Then the returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then db.Exec should be called once for the expected query", func() {
+				So(len(driver.ExecCalls()), ShouldEqual, 1)
+				So(driver.ExecCalls()[0].Query, ShouldEqual, expectedQuery)
+			})
+		})
+	})
+
+	Convey("Given a bolt connection that returns an error", t, func() {
+		driver := &internal.Neo4jDriverMock{
+			ExecFunc: func(q string, params map[string]interface{}) (bolt.Result, error) {
+				return nil, errExec
+			},
+		}
+
+		db := &Neo4j{driver, 5, 30}
+
+		Convey("When SetRollupCounts is called", func() {
+			err := db.SetRollupCounts(context.Background(), 1, instanceID, dimensionName)
+
+			Convey("Then the returned error should be that returned from the exec call", func() {
+				So(err, ShouldResemble, graph.ErrNonRetriable{WrappedErr: errExec})
+				So(len(driver.ExecCalls()), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
 func TestStore_MarkNodesToRemain(t *testing.T) {
 
 	expectedQuery := fmt.Sprintf("MATCH (parent:`_hierarchy_node_%s_%s`)<-[:hasParent*]-(child:`_hierarchy_node_%s_%s`) "+
@@ -687,3 +788,274 @@ func TestStore_HierarchyExists(t *testing.T) {
 		})
 	})
 }
+
+func TestStore_SearchHierarchy(t *testing.T) {
+
+	hierarchyNode := func(code, label string) *mapper.Result {
+		return &mapper.Result{Data: []interface{}{boltstructures.Node{
+			Properties: map[string]interface{}{"code": code, "label": label, "hasData": true, "numberOfChildren": int64(0)},
+		}}}
+	}
+
+	expectedSearchQuery := "MATCH (i:`_hierarchy_node_instanceID_dimensionName`) WITH i, i.label AS label WHERE toLower(label) CONTAINS toLower({text}) RETURN i " +
+		"ORDER BY CASE WHEN toLower(label) STARTS WITH toLower({text}) THEN 0 ELSE 1 END, label LIMIT {limit}"
+	expectedAncestryQuery := fmt.Sprintf(query.GetAncestry, instanceID, dimensionName)
+
+	Convey("Given a bolt connection that returns matching nodes and their ancestry", t, func() {
+		neoDriverMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(q string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				if q == expectedSearchQuery {
+					mapp(hierarchyNode("W06000015", "Cardiff"))
+					return mapp(hierarchyNode("E07000008", "Cambridge"))
+				}
+				if params["code"] == "W06000015" {
+					return mapp(hierarchyNode("W92000004", "Wales"))
+				}
+				return graph.ErrNotFound
+			},
+		}
+
+		db := &Neo4j{neoDriverMock, 5, 30}
+
+		Convey("When SearchHierarchy is called", func() {
+			elements, err := db.SearchHierarchy(context.Background(), instanceID, dimensionName, "CA", 10)
+
+			Convey("Then the returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the hierarchy is searched with the expected query and parameters", func() {
+				calls := neoDriverMock.ReadWithParamsCalls()
+				So(calls, ShouldHaveLength, 3)
+				So(calls[0].Query, ShouldEqual, expectedSearchQuery)
+				So(calls[0].Params, ShouldResemble, map[string]interface{}{"text": "CA", "limit": 10})
+				So(calls[1].Query, ShouldEqual, expectedAncestryQuery)
+				So(calls[2].Query, ShouldEqual, expectedAncestryQuery)
+			})
+
+			Convey("Then the matching nodes are returned with their breadcrumbs", func() {
+				So(elements, ShouldHaveLength, 2)
+				So(elements[0].ID, ShouldEqual, "W06000015")
+				So(elements[0].Breadcrumbs, ShouldResemble, []*models.HierarchyElement{{ID: "W92000004", Label: "Wales", HasData: true}})
+				So(elements[1].ID, ShouldEqual, "E07000008")
+				So(elements[1].Breadcrumbs, ShouldBeNil)
+			})
+		})
+
+		Convey("When SearchHierarchy is called with a language", func() {
+			_, err := db.SearchHierarchy(context.Background(), instanceID, dimensionName, "CA", 10, graph.WithLanguage("cy"))
+
+			Convey("Then the labels in the language are searched, falling back to the default label", func() {
+				So(err, ShouldBeNil)
+				So(neoDriverMock.ReadWithParamsCalls()[0].Query, ShouldEqual,
+					"MATCH (i:`_hierarchy_node_instanceID_dimensionName`) WITH i, coalesce(i.`label_cy`, i.label) AS label WHERE toLower(label) CONTAINS toLower({text}) RETURN i "+
+						"ORDER BY CASE WHEN toLower(label) STARTS WITH toLower({text}) THEN 0 ELSE 1 END, label LIMIT {limit}")
+			})
+		})
+	})
+
+	Convey("Given a bolt connection that returns no matching nodes", t, func() {
+		neoDriverMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(q string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
+
+		db := &Neo4j{neoDriverMock, 5, 30}
+
+		Convey("When SearchHierarchy is called", func() {
+			elements, err := db.SearchHierarchy(context.Background(), instanceID, dimensionName, "xyz", 10)
+
+			Convey("Then an empty list is returned", func() {
+				So(err, ShouldBeNil)
+				So(elements, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When SearchHierarchy is called with invalid parameters", func() {
+			_, errText := db.SearchHierarchy(context.Background(), instanceID, dimensionName, "", 10)
+			_, errLimit := db.SearchHierarchy(context.Background(), instanceID, dimensionName, "xyz", 0)
+
+			Convey("Then the expected errors are returned and the database is not queried", func() {
+				So(errText.Error(), ShouldEqual, "search text is required but was empty")
+				So(errLimit.Error(), ShouldEqual, "search limit must be greater than zero")
+				So(neoDriverMock.ReadWithParamsCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestStore_GetHierarchyElement_RollupCounts(t *testing.T) {
+
+	parent := &mapper.Result{Data: []interface{}{boltstructures.Node{
+		Properties: map[string]interface{}{"code": "E06000001", "label": "Hartlepool", "hasData": true, "numberOfChildren": int64(1),
+			"numberOfOptions": int64(2), "numberOfObservations": int64(1234)},
+	}}}
+	child := &mapper.Result{Data: []interface{}{boltstructures.Node{
+		Properties: map[string]interface{}{"code": "E05008942", "label": "De Bruce", "hasData": true, "numberOfChildren": int64(0)},
+	}}}
+
+	Convey("Given a bolt connection that returns a hierarchy node with rollup counts and a child without", t, func() {
+		neoDriverMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(q string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				if q == fmt.Sprintf(query.GetHierarchyElement, instanceID, dimensionName) {
+					return mapp(parent)
+				}
+				if strings.HasPrefix(q, "MATCH (i:`_hierarchy_node_instanceID_dimensionName` {code:{code}})<-[r:hasParent]-(child)") {
+					return mapp(child)
+				}
+				return nil
+			},
+		}
+
+		db := &Neo4j{neoDriverMock, 5, 30}
+
+		Convey("When GetHierarchyElement is called", func() {
+			res, err := db.GetHierarchyElement(context.Background(), instanceID, dimensionName, "E06000001")
+
+			Convey("Then the rollup counts are returned where set", func() {
+				So(err, ShouldBeNil)
+				So(*res.NoOfOptions, ShouldEqual, 2)
+				So(*res.NoOfObservations, ShouldEqual, 1234)
+				So(res.Children, ShouldHaveLength, 1)
+				So(res.Children[0].NoOfOptions, ShouldBeNil)
+				So(res.Children[0].NoOfObservations, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestStore_GetHierarchyElement_Paginated(t *testing.T) {
+
+	parent := &mapper.Result{Data: []interface{}{boltstructures.Node{
+		Properties: map[string]interface{}{"code": "E06000001", "label": "Hartlepool", "hasData": true, "numberOfChildren": int64(1000)},
+	}}}
+
+	Convey("Given a bolt connection that returns a hierarchy node", t, func() {
+		neoDriverMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(q string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				if q == fmt.Sprintf(query.GetHierarchyElement, instanceID, dimensionName) {
+					return mapp(parent)
+				}
+				return nil
+			},
+		}
+
+		db := &Neo4j{neoDriverMock, 5, 30}
+
+		Convey("When GetHierarchyElement is called with a page of children sorted by code", func() {
+			res, err := db.GetHierarchyElement(context.Background(), instanceID, dimensionName, "E06000001",
+				graph.WithOffset(100), graph.WithLimit(50), graph.WithSort(graph.SortByCode))
+
+			Convey("Then the returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the requested page of children is queried", func() {
+				calls := neoDriverMock.ReadWithParamsCalls()
+				So(calls, ShouldHaveLength, 3)
+				So(calls[1].Query, ShouldEqual, "MATCH (i:`_hierarchy_node_instanceID_dimensionName` {code:{code}})<-[r:hasParent]-(child) "+
+					"RETURN child ORDER BY child.code SKIP {offset} LIMIT {limit}")
+				So(calls[1].Params, ShouldResemble, map[string]interface{}{"code": "E06000001", "offset": 100, "limit": 50})
+			})
+
+			Convey("Then the number of children still reports the total", func() {
+				So(res.NoOfChildren, ShouldEqual, 1000)
+			})
+		})
+
+		Convey("When GetHierarchyElement is called without pagination", func() {
+			_, err := db.GetHierarchyElement(context.Background(), instanceID, dimensionName, "E06000001")
+
+			Convey("Then all children are queried, sorted by order and then by label", func() {
+				So(err, ShouldBeNil)
+				calls := neoDriverMock.ReadWithParamsCalls()
+				So(calls[1].Query, ShouldEqual, "MATCH (i:`_hierarchy_node_instanceID_dimensionName` {code:{code}})<-[r:hasParent]-(child) "+
+					"RETURN child ORDER BY child.order, child.label")
+				So(calls[1].Params, ShouldResemble, map[string]interface{}{"code": "E06000001"})
+			})
+		})
+
+		Convey("When GetHierarchyElement is called with children sorted by label in another language", func() {
+			_, err := db.GetHierarchyElement(context.Background(), instanceID, dimensionName, "E06000001",
+				graph.WithSort(graph.SortByLabel), graph.WithLanguage("cy"))
+
+			Convey("Then the children are sorted by their label in the language, falling back to the default label", func() {
+				So(err, ShouldBeNil)
+				calls := neoDriverMock.ReadWithParamsCalls()
+				So(calls[1].Query, ShouldEqual, "MATCH (i:`_hierarchy_node_instanceID_dimensionName` {code:{code}})<-[r:hasParent]-(child) "+
+					"RETURN child ORDER BY coalesce(child.`label_cy`, child.label)")
+			})
+		})
+
+		Convey("When GetHierarchyElement is called with an invalid offset", func() {
+			_, err := db.GetHierarchyElement(context.Background(), instanceID, dimensionName, "E06000001", graph.WithOffset(-1))
+
+			Convey("Then the expected error is returned and the database is not queried", func() {
+				So(err.Error(), ShouldEqual, "invalid offset -1")
+				So(neoDriverMock.ReadWithParamsCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestStore_GetHierarchyRoots(t *testing.T) {
+
+	root := func(code, label string) *mapper.Result {
+		return &mapper.Result{Data: []interface{}{boltstructures.Node{
+			Properties: map[string]interface{}{"code": code, "label": label, "hasData": true, "numberOfChildren": int64(2)},
+		}}}
+	}
+
+	expectedRootsQuery := "MATCH (i:`_hierarchy_node_instanceID_dimensionName`) WHERE NOT (i)-[:hasParent]->() RETURN i ORDER BY i.label"
+
+	Convey("Given a bolt connection with a hierarchy that has several roots", t, func() {
+		neoDriverMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(q string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				switch q {
+				case expectedRootsQuery:
+					mapp(root("K04000001", "England and Wales"))
+					return mapp(root("S92000003", "Scotland"))
+				case fmt.Sprintf(query.GetHierarchyRoot, instanceID, dimensionName):
+					return mapp(root("K04000001", "England and Wales"))
+				}
+				return nil
+			},
+		}
+
+		db := &Neo4j{neoDriverMock, 5, 30}
+
+		Convey("When GetHierarchyRoots is called", func() {
+			roots, err := db.GetHierarchyRoots(context.Background(), instanceID, dimensionName)
+
+			Convey("Then all the roots are returned", func() {
+				So(err, ShouldBeNil)
+				So(roots, ShouldResemble, []*models.HierarchyElement{
+					{ID: "K04000001", Label: "England and Wales", HasData: true, NoOfChildren: 2},
+					{ID: "S92000003", Label: "Scotland", HasData: true, NoOfChildren: 2},
+				})
+				So(neoDriverMock.ReadWithParamsCalls()[0].Query, ShouldEqual, expectedRootsQuery)
+			})
+		})
+
+		Convey("When GetHierarchyRoot is called", func() {
+			res, err := db.GetHierarchyRoot(context.Background(), instanceID, dimensionName)
+
+			Convey("Then the first root is returned", func() {
+				So(err, ShouldBeNil)
+				So(res.ID, ShouldEqual, "K04000001")
+			})
+		})
+
+		Convey("When GetHierarchyRoot is called requiring a single root", func() {
+			res, err := db.GetHierarchyRoot(context.Background(), instanceID, dimensionName, graph.WithSingleRoot())
+
+			Convey("Then ErrMultipleRoots is returned with the codes of the roots", func() {
+				So(res, ShouldBeNil)
+				So(err, ShouldResemble, graph.ErrMultipleRoots{Codes: []string{"K04000001", "S92000003"}})
+				So(errors.Is(err, graph.ErrMultipleFound), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "hierarchy has multiple roots: K04000001, S92000003")
+			})
+		})
+	})
+}
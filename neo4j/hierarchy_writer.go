@@ -3,8 +3,11 @@ package neo4j
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
 
-	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
 	"github.com/ONSdigital/log.go/v2/log"
 )
@@ -38,11 +41,22 @@ func (n *Neo4j) CreateInstanceHierarchyConstraints(ctx context.Context, attempt
 
 // CloneNodes copies nodes from a generic hierarchy and identifies them as instance specific hierarchy nodes
 func (n *Neo4j) CloneNodes(ctx context.Context, attempt int, instanceID, codeListID, dimensionName string) error {
+	// labels in languages other than the default are copied as well, if the generic hierarchy has any
+	setLabels, keysQuery, err := n.cloneLocalisedLabelsPart(codeListID)
+	if err != nil {
+		if finalErr := n.checkAttempts(err, keysQuery, attempt); finalErr != nil {
+			return finalErr
+		}
+
+		return n.CloneNodes(ctx, attempt+1, instanceID, codeListID, dimensionName)
+	}
+
 	q := fmt.Sprintf(
 		query.CloneHierarchyNodes,
 		codeListID,
 		instanceID,
 		dimensionName,
+		setLabels,
 	)
 
 	logData := log.Data{
@@ -157,6 +171,38 @@ func (n *Neo4j) SetHasData(ctx context.Context, attempt int, instanceID, dimensi
 	return nil
 }
 
+// SetRollupCounts stores on each node the number of dimension options matching its code and the codes of its
+// descendants, and the number of observations of those options
+func (n *Neo4j) SetRollupCounts(ctx context.Context, attempt int, instanceID, dimensionName string) error {
+	q := fmt.Sprintf(
+		query.SetRollupCounts,
+		instanceID,
+		dimensionName,
+		instanceID,
+		dimensionName,
+		instanceID,
+		dimensionName,
+	)
+
+	logData := log.Data{
+		"instance_id":    instanceID,
+		"dimension_name": dimensionName,
+		"query":          q,
+	}
+
+	log.Info(ctx, "setting rollup counts on the instance hierarchy", logData)
+
+	if _, err := n.Exec(q, nil); err != nil {
+		if finalErr := n.checkAttempts(err, q, attempt); finalErr != nil {
+			return finalErr
+		}
+
+		return n.SetRollupCounts(ctx, attempt+1, instanceID, dimensionName)
+	}
+
+	return nil
+}
+
 // MarkNodesToRemain traverses the instance hierarchy to identify nodes which
 // contain data or have children which contain data
 func (n *Neo4j) MarkNodesToRemain(ctx context.Context, attempt int, instanceID, dimensionName string) error {
@@ -239,30 +285,221 @@ func (n *Neo4j) RemoveRemainMarker(ctx context.Context, attempt int, instanceID,
 	return nil
 }
 
+// CloneNodesFromIDs copies the generic hierarchy nodes with the provided IDs as instance specific hierarchy nodes,
+// with a 'clone_of' relationship to the generic hierarchy node. Nodes that have already been cloned are kept,
+// and only flagged as having data if hasData is true.
 func (n *Neo4j) CloneNodesFromIDs(ctx context.Context, attempt int, instanceID, codeListID, dimensionName string, ids map[string]string, hasData bool) (err error) {
-	return driver.ErrNotImplemented
+	logData := log.Data{
+		"instance_id":    instanceID,
+		"code_list_id":   codeListID,
+		"dimension_name": dimensionName,
+		"has_data":       hasData,
+		"num_nodes":      len(ids),
+	}
+
+	log.Info(ctx, "cloning necessary nodes from the generic hierarchy", logData)
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	nodeIDs, err := nodeIDsParam(ids)
+	if err != nil {
+		return err
+	}
+
+	setLabels, keysQuery, err := n.cloneLocalisedLabelsPart(codeListID)
+	if err != nil {
+		if finalErr := n.checkAttempts(err, keysQuery, attempt); finalErr != nil {
+			return finalErr
+		}
+
+		return n.CloneNodesFromIDs(ctx, attempt+1, instanceID, codeListID, dimensionName, ids, hasData)
+	}
+
+	q := fmt.Sprintf(
+		query.CloneHierarchyNodesFromIDs,
+		codeListID,
+		instanceID,
+		dimensionName,
+		setLabels,
+	)
+	params := map[string]interface{}{
+		"ids":       nodeIDs,
+		"code_list": codeListID,
+		"has_data":  hasData,
+	}
+
+	return n.execWithRetries(attempt, q, params)
 }
 
+// CloneRelationshipsFromIDs copies the 'hasParent' relationships of the generic hierarchy nodes with the provided IDs
+// to their clones, where both the node and its parent have been cloned
 func (n *Neo4j) CloneRelationshipsFromIDs(ctx context.Context, attempt int, instanceID, dimensionName string, ids map[string]string) error {
-	return driver.ErrNotImplemented
+	log.Info(ctx, "cloning relationships from the generic hierarchy", log.Data{
+		"instance_id":    instanceID,
+		"dimension_name": dimensionName,
+		"num_ids":        len(ids),
+	})
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	nodeIDs, err := nodeIDsParam(ids)
+	if err != nil {
+		return err
+	}
+
+	q := fmt.Sprintf(
+		query.CloneHierarchyRelationshipsFromIDs,
+		instanceID,
+		dimensionName,
+		instanceID,
+		dimensionName,
+	)
+
+	return n.execWithRetries(attempt, q, map[string]interface{}{"ids": nodeIDs})
 }
 
+// CreateHasCodeEdges creates a 'hasCode' relationship from each generic hierarchy node in the provided map (node ID to code)
+// to the node of its code in the code list, if it does not exist already
 func (n *Neo4j) CreateHasCodeEdges(ctx context.Context, attempt int, codeListID string, codesById map[string]string) (err error) {
-	return driver.ErrNotImplemented
+	log.Info(ctx, "creating 'hasCode' edges between generic hierarchy nodes and their corresponding code nodes", log.Data{
+		"code_list_id": codeListID,
+		"num_codes":    len(codesById),
+	})
+
+	if len(codesById) == 0 {
+		return nil
+	}
+
+	nodeIDs, err := nodeIDsParam(codesById)
+	if err != nil {
+		return err
+	}
+
+	nodes := make([]interface{}, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		nodes = append(nodes, map[string]interface{}{
+			"id":   nodeID,
+			"code": codesById[strconv.FormatInt(nodeID, 10)],
+		})
+	}
+
+	q := fmt.Sprintf(query.CreateHasCodeEdges, codeListID)
+
+	return n.execWithRetries(attempt, q, map[string]interface{}{"nodes": nodes})
 }
 
+// CloneOrderFromIDs copies the order of the 'usedBy' relationship between the code of each generic hierarchy node with
+// the provided IDs and the code list to the clone of the node. The 'hasCode' relationships must have been created.
 func (n *Neo4j) CloneOrderFromIDs(ctx context.Context, codeListID string, ids map[string]string) (err error) {
-	return driver.ErrNotImplemented
+	log.Info(ctx, "cloning order property corresponding to the code of the generic hierarchy nodes", log.Data{
+		"code_list_id": codeListID,
+		"num_nodes":    len(ids),
+	})
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	nodeIDs, err := nodeIDsParam(ids)
+	if err != nil {
+		return err
+	}
+
+	q := fmt.Sprintf(query.CloneOrderFromIDs, codeListID)
+
+	return n.execWithRetries(1, q, map[string]interface{}{"ids": nodeIDs})
 }
 
+// RemoveCloneEdges removes the 'clone_of' relationships from the nodes of an instance hierarchy
 func (n *Neo4j) RemoveCloneEdges(ctx context.Context, attempt int, instanceID, dimensionName string) (err error) {
-	return driver.ErrNotImplemented
+	q := fmt.Sprintf(query.RemoveCloneMarkers, instanceID, dimensionName)
+
+	log.Info(ctx, "removing edges to generic hierarchy", log.Data{
+		"instance_id":    instanceID,
+		"dimension_name": dimensionName,
+		"query":          q,
+	})
+
+	return n.execWithRetries(attempt, q, nil)
 }
 
+// RemoveCloneEdgesFromSourceIDs removes the 'clone_of' relationships from the cloned nodes with the provided IDs
 func (n *Neo4j) RemoveCloneEdgesFromSourceIDs(ctx context.Context, attempt int, ids map[string]string) (err error) {
-	return driver.ErrNotImplemented
+	log.Info(ctx, "removing edges to generic hierarchy", log.Data{"num_ids": len(ids)})
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	nodeIDs, err := nodeIDsParam(ids)
+	if err != nil {
+		return err
+	}
+
+	return n.execWithRetries(attempt, query.RemoveCloneMarkersFromSourceIDs, map[string]interface{}{"ids": nodeIDs})
 }
 
+// SetNumberOfChildrenFromIDs sets the numberOfChildren property of the hierarchy nodes with the provided IDs
 func (n *Neo4j) SetNumberOfChildrenFromIDs(ctx context.Context, attempt int, ids map[string]string) (err error) {
-	return driver.ErrNotImplemented
+	log.Info(ctx, "setting number of children property value on the instance hierarchy nodes", log.Data{"num_ids": len(ids)})
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	nodeIDs, err := nodeIDsParam(ids)
+	if err != nil {
+		return err
+	}
+
+	return n.execWithRetries(attempt, query.SetNumberOfChildrenFromIDs, map[string]interface{}{"ids": nodeIDs})
+}
+
+// cloneLocalisedLabelsPart returns the part of a clone query that copies the labels in languages other than the default,
+// for the keys of the labels that the generic hierarchy has, along with the query used to read those keys
+func (n *Neo4j) cloneLocalisedLabelsPart(codeListID string) (setLabels, keysQuery string, err error) {
+	keysQuery = fmt.Sprintf(query.GetLocalisedLabelKeys, codeListID)
+	labelKeys, err := n.readSortedStringList(keysQuery)
+	if err != nil {
+		return "", keysQuery, err
+	}
+
+	for _, key := range labelKeys {
+		setLabels += fmt.Sprintf(query.CloneLocalisedLabelPart, key, key)
+	}
+	return setLabels, keysQuery, nil
+}
+
+// execWithRetries executes the query, retrying transient errors up to the maximum number of attempts
+func (n *Neo4j) execWithRetries(attempt int, q string, params map[string]interface{}) error {
+	if _, err := n.Exec(q, params); err != nil {
+		if finalErr := n.checkAttempts(err, q, attempt); finalErr != nil {
+			return finalErr
+		}
+
+		return n.execWithRetries(attempt+1, q, params)
+	}
+
+	return nil
+}
+
+// nodeIDsParam returns the node IDs which are the keys of the provided map as a sorted list of integers
+func nodeIDsParam(ids map[string]string) ([]int64, error) {
+	nodeIDs := make([]int64, 0, len(ids))
+	for id := range ids {
+		nodeID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid node id %q", id)
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return nodeIDs[i] < nodeIDs[j]
+	})
+	return nodeIDs, nil
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
 	bolt "github.com/ONSdigital/golang-neo4j-bolt-driver"
 	"github.com/ONSdigital/log.go/v2/log"
@@ -156,26 +159,49 @@ func (n *Neo4j) AddVersionDetailsToInstance(ctx context.Context, instanceID, dat
 	return nil
 }
 
-// SetInstanceIsPublished sets a flag on an instance node to indicate the published state
+// SetInstanceIsPublished sets the publish state of an instance node to published, and marks the
+// instances previously published for the same dataset edition as superseded
 func (n *Neo4j) SetInstanceIsPublished(ctx context.Context, instanceID string) error {
-	data := log.Data{
-		"instance_id": instanceID,
+	if err := n.SetInstancePublishState(ctx, instanceID, models.PublishStatePublished); err != nil {
+		return err
+	}
+
+	if _, err := n.Exec(fmt.Sprintf(query.SupersedePublishedInstances, instanceID), nil); err != nil {
+		return errors.WithMessage(err, "neoClient SetInstanceIsPublished: error superseding previously published instances")
 	}
 
-	log.Info(ctx, "neoClient SetInstanceIsPublished: attempting to set is_published property on instance node", data)
+	log.Info(ctx, "neoClient SetInstanceIsPublished: previously published instances superseded", log.Data{"instance_id": instanceID})
+	return nil
+}
 
-	q := fmt.Sprintf(query.SetInstanceIsPublished, instanceID)
+// SetInstancePublishState sets the publish state of an instance node. The is_published flag
+// is kept in step with the state, so superseded instances remain published.
+func (n *Neo4j) SetInstancePublishState(ctx context.Context, instanceID string, state models.PublishState) error {
+	if err := state.Validate(); err != nil {
+		return err
+	}
 
-	result, err := n.Exec(q, nil)
+	data := log.Data{
+		"instance_id":   instanceID,
+		"publish_state": state,
+	}
+
+	q := fmt.Sprintf(query.SetInstancePublishState, instanceID)
+	params := map[string]interface{}{
+		"is_published":  state.IsPublished(),
+		"publish_state": string(state),
+	}
+
+	result, err := n.Exec(q, params)
 	if err != nil {
-		return errors.WithMessage(err, "neoClient SetInstanceIsPublished: error executing neo4j update statement")
+		return errors.WithMessage(err, "neoClient SetInstancePublishState: error executing neo4j update statement")
 	}
 
-	if err := checkPropertiesSet(result, 1); err != nil {
-		return errors.WithMessage(err, "neoClient SetInstanceIsPublished: invalid results")
+	if err := checkPropertiesSet(result, int64(len(params))); err != nil {
+		return errors.WithMessage(err, "neoClient SetInstancePublishState: invalid results")
 	}
 
-	log.Info(ctx, "neoClient SetInstanceIsPublished: update successful", data)
+	log.Info(ctx, "neoClient SetInstancePublishState: update successful", data)
 	return nil
 }
 
@@ -201,3 +227,138 @@ func checkPropertiesSet(result bolt.Result, expected int64) error {
 
 	return nil
 }
+
+// GetInstance returns the details stored on the instance node with the provided id
+func (n *Neo4j) GetInstance(ctx context.Context, instanceID string) (*models.Instance, error) {
+	if len(instanceID) == 0 {
+		return nil, errors.New("instance id is required but was empty")
+	}
+
+	instance := &models.Instance{}
+	if err := n.Read(fmt.Sprintf(query.GetInstance, instanceID), mapper.Instance(instance), true); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// ListInstances returns the instances matching the provided filter, ordered by dataset,
+// edition and version. A nil filter returns all instances. Instances are found by their
+// _<id>_Instance label and header property.
+func (n *Neo4j) ListInstances(ctx context.Context, filter *models.InstanceFilter) ([]*models.Instance, error) {
+	var conditions []string
+	params := map[string]interface{}{}
+	if filter != nil {
+		if len(filter.DatasetID) > 0 {
+			conditions = append(conditions, query.InstanceDatasetIDFilterPart)
+			params["dataset_id"] = filter.DatasetID
+		}
+		if len(filter.Edition) > 0 {
+			conditions = append(conditions, query.InstanceEditionFilterPart)
+			params["edition"] = filter.Edition
+		}
+		if filter.IsPublished != nil {
+			if *filter.IsPublished {
+				conditions = append(conditions, query.InstancePublishedFilterPart)
+			} else {
+				conditions = append(conditions, query.InstanceNotPublishedFilterPart)
+			}
+		}
+	}
+
+	var where string
+	if len(conditions) > 0 {
+		where = " AND " + strings.Join(conditions, " AND ")
+	}
+	stmt := fmt.Sprintf(query.ListInstances, where)
+
+	instances := make([]*models.Instance, 0)
+	if err := n.ReadWithParams(stmt, params, mapper.Instances(&instances), false); err != nil {
+		if err == driver.ErrNotFound {
+			return instances, nil
+		}
+		log.Error(ctx, "neo4j read failed on ListInstances", err, log.Data{"statement": stmt, "params": params})
+		return nil, err
+	}
+	return instances, nil
+}
+
+// ValidateInstance checks the consistency of an imported instance, for each of the
+// dimensions recorded on the instance node
+func (n *Neo4j) ValidateInstance(ctx context.Context, instanceID string) (*models.InstanceValidationReport, error) {
+	if len(instanceID) == 0 {
+		return nil, errors.New("instance id is required but was empty")
+	}
+
+	exists, err := n.InstanceExists(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, driver.ErrNotFound
+	}
+
+	var dimensions []string
+	if err := n.Read(fmt.Sprintf(query.GetInstanceDimensions, instanceID), mapper.StringList(&dimensions), true); err != nil {
+		return nil, err
+	}
+	sort.Strings(dimensions)
+
+	count, err := n.CountInsertedObservations(ctx, instanceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count observations")
+	}
+
+	report := &models.InstanceValidationReport{
+		InstanceID:       instanceID,
+		ObservationCount: count,
+		Dimensions:       make([]*models.DimensionValidationReport, 0, len(dimensions)),
+	}
+
+	for _, dimension := range dimensions {
+		d, err := n.validateDimension(ctx, instanceID, dimension)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to validate dimension %q", dimension)
+		}
+		report.Dimensions = append(report.Dimensions, d)
+	}
+
+	log.Info(ctx, "instance validated", log.Data{"instance_id": instanceID, "is_valid": report.IsValid()})
+	return report, nil
+}
+
+func (n *Neo4j) validateDimension(ctx context.Context, instanceID, dimension string) (d *models.DimensionValidationReport, err error) {
+	d = &models.DimensionValidationReport{Name: dimension}
+
+	if d.ObservationsMissingEdges, err = n.Count(fmt.Sprintf(query.CountObservationsMissingDimension, instanceID, instanceID, dimension)); err != nil {
+		return nil, err
+	}
+
+	if d.OptionsWithoutObservations, err = n.readSortedStringList(fmt.Sprintf(query.GetDimensionOptionsWithoutObservations, instanceID, dimension)); err != nil {
+		return nil, err
+	}
+
+	if d.OrphanOptions, err = n.readSortedStringList(fmt.Sprintf(query.GetOrphanDimensionOptions, instanceID, dimension, instanceID)); err != nil {
+		return nil, err
+	}
+
+	if d.HasHierarchy, err = n.HierarchyExists(ctx, instanceID, dimension); err != nil {
+		return nil, err
+	}
+	if !d.HasHierarchy {
+		return d, nil
+	}
+
+	if d.HasDataMismatches, err = n.readSortedStringList(fmt.Sprintf(query.GetHasDataMismatches, instanceID, dimension, instanceID, dimension)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (n *Neo4j) readSortedStringList(stmt string) ([]string, error) {
+	list := make([]string, 0)
+	if err := n.Read(stmt, mapper.StringList(&list), true); err != nil {
+		return nil, err
+	}
+	sort.Strings(list)
+	return list, nil
+}
@@ -6,9 +6,13 @@ import (
 	"strings"
 	"testing"
 
+	graph "github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/internal"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
 	bolt "github.com/ONSdigital/golang-neo4j-bolt-driver"
+	boltstructures "github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
 	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -106,7 +110,7 @@ func Test_SetInstanceIsPublishedSuccess(t *testing.T) {
 			MetadataFunc: func() map[string]interface{} {
 				return map[string]interface{}{
 					"stats": map[string]interface{}{
-						"properties-set": int64(1),
+						"properties-set": int64(2),
 					},
 				}
 			},
@@ -121,8 +125,17 @@ func Test_SetInstanceIsPublishedSuccess(t *testing.T) {
 
 		err := db.SetInstanceIsPublished(context.Background(), testInstanceID)
 		So(err, ShouldBeNil)
-		So(len(driver.ExecCalls()), ShouldEqual, 1)
-		So(driver.ExecCalls()[0].Params, ShouldBeNil)
+		So(len(driver.ExecCalls()), ShouldEqual, 2)
+		So(driver.ExecCalls()[0].Query, ShouldEqual, "MATCH (i:`_666_Instance`) SET i.is_published = {is_published}, i.publish_state = {publish_state}")
+		So(driver.ExecCalls()[0].Params, ShouldResemble, map[string]interface{}{
+			"is_published":  true,
+			"publish_state": "published",
+		})
+
+		So(driver.ExecCalls()[1].Query, ShouldEqual, "MATCH (i:`_666_Instance`) MATCH (p) WHERE exists(p.header)"+
+			" AND any(l IN labels(p) WHERE l STARTS WITH '_' AND l ENDS WITH '_Instance') AND p <> i"+
+			" AND p.dataset_id = i.dataset_id AND p.edition = i.edition AND p.is_published = true"+
+			" AND coalesce(p.publish_state, 'published') = 'published' SET p.publish_state = 'superseded'")
 
 		So(len(res.MetadataCalls()), ShouldEqual, 1)
 	})
@@ -142,7 +155,7 @@ func Test_SetInstanceIsPublishedError(t *testing.T) {
 		err := db.SetInstanceIsPublished(context.Background(), testInstanceID)
 
 		Convey("then the expected error is returned", func() {
-			So(err, ShouldResemble, errors.WithMessage(errTest, "neoClient SetInstanceIsPublished: error executing neo4j update statement"))
+			So(err, ShouldResemble, errors.WithMessage(errTest, "neoClient SetInstancePublishState: error executing neo4j update statement"))
 			So(len(driver.ExecCalls()), ShouldEqual, 1)
 		})
 	})
@@ -166,13 +179,81 @@ func Test_SetInstanceIsPublishedError(t *testing.T) {
 		err := db.SetInstanceIsPublished(context.Background(), testInstanceID)
 
 		Convey("then the expected error is returned", func() {
-			So(err.Error(), ShouldContainSubstring, "neoClient SetInstanceIsPublished: invalid results")
+			So(err.Error(), ShouldContainSubstring, "neoClient SetInstancePublishState: invalid results")
 			So(len(driver.ExecCalls()), ShouldEqual, 1)
 			So(len(res.MetadataCalls()), ShouldEqual, 1)
 		})
 	})
 }
 
+func Test_SetInstancePublishState(t *testing.T) {
+	Convey("SetInstancePublishState marks an instance as superseded", t, func() {
+		res := &internal.ResultMock{
+			MetadataFunc: func() map[string]interface{} {
+				return map[string]interface{}{
+					"stats": map[string]interface{}{
+						"properties-set": int64(2),
+					},
+				}
+			},
+		}
+		driver := &internal.Neo4jDriverMock{
+			ExecFunc: func(query string, params map[string]interface{}) (bolt.Result, error) {
+				return res, nil
+			},
+		}
+
+		db := &Neo4j{driver, 5, 30}
+
+		err := db.SetInstancePublishState(context.Background(), testInstanceID, models.PublishStateSuperseded)
+		So(err, ShouldBeNil)
+		So(len(driver.ExecCalls()), ShouldEqual, 1)
+		So(driver.ExecCalls()[0].Query, ShouldEqual, "MATCH (i:`_666_Instance`) SET i.is_published = {is_published}, i.publish_state = {publish_state}")
+		So(driver.ExecCalls()[0].Params, ShouldResemble, map[string]interface{}{
+			"is_published":  true,
+			"publish_state": "superseded",
+		})
+	})
+
+	Convey("SetInstancePublishState marks an instance as unpublished", t, func() {
+		res := &internal.ResultMock{
+			MetadataFunc: func() map[string]interface{} {
+				return map[string]interface{}{
+					"stats": map[string]interface{}{
+						"properties-set": int64(2),
+					},
+				}
+			},
+		}
+		driver := &internal.Neo4jDriverMock{
+			ExecFunc: func(query string, params map[string]interface{}) (bolt.Result, error) {
+				return res, nil
+			},
+		}
+
+		db := &Neo4j{driver, 5, 30}
+
+		err := db.SetInstancePublishState(context.Background(), testInstanceID, models.PublishStateUnpublished)
+		So(err, ShouldBeNil)
+		So(driver.ExecCalls()[0].Params, ShouldResemble, map[string]interface{}{
+			"is_published":  false,
+			"publish_state": "unpublished",
+		})
+	})
+
+	Convey("given an invalid publish state", t, func() {
+		driver := &internal.Neo4jDriverMock{}
+		db := &Neo4j{driver, 5, 30}
+
+		err := db.SetInstancePublishState(context.Background(), testInstanceID, models.PublishState("withdrawn"))
+
+		Convey("then the expected error is returned and the database is not called", func() {
+			So(err.Error(), ShouldEqual, `invalid publish state "withdrawn"`)
+			So(len(driver.ExecCalls()), ShouldEqual, 0)
+		})
+	})
+}
+
 func Test_AddDimensions(t *testing.T) {
 
 	Convey("Given Neo4j.Exec returns an error", t, func() {
@@ -688,3 +769,268 @@ func Test_checkPropertiesSet(t *testing.T) {
 		})
 	})
 }
+
+func TestNeo4j_ValidateInstance(t *testing.T) {
+
+	Convey("Given an instance with inconsistent dimensions", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				list := []interface{}{}
+				switch query {
+				case "MATCH (i:`_666_Instance`) RETURN coalesce(i.dimensions, [])":
+					list = []interface{}{"sex", "geography"}
+				case "MATCH (d:`_666_sex`) WHERE NOT ()-[:isValueOf]->(d) RETURN collect(d.value)":
+					list = []interface{}{"female", "any"}
+				case "MATCH (n:`_hierarchy_node_666_geography`) OPTIONAL MATCH (d:`_666_geography` {value: n.code})" +
+					" WITH n, count(d) > 0 AS withData WHERE coalesce(n.hasData, false) <> withData RETURN collect(n.code)":
+					list = []interface{}{"W92000004", "E92000001"}
+				}
+				return mapp(&mapper.Result{Data: []interface{}{list}})
+			},
+			CountFunc: func(query string) (int64, error) {
+				switch query {
+				case "MATCH (i: `_666_Instance`) RETURN COUNT(*)":
+					return 1, nil
+				case "MATCH (o:`_666_observation`) RETURN COUNT(o)":
+					return 100, nil
+				case "MATCH (o:`_666_observation`) WHERE NOT (o)-[:isValueOf]->(:`_666_sex`) RETURN count(o)":
+					return 2, nil
+				}
+				return 0, nil
+			},
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				if strings.Contains(query, "_hierarchy_node_666_geography") {
+					return mapp(&mapper.Result{Data: []interface{}{boltstructures.Node{Properties: map[string]interface{}{}}}})
+				}
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When ValidateInstance is called", func() {
+			report, err := db.ValidateInstance(context.Background(), testInstanceID)
+
+			Convey("Then the expected report is returned", func() {
+				So(err, ShouldBeNil)
+				So(report.InstanceID, ShouldEqual, testInstanceID)
+				So(report.ObservationCount, ShouldEqual, 100)
+				So(report.IsValid(), ShouldBeFalse)
+				So(report.Dimensions, ShouldHaveLength, 2)
+			})
+
+			Convey("Then the hierarchy of a dimension is checked against its options", func() {
+				geography := report.Dimensions[0]
+				So(geography.Name, ShouldEqual, "geography")
+				So(geography.HasHierarchy, ShouldBeTrue)
+				So(geography.HasDataMismatches, ShouldResemble, []string{"E92000001", "W92000004"})
+			})
+
+			Convey("Then missing edges and unused options are reported for a dimension", func() {
+				sex := report.Dimensions[1]
+				So(sex.Name, ShouldEqual, "sex")
+				So(sex.ObservationsMissingEdges, ShouldEqual, 2)
+				So(sex.OptionsWithoutObservations, ShouldResemble, []string{"any", "female"})
+				So(sex.OrphanOptions, ShouldBeEmpty)
+				So(sex.HasHierarchy, ShouldBeFalse)
+				So(sex.HasDataMismatches, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an instance that does not exist", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			CountFunc: func(query string) (int64, error) {
+				return 0, nil
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When ValidateInstance is called", func() {
+			report, err := db.ValidateInstance(context.Background(), testInstanceID)
+
+			Convey("Then a not found error is returned without validating the instance", func() {
+				So(report, ShouldBeNil)
+				So(err, ShouldEqual, graph.ErrNotFound)
+				So(neoMock.CountCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given an empty instance id", t, func() {
+		neoMock := &internal.Neo4jDriverMock{}
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When ValidateInstance is called", func() {
+			report, err := db.ValidateInstance(context.Background(), "")
+
+			Convey("Then an error is returned without querying the database", func() {
+				So(report, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "instance id is required but was empty")
+				So(neoMock.CountCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func testInstanceNode(instanceID string, properties map[string]interface{}) boltstructures.Node {
+	return boltstructures.Node{
+		Labels:     []string{fmt.Sprintf("_%s_Instance", instanceID)},
+		Properties: properties,
+	}
+}
+
+func TestNeo4j_GetInstance(t *testing.T) {
+	Convey("Given an instance with version details that has been published", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{testInstanceNode(testInstanceID, map[string]interface{}{
+					"header":       "V4_0,time,sex",
+					"dimensions":   []interface{}{"time", "sex"},
+					"dataset_id":   testDatasetId,
+					"edition":      testEdition,
+					"version":      int64(testVersion),
+					"is_published": true,
+				})}})
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetInstance is called", func() {
+			instance, err := db.GetInstance(context.Background(), testInstanceID)
+
+			Convey("Then the instance node is read", func() {
+				So(neoMock.ReadCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadCalls()[0].Query, ShouldEqual, "MATCH (i:`_666_Instance`) RETURN i")
+				So(neoMock.ReadCalls()[0].Single, ShouldBeTrue)
+			})
+
+			Convey("Then the instance details are returned", func() {
+				So(err, ShouldBeNil)
+				So(instance, ShouldResemble, &models.Instance{
+					InstanceID:   testInstanceID,
+					CSVHeader:    []string{"V4_0", "time", "sex"},
+					Dimensions:   []interface{}{"time", "sex"},
+					DatasetID:    testDatasetId,
+					Edition:      testEdition,
+					Version:      testVersion,
+					IsPublished:  true,
+					PublishState: models.PublishStatePublished,
+				})
+			})
+		})
+	})
+
+	Convey("Given an instance that does not exist", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetInstance is called", func() {
+			instance, err := db.GetInstance(context.Background(), testInstanceID)
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(instance, ShouldBeNil)
+				So(err, ShouldEqual, graph.ErrNotFound)
+			})
+		})
+	})
+
+	Convey("Given an empty instance ID", t, func() {
+		neoMock := &internal.Neo4jDriverMock{}
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When GetInstance is called", func() {
+			instance, err := db.GetInstance(context.Background(), "")
+
+			Convey("Then the expected error is returned and the database is not called", func() {
+				So(instance, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "instance id is required but was empty")
+				So(neoMock.ReadCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestNeo4j_ListInstances(t *testing.T) {
+	Convey("Given a database holding instances", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				for _, id := range []string{"1", "2"} {
+					node := testInstanceNode(id, map[string]interface{}{"header": "V4_0,time"})
+					if err := mapp(&mapper.Result{Data: []interface{}{node}}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When ListInstances is called without a filter", func() {
+			instances, err := db.ListInstances(context.Background(), nil)
+
+			Convey("Then all instances are queried", func() {
+				So(neoMock.ReadWithParamsCalls(), ShouldHaveLength, 1)
+				So(neoMock.ReadWithParamsCalls()[0].Query, ShouldEqual,
+					"MATCH (i) WHERE exists(i.header) AND any(l IN labels(i) WHERE l STARTS WITH '_' AND l ENDS WITH '_Instance') RETURN i ORDER BY i.dataset_id, i.edition, i.version")
+				So(neoMock.ReadWithParamsCalls()[0].Params, ShouldBeEmpty)
+			})
+
+			Convey("Then the instances are returned", func() {
+				So(err, ShouldBeNil)
+				So(instances, ShouldHaveLength, 2)
+				So(instances[0].InstanceID, ShouldEqual, "1")
+				So(instances[1].InstanceID, ShouldEqual, "2")
+			})
+		})
+
+		Convey("When ListInstances is called with a filter", func() {
+			published := false
+			_, err := db.ListInstances(context.Background(), &models.InstanceFilter{
+				DatasetID:   testDatasetId,
+				Edition:     testEdition,
+				IsPublished: &published,
+			})
+
+			Convey("Then the instances are filtered", func() {
+				So(err, ShouldBeNil)
+				So(neoMock.ReadWithParamsCalls()[0].Query, ShouldEqual,
+					"MATCH (i) WHERE exists(i.header) AND any(l IN labels(i) WHERE l STARTS WITH '_' AND l ENDS WITH '_Instance')"+
+						" AND i.dataset_id = {dataset_id} AND i.edition = {edition}"+
+						" AND coalesce(i.is_published, false) = false RETURN i ORDER BY i.dataset_id, i.edition, i.version")
+				So(neoMock.ReadWithParamsCalls()[0].Params, ShouldResemble, map[string]interface{}{
+					"dataset_id": testDatasetId,
+					"edition":    testEdition,
+				})
+			})
+		})
+	})
+
+	Convey("Given a database without matching instances", t, func() {
+		neoMock := &internal.Neo4jDriverMock{
+			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
+
+		db := Neo4j{neoMock, 5, 30}
+
+		Convey("When ListInstances is called", func() {
+			instances, err := db.ListInstances(context.Background(), nil)
+
+			Convey("Then an empty list is returned", func() {
+				So(err, ShouldBeNil)
+				So(instances, ShouldBeEmpty)
+			})
+		})
+	})
+}
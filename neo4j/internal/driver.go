@@ -5,6 +5,7 @@ package internal
 
 import (
 	"context"
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/neo4jdriver"
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
@@ -43,6 +44,9 @@ var _ neo4jdriver.Neo4jDriver = &Neo4jDriverMock{}
 // 			ReadWithParamsFunc: func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
 // 				panic("mock out the ReadWithParams method")
 // 			},
+// 			StatsFunc: func() driver.PoolStats {
+// 				panic("mock out the Stats method")
+// 			},
 // 			StreamRowsFunc: func(query string) (*neo4jdriver.BoltRowReader, error) {
 // 				panic("mock out the StreamRows method")
 // 			},
@@ -74,6 +78,9 @@ type Neo4jDriverMock struct {
 	// ReadWithParamsFunc mocks the ReadWithParams method.
 	ReadWithParamsFunc func(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error
 
+	// StatsFunc mocks the Stats method.
+	StatsFunc func() driver.PoolStats
+
 	// StreamRowsFunc mocks the StreamRows method.
 	StreamRowsFunc func(query string) (*neo4jdriver.BoltRowReader, error)
 
@@ -126,6 +133,9 @@ type Neo4jDriverMock struct {
 			// Single is the single argument value.
 			Single bool
 		}
+		// Stats holds details about calls to the Stats method.
+		Stats []struct {
+		}
 		// StreamRows holds details about calls to the StreamRows method.
 		StreamRows []struct {
 			// Query is the query argument value.
@@ -139,6 +149,7 @@ type Neo4jDriverMock struct {
 	lockHealthcheck    sync.RWMutex
 	lockRead           sync.RWMutex
 	lockReadWithParams sync.RWMutex
+	lockStats          sync.RWMutex
 	lockStreamRows     sync.RWMutex
 }
 
@@ -382,6 +393,32 @@ func (mock *Neo4jDriverMock) ReadWithParamsCalls() []struct {
 	return calls
 }
 
+// Stats calls StatsFunc.
+func (mock *Neo4jDriverMock) Stats() driver.PoolStats {
+	if mock.StatsFunc == nil {
+		panic("Neo4jDriverMock.StatsFunc: method is nil but Neo4jDriver.Stats was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockStats.Lock()
+	mock.calls.Stats = append(mock.calls.Stats, callInfo)
+	mock.lockStats.Unlock()
+	return mock.StatsFunc()
+}
+
+// StatsCalls gets all the calls that were made to Stats.
+// Check the length with:
+//     len(mockedNeo4jDriver.StatsCalls())
+func (mock *Neo4jDriverMock) StatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStats.RLock()
+	calls = mock.calls.Stats
+	mock.lockStats.RUnlock()
+	return calls
+}
+
 // StreamRows calls StreamRowsFunc.
 func (mock *Neo4jDriverMock) StreamRows(query string) (*neo4jdriver.BoltRowReader, error) {
 	if mock.StreamRowsFunc == nil {
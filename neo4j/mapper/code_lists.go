@@ -5,6 +5,7 @@ import (
 
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/pkg/errors"
 )
 
 //CodeLists returns a dpbolt.ResultMapper which converts a dpbolt.Result to models.CodeLists
@@ -40,3 +41,41 @@ func CodeList(codeList *models.CodeList, id string) ResultMapper {
 		return nil
 	}
 }
+
+// CodeListNodes returns a dpbolt.ResultMapper which converts a dpbolt.Result of a code list node and the number
+// of codes it uses to a models.CodeListNode, appending it to the provided nodes. As code list type flags are
+// stored as node labels, each flag label is added to the node properties as a true boolean property.
+func CodeListNodes(nodes *[]models.CodeListNode) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 2 {
+			return errors.Errorf("get code list detail error: expecting two result values but %d returned", len(r.Data))
+		}
+
+		node, err := getNode(r.Data[0])
+		if err != nil {
+			return err
+		}
+
+		codeCount, ok := r.Data[1].(int64)
+		if !ok {
+			return castingError(int64(0), r.Data[1])
+		}
+
+		properties := make(map[string]interface{}, len(node.Properties)+len(node.Labels))
+		for key, value := range node.Properties {
+			properties[key] = value
+		}
+		for _, label := range node.Labels {
+			if label == "_code_list" || strings.HasPrefix(label, "_code_list_") {
+				continue
+			}
+			properties[strings.TrimPrefix(label, "_")] = true
+		}
+
+		*nodes = append(*nodes, models.CodeListNode{
+			Properties: properties,
+			CodeCount:  codeCount,
+		})
+		return nil
+	}
+}
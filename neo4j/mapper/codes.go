@@ -6,12 +6,14 @@ import (
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
+	"github.com/pkg/errors"
 )
 
-//Codes returns a dpbolt.ResultMapper mapper which converts dpbolt.Result to models.CodeResults
-func Codes(results *models.CodeResults, codeListID string, edition string) ResultMapper {
+//Codes returns a dpbolt.ResultMapper mapper which converts dpbolt.Result to models.CodeResults,
+//with labels in the provided language where available
+func Codes(results *models.CodeResults, codeListID string, edition string, language string) ResultMapper {
 	return func(r *Result) error {
-		code, err := code(r)
+		code, err := code(r, language)
 		if err != nil {
 			return err
 		}
@@ -21,10 +23,11 @@ func Codes(results *models.CodeResults, codeListID string, edition string) Resul
 	}
 }
 
-//Code returns a dpbolt.ResultMapper which converts a dpbolt.Result to models.Code
-func Code(codeModel *models.Code, codeListID string, edition string) ResultMapper {
+//Code returns a dpbolt.ResultMapper which converts a dpbolt.Result to models.Code,
+//with the label in the provided language where available
+func Code(codeModel *models.Code, codeListID string, edition string, language string) ResultMapper {
 	return func(r *Result) error {
-		co, err := code(r)
+		co, err := code(r, language)
 		if err != nil {
 			return err
 		}
@@ -34,7 +37,7 @@ func Code(codeModel *models.Code, codeListID string, edition string) ResultMappe
 	}
 }
 
-func code(r *Result) (*models.Code, error) {
+func code(r *Result, language string) (*models.Code, error) {
 	if len(r.Data) == 0 {
 		return nil, driver.ErrNotFound
 	}
@@ -58,13 +61,129 @@ func code(r *Result) (*models.Code, error) {
 	}
 
 	var codeLabel string
-	if codeLabel, err = getStringProperty("label", rel.Properties); err != nil {
+	if codeLabel, err = getLabelProperty(language, rel.Properties); err != nil {
 		return nil, err
 	}
 
+	var o *int64
+	if o, err = getOptionalInt64Property("order", rel.Properties); err != nil {
+		return nil, err
+	}
+
+	var order *int
+	if o != nil {
+		i := int(*o)
+		order = &i
+	}
+
 	return &models.Code{
 		ID:    strconv.FormatInt(id, 10),
 		Code:  codeVal,
 		Label: codeLabel,
+		Order: order,
 	}, nil
 }
+
+// CodesOrder returns a dpbolt.ResultMapper which converts dpbolt.Result of code value and order to an entry
+// of the provided map, with a nil order if the code has none in the code list
+func CodesOrder(codeOrders map[string]*int) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 2 {
+			return errors.Errorf("get codes order error: expecting two result values but %d returned", len(r.Data))
+		}
+
+		code, ok := r.Data[0].(string)
+		if !ok {
+			return castingError("", r.Data[0])
+		}
+
+		codeOrders[code] = nil
+		if r.Data[1] != nil {
+			order, ok := r.Data[1].(int64)
+			if !ok {
+				return castingError(int64(0), r.Data[1])
+			}
+			o := int(order)
+			codeOrders[code] = &o
+		}
+		return nil
+	}
+}
+
+// CodeListEntries returns a dpbolt.ResultMapper which converts dpbolt.Result of code list edition, code value,
+// label and order to a models.CodeListEntry, appending it to the entries of the edition
+func CodeListEntries(entries map[string][]models.CodeListEntry) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 4 {
+			return errors.Errorf("get code list entries error: expecting four result values but %d returned", len(r.Data))
+		}
+
+		edition, ok := r.Data[0].(string)
+		if !ok {
+			return castingError("", r.Data[0])
+		}
+
+		entry, err := codeListEntry(r.Data[1:])
+		if err != nil {
+			return err
+		}
+
+		entries[edition] = append(entries[edition], *entry)
+		return nil
+	}
+}
+
+// CodesBatch returns a dpbolt.ResultMapper which converts dpbolt.Result of code list ID, edition, code value,
+// label and order to a models.CodeLookup, adding it to the provided codes
+func CodesBatch(codes map[models.CodeRef]models.CodeLookup) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 5 {
+			return errors.Errorf("get codes batch error: expecting five result values but %d returned", len(r.Data))
+		}
+
+		var ref models.CodeRef
+		var ok bool
+		if ref.CodeListID, ok = r.Data[0].(string); !ok {
+			return castingError("", r.Data[0])
+		}
+		if ref.Edition, ok = r.Data[1].(string); !ok {
+			return castingError("", r.Data[1])
+		}
+
+		entry, err := codeListEntry(r.Data[2:])
+		if err != nil {
+			return err
+		}
+		ref.Code = entry.Code
+
+		codes[ref] = models.CodeLookup{Label: entry.Label, Order: entry.Order}
+		return nil
+	}
+}
+
+// codeListEntry converts the code value, label and order values of a result to a models.CodeListEntry
+func codeListEntry(data []interface{}) (*models.CodeListEntry, error) {
+	var entry models.CodeListEntry
+	var ok bool
+	if entry.Code, ok = data[0].(string); !ok {
+		return nil, castingError("", data[0])
+	}
+
+	// edges created before labels or order were set have null values
+	if data[1] != nil {
+		if entry.Label, ok = data[1].(string); !ok {
+			return nil, castingError("", data[1])
+		}
+	}
+
+	if data[2] != nil {
+		order, ok := data[2].(int64)
+		if !ok {
+			return nil, castingError(int64(0), data[2])
+		}
+		o := int(order)
+		entry.Order = &o
+	}
+
+	return &entry, nil
+}
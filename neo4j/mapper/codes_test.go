@@ -30,7 +30,7 @@ func TestCodeResultExtractor_Success(t *testing.T) {
 		}
 
 		actual := &models.Code{}
-		extractor := Code(actual, testCodeListID, testEdition)
+		extractor := Code(actual, testCodeListID, testEdition, "")
 
 		Convey("when extractor is called", func() {
 			err := extractor(
@@ -53,6 +53,30 @@ func TestCodeResultExtractor_Success(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("given a valid result with an order on the relationship", t, func() {
+		node := graph.Node{
+			NodeIdentity: testNodeIdentity,
+			Properties:   map[string]interface{}{"value": testNodeValue},
+		}
+
+		rel := graph.Relationship{
+			Properties: map[string]interface{}{"label": testRelationshipLabel, "order": int64(3)},
+		}
+
+		actual := &models.Code{}
+		extractor := Code(actual, testCodeListID, testEdition, "")
+
+		Convey("when extractor is called", func() {
+			err := extractor(&Result{Data: []interface{}{node, rel}})
+
+			Convey("then no error is returned and the order of the code is populated", func() {
+				So(err, ShouldBeNil)
+				So(actual.Order, ShouldNotBeNil)
+				So(*actual.Order, ShouldEqual, 3)
+			})
+		})
+	})
 }
 
 func TestCodeResultExtractor_BadTypes(t *testing.T) {
@@ -64,7 +88,7 @@ func TestCodeResultExtractor_BadTypes(t *testing.T) {
 
 	Convey("given data.0 is not type graph.Node", t, func() {
 		actual := &models.Code{}
-		extractor := Code(actual, testCodeListID, testEdition)
+		extractor := Code(actual, testCodeListID, testEdition, "")
 
 		Convey("when extractor is called", func() {
 			err := extractor(
@@ -86,7 +110,7 @@ func TestCodeResultExtractor_BadTypes(t *testing.T) {
 
 	Convey("given node.Properties.value is not type string", t, func() {
 		actual := &models.Code{}
-		extractor := Code(actual, testCodeListID, testEdition)
+		extractor := Code(actual, testCodeListID, testEdition, "")
 
 		Convey("when extractor is called", func() {
 			err := extractor(
@@ -108,7 +132,7 @@ func TestCodeResultExtractor_BadTypes(t *testing.T) {
 
 	Convey("given data.1 is not type graph.Relationship", t, func() {
 		actual := &models.Code{}
-		extractor := Code(actual, testCodeListID, testEdition)
+		extractor := Code(actual, testCodeListID, testEdition, "")
 
 		Convey("when extractor is called", func() {
 			err := extractor(
@@ -130,7 +154,7 @@ func TestCodeResultExtractor_BadTypes(t *testing.T) {
 
 	Convey("given relationship.Properties.label is not type string", t, func() {
 		actual := &models.Code{}
-		extractor := Code(actual, testCodeListID, testEdition)
+		extractor := Code(actual, testCodeListID, testEdition, "")
 
 		Convey("when extractor is called", func() {
 			err := extractor(
@@ -150,3 +174,68 @@ func TestCodeResultExtractor_BadTypes(t *testing.T) {
 		})
 	})
 }
+
+func TestCodeListEntries(t *testing.T) {
+	Convey("given results for the codes of two editions", t, func() {
+		entries := make(map[string][]models.CodeListEntry)
+		extractor := CodeListEntries(entries)
+
+		Convey("when extractor is called", func() {
+			So(extractor(&Result{Data: []interface{}{"2019", "jan", "January", int64(1)}}), ShouldBeNil)
+			So(extractor(&Result{Data: []interface{}{"2019", "feb", nil, nil}}), ShouldBeNil)
+			So(extractor(&Result{Data: []interface{}{"2020", "jan", "January", int64(2)}}), ShouldBeNil)
+
+			Convey("then the entries are grouped by edition", func() {
+				one, two := 1, 2
+				So(entries, ShouldResemble, map[string][]models.CodeListEntry{
+					"2019": {{Code: "jan", Label: "January", Order: &one}, {Code: "feb"}},
+					"2020": {{Code: "jan", Label: "January", Order: &two}},
+				})
+			})
+		})
+	})
+
+	Convey("given a result with an order that is not type int64", t, func() {
+		entries := make(map[string][]models.CodeListEntry)
+		extractor := CodeListEntries(entries)
+
+		Convey("when extractor is called", func() {
+			err := extractor(&Result{Data: []interface{}{"2019", "jan", "January", "1"}})
+
+			Convey("then expected error is returned", func() {
+				So(err.Error(), ShouldEqual, "failed to cast value to requested type, expected \"int64\" but was type \"string\"")
+				So(entries, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestCodesOrder(t *testing.T) {
+	Convey("given results of codes with and without an order", t, func() {
+		codeOrders := make(map[string]*int)
+		extractor := CodesOrder(codeOrders)
+
+		Convey("when extractor is called", func() {
+			So(extractor(&Result{Data: []interface{}{"jan", int64(1)}}), ShouldBeNil)
+			So(extractor(&Result{Data: []interface{}{"feb", nil}}), ShouldBeNil)
+
+			Convey("then the orders are mapped by code", func() {
+				one := 1
+				So(codeOrders, ShouldResemble, map[string]*int{"jan": &one, "feb": nil})
+			})
+		})
+	})
+
+	Convey("given a result with an order that is not type int64", t, func() {
+		codeOrders := make(map[string]*int)
+		extractor := CodesOrder(codeOrders)
+
+		Convey("when extractor is called", func() {
+			err := extractor(&Result{Data: []interface{}{"jan", "1"}})
+
+			Convey("then expected error is returned", func() {
+				So(err.Error(), ShouldEqual, "failed to cast value to requested type, expected \"int64\" but was type \"string\"")
+			})
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strconv"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
 	"github.com/pkg/errors"
 )
@@ -75,6 +76,18 @@ func getStringProperty(key string, props map[string]interface{}) (string, error)
 	return strVal, nil
 }
 
+// getLabelProperty returns the label in the requested language from the properties of a node or relationship,
+// falling back to the label in the default language if it is not available.
+func getLabelProperty(language string, props map[string]interface{}) (string, error) {
+	if key := driver.LabelProperty(language); key != driver.LabelProperty(driver.DefaultLanguage) {
+		label, err := getStringProperty(key, props)
+		if err != nil || len(label) > 0 {
+			return label, err
+		}
+	}
+	return getStringProperty("label", props)
+}
+
 // getBoolProperty return requested key value from map as a string. If key not found returns empty string and nil,
 // returns casting error if val cannot be cast to string.
 func getBoolProperty(key string, props map[string]interface{}) (bool, error) {
@@ -113,6 +126,20 @@ func getint64Property(key string, props map[string]interface{}) (int64, error) {
 	return intVal, nil
 }
 
+// getOptionalInt64Property return requested key value from map as a *int64. If key not found returns nil and nil,
+// returns casting error if val cannot be cast to int64.
+func getOptionalInt64Property(key string, props map[string]interface{}) (*int64, error) {
+	if _, ok := props[key]; !ok {
+		return nil, nil
+	}
+
+	intVal, err := getint64Property(key, props)
+	if err != nil {
+		return nil, err
+	}
+	return &intVal, nil
+}
+
 func castingError(expected interface{}, actual interface{}) error {
 	t1 := reflect.TypeOf(expected).String()
 	t2 := reflect.TypeOf(actual).String()
@@ -146,3 +173,73 @@ func GetNodeID(id *string) ResultMapper {
 		return nil
 	}
 }
+
+// StringList returns dpbolt.ResultMapper for extracting a list of strings from a single result value
+func StringList(list *[]string) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 1 {
+			return errors.Errorf("get string list error: expecting single result value but %d returned", len(r.Data))
+		}
+
+		values, ok := r.Data[0].([]interface{})
+		if !ok {
+			return castingError([]interface{}{}, r.Data[0])
+		}
+
+		for _, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				return castingError("", v)
+			}
+			*list = append(*list, s)
+		}
+		return nil
+	}
+}
+
+// NodeIDCodes returns dpbolt.ResultMapper which adds the node id and code of a dpbolt.Result to the provided map,
+// keyed by node id
+func NodeIDCodes(ids map[string]string) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 2 {
+			return errors.Errorf("node id and code error: expecting two result values but %d returned", len(r.Data))
+		}
+
+		nodeID, ok := r.Data[0].(int64)
+		if !ok {
+			return castingError(int64(0), r.Data[0])
+		}
+
+		code, ok := r.Data[1].(string)
+		if !ok {
+			return castingError("", r.Data[1])
+		}
+
+		ids[strconv.FormatInt(nodeID, 10)] = code
+		return nil
+	}
+}
+
+// NodeIDs returns dpbolt.ResultMapper which adds a list of node ids from a single result value to the keys
+// of the provided map, with empty values
+func NodeIDs(ids map[string]string) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 1 {
+			return errors.Errorf("get node ids error: expecting single result value but %d returned", len(r.Data))
+		}
+
+		values, ok := r.Data[0].([]interface{})
+		if !ok {
+			return castingError([]interface{}{}, r.Data[0])
+		}
+
+		for _, v := range values {
+			nodeID, ok := v.(int64)
+			if !ok {
+				return castingError(int64(0), v)
+			}
+			ids[strconv.FormatInt(nodeID, 10)] = ""
+		}
+		return nil
+	}
+}
@@ -161,3 +161,104 @@ func TestGetStringProperty(t *testing.T) {
 		So(val, ShouldEqual, "aaa")
 	})
 }
+
+func TestGetLabelProperty(t *testing.T) {
+	props := map[string]interface{}{"label": "Wales", "label_cy": "Cymru"}
+
+	Convey("should return the default label if no language is requested", t, func() {
+		val, err := getLabelProperty("", props)
+		So(err, ShouldBeNil)
+		So(val, ShouldEqual, "Wales")
+	})
+
+	Convey("should return the default label if the default language is requested", t, func() {
+		val, err := getLabelProperty("en", props)
+		So(err, ShouldBeNil)
+		So(val, ShouldEqual, "Wales")
+	})
+
+	Convey("should return the label in the requested language if it exists in properties", t, func() {
+		val, err := getLabelProperty("cy", props)
+		So(err, ShouldBeNil)
+		So(val, ShouldEqual, "Cymru")
+	})
+
+	Convey("should fall back to the default label if the requested language does not exist in properties", t, func() {
+		val, err := getLabelProperty("gd", props)
+		So(err, ShouldBeNil)
+		So(val, ShouldEqual, "Wales")
+	})
+}
+
+func TestStringList(t *testing.T) {
+	Convey("given dpbolt.Result.Data contains a list of strings", t, func() {
+		r := &Result{Data: []interface{}{[]interface{}{"a", "b"}}}
+
+		Convey("when StringList is called", func() {
+			var list []string
+			err := StringList(&list)(r)
+
+			Convey("then the expected list is set and err is nil", func() {
+				So(err, ShouldBeNil)
+				So(list, ShouldResemble, []string{"a", "b"})
+			})
+		})
+	})
+
+	Convey("given dpbolt.Result.Data contains a list with a value that is not a string", t, func() {
+		r := &Result{Data: []interface{}{[]interface{}{"a", int64(1)}}}
+
+		Convey("when StringList is called", func() {
+			var list []string
+			err := StringList(&list)(r)
+
+			Convey("then the expected err is returned", func() {
+				So(err.Error(), ShouldResemble, "failed to cast value to requested type, expected \"string\" but was type \"int64\"")
+			})
+		})
+	})
+}
+
+func TestNodeIDCodes(t *testing.T) {
+	Convey("given dpbolt.Result.Data contains a node id and a code", t, func() {
+		r := &Result{Data: []interface{}{int64(12), "K02000001"}}
+
+		Convey("when NodeIDCodes is called", func() {
+			ids := make(map[string]string)
+			err := NodeIDCodes(ids)(r)
+
+			Convey("then the code is added to the map by node id and err is nil", func() {
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, map[string]string{"12": "K02000001"})
+			})
+		})
+	})
+
+	Convey("given dpbolt.Result.Data contains a node id that is not an int64", t, func() {
+		r := &Result{Data: []interface{}{"12", "K02000001"}}
+
+		Convey("when NodeIDCodes is called", func() {
+			err := NodeIDCodes(make(map[string]string))(r)
+
+			Convey("then the expected err is returned", func() {
+				So(err.Error(), ShouldResemble, "failed to cast value to requested type, expected \"int64\" but was type \"string\"")
+			})
+		})
+	})
+}
+
+func TestNodeIDs(t *testing.T) {
+	Convey("given dpbolt.Result.Data contains a list of node ids", t, func() {
+		r := &Result{Data: []interface{}{[]interface{}{int64(1), int64(2)}}}
+
+		Convey("when NodeIDs is called", func() {
+			ids := make(map[string]string)
+			err := NodeIDs(ids)(r)
+
+			Convey("then the node ids are added to the map and err is nil", func() {
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, map[string]string{"1": "", "2": ""})
+			})
+		})
+	})
+}
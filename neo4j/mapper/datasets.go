@@ -1,7 +1,11 @@
 package mapper
 
 import (
+	"strings"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
+	"github.com/pkg/errors"
 )
 
 // Datasets maps datasetIDs to dataset data
@@ -74,3 +78,42 @@ func CodesDatasets(datasets Datasets) ResultMapper {
 		return nil
 	}
 }
+
+// DatasetDimensions returns a dpbolt.ResultMapper which converts dpbolt.Result of code list labels, code list
+// edition and code count to a models.DatasetDimension, appending it to the provided models.DatasetDimensions
+func DatasetDimensions(dimensions *models.DatasetDimensions) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 3 {
+			return errors.Errorf("get dataset dimensions error: expecting three result values but %d returned", len(r.Data))
+		}
+
+		labels, ok := r.Data[0].([]interface{})
+		if !ok {
+			return castingError([]interface{}{}, r.Data[0])
+		}
+
+		var dimension models.DatasetDimension
+		for _, v := range labels {
+			label, ok := v.(string)
+			if !ok {
+				return castingError("", v)
+			}
+			if strings.HasPrefix(label, "_code_list_") {
+				// retrieve the codelist id from label
+				dimension.CodeListID = strings.TrimPrefix(label, "_code_list_")
+				break
+			}
+		}
+
+		if dimension.Edition, ok = r.Data[1].(string); !ok {
+			return castingError("", r.Data[1])
+		}
+
+		if dimension.CodeCount, ok = r.Data[2].(int64); !ok {
+			return castingError(int64(0), r.Data[2])
+		}
+
+		dimensions.Items = append(dimensions.Items, dimension)
+		return nil
+	}
+}
@@ -2,6 +2,8 @@ package mapper
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
@@ -34,8 +36,9 @@ func HierarchyCodelist(codelistID *string) ResultMapper {
 	}
 }
 
-// Hierarchy returns a dpbolt.ResultMapper mapper which converts dpbolt.Result to models.HierarchyResponse
-func Hierarchy(res *models.HierarchyResponse) ResultMapper {
+// Hierarchy returns a dpbolt.ResultMapper mapper which converts dpbolt.Result to models.HierarchyResponse,
+// with the label in the provided language where available
+func Hierarchy(res *models.HierarchyResponse, language string) ResultMapper {
 	return func(r *Result) error {
 		var node graph.Node
 		var err error
@@ -45,7 +48,7 @@ func Hierarchy(res *models.HierarchyResponse) ResultMapper {
 		}
 
 		var e *models.HierarchyElement
-		if e, err = createElement(node); err != nil {
+		if e, err = createElement(node, language); err != nil {
 			return err
 		}
 
@@ -53,14 +56,18 @@ func Hierarchy(res *models.HierarchyResponse) ResultMapper {
 		res.Label = e.Label
 		res.HasData = e.HasData
 		res.NoOfChildren = e.NoOfChildren
+		res.NoOfOptions = e.NoOfOptions
+		res.NoOfObservations = e.NoOfObservations
+		res.Order = e.Order
 
 		return nil
 
 	}
 }
 
-// HierarchyElement returns a dpbolt.ResultMapper mapper which converts dpbolt.Result to HierarchyElements
-func HierarchyElement(list *HierarchyElements) ResultMapper {
+// HierarchyElement returns a dpbolt.ResultMapper mapper which converts dpbolt.Result to HierarchyElements,
+// with labels in the provided language where available
+func HierarchyElement(list *HierarchyElements, language string) ResultMapper {
 	return func(r *Result) error {
 		var node graph.Node
 		var err error
@@ -70,7 +77,7 @@ func HierarchyElement(list *HierarchyElements) ResultMapper {
 		}
 
 		var e *models.HierarchyElement
-		if e, err = createElement(node); err != nil {
+		if e, err = createElement(node, language); err != nil {
 			return err
 		}
 
@@ -80,13 +87,13 @@ func HierarchyElement(list *HierarchyElements) ResultMapper {
 	}
 }
 
-func createElement(node graph.Node) (*models.HierarchyElement, error) {
+func createElement(node graph.Node, language string) (*models.HierarchyElement, error) {
 	id, err := getStringProperty("code", node.Properties)
 	if err != nil {
 		return nil, errors.New("code property not found")
 	}
 
-	label, err := getStringProperty("label", node.Properties)
+	label, err := getLabelProperty(language, node.Properties)
 	if err != nil {
 		return nil, errors.New("label property not found")
 	}
@@ -101,10 +108,132 @@ func createElement(node graph.Node) (*models.HierarchyElement, error) {
 		return nil, errors.New("numberOfChildren property not found")
 	}
 
+	options, err := getOptionalInt64Property("numberOfOptions", node.Properties)
+	if err != nil {
+		return nil, errors.New("numberOfOptions property is invalid")
+	}
+
+	observations, err := getOptionalInt64Property("numberOfObservations", node.Properties)
+	if err != nil {
+		return nil, errors.New("numberOfObservations property is invalid")
+	}
+
+	order, err := getOptionalInt64Property("order", node.Properties)
+	if err != nil {
+		return nil, errors.New("order property is invalid")
+	}
+
 	return &models.HierarchyElement{
-		ID:           id,
-		Label:        label,
-		HasData:      hasData,
-		NoOfChildren: children,
+		ID:               id,
+		Label:            label,
+		HasData:          hasData,
+		NoOfChildren:     children,
+		NoOfOptions:      options,
+		NoOfObservations: observations,
+		Order:            order,
 	}, nil
 }
+
+// HierarchyIntegrityNodes returns a dpbolt.ResultMapper which appends the code and numberOfChildren
+// of a hierarchy node to the provided list
+func HierarchyIntegrityNodes(nodes *[]models.HierarchyIntegrityNode) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 2 {
+			return fmt.Errorf("hierarchy integrity node error: expecting two result values but %d returned", len(r.Data))
+		}
+
+		var node models.HierarchyIntegrityNode
+		var ok bool
+		if node.Code, ok = r.Data[0].(string); !ok {
+			return castingError("", r.Data[0])
+		}
+
+		// numberOfChildren is only set on instance hierarchy nodes
+		if r.Data[1] != nil {
+			numberOfChildren, ok := r.Data[1].(int64)
+			if !ok {
+				return castingError(int64(0), r.Data[1])
+			}
+			node.NumberOfChildren = &numberOfChildren
+		}
+
+		*nodes = append(*nodes, node)
+		return nil
+	}
+}
+
+// HierarchyEdges returns a dpbolt.ResultMapper which appends the codes of a hierarchy node and
+// its parent to the provided list
+func HierarchyEdges(edges *[]models.HierarchyEdge) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 2 {
+			return fmt.Errorf("hierarchy edge error: expecting two result values but %d returned", len(r.Data))
+		}
+
+		var edge models.HierarchyEdge
+		var ok bool
+		if edge.Code, ok = r.Data[0].(string); !ok {
+			return castingError("", r.Data[0])
+		}
+		if edge.ParentCode, ok = r.Data[1].(string); !ok {
+			return castingError("", r.Data[1])
+		}
+
+		*edges = append(*edges, edge)
+		return nil
+	}
+}
+
+// HierarchySyncNodes returns a dpbolt.ResultMapper which appends a hierarchy node, with its label properties,
+// the codes of its parents and its order, to the provided list
+func HierarchySyncNodes(nodes *[]models.HierarchySyncNode) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 3 {
+			return fmt.Errorf("hierarchy sync node error: expecting three result values but %d returned", len(r.Data))
+		}
+
+		node, err := getNode(r.Data[0])
+		if err != nil {
+			return err
+		}
+
+		var syncNode models.HierarchySyncNode
+		if syncNode.Code, err = getStringProperty("code", node.Properties); err != nil {
+			return err
+		}
+
+		syncNode.Labels = make(map[string]string)
+		for key := range node.Properties {
+			if key != "label" && !strings.HasPrefix(key, "label_") {
+				continue
+			}
+			if syncNode.Labels[key], err = getStringProperty(key, node.Properties); err != nil {
+				return err
+			}
+		}
+
+		parents, ok := r.Data[1].([]interface{})
+		if !ok {
+			return castingError([]interface{}{}, r.Data[1])
+		}
+		syncNode.ParentCodes = make([]string, 0, len(parents))
+		for _, p := range parents {
+			parent, ok := p.(string)
+			if !ok {
+				return castingError("", p)
+			}
+			syncNode.ParentCodes = append(syncNode.ParentCodes, parent)
+		}
+
+		if r.Data[2] != nil {
+			order, ok := r.Data[2].(int64)
+			if !ok {
+				return castingError(int64(0), r.Data[2])
+			}
+			syncNode.Order = &order
+		}
+
+		*nodes = append(*nodes, syncNode)
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/golang-neo4j-bolt-driver/structures/graph"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func hierarchyNode(code string, order interface{}) graph.Node {
+	props := map[string]interface{}{
+		"code":             code,
+		"label":            code + " label",
+		"hasData":          true,
+		"numberOfChildren": int64(0),
+	}
+	if order != nil {
+		props["order"] = order
+	}
+	return graph.Node{Properties: props}
+}
+
+func TestHierarchyElement(t *testing.T) {
+	Convey("Given a result with a hierarchy node that has an order and one that does not", t, func() {
+		list := &HierarchyElements{}
+		mapper := HierarchyElement(list, "")
+
+		Convey("When the mapper is called for each node", func() {
+			err := mapper(&Result{Data: []interface{}{hierarchyNode("a", int64(2))}})
+			So(err, ShouldBeNil)
+			err = mapper(&Result{Data: []interface{}{hierarchyNode("b", nil)}})
+			So(err, ShouldBeNil)
+
+			Convey("Then the order is read when the node has one", func() {
+				order := int64(2)
+				So(list.List, ShouldResemble, []*models.HierarchyElement{
+					{ID: "a", Label: "a label", HasData: true, Order: &order},
+					{ID: "b", Label: "b label", HasData: true},
+				})
+			})
+		})
+	})
+
+	Convey("Given a result with a hierarchy node with an invalid order", t, func() {
+		mapper := HierarchyElement(&HierarchyElements{}, "")
+
+		Convey("When the mapper is called", func() {
+			err := mapper(&Result{Data: []interface{}{hierarchyNode("a", "two")}})
+
+			Convey("Then an error is returned", func() {
+				So(err.Error(), ShouldEqual, "order property is invalid")
+			})
+		})
+	})
+}
+
+func TestHierarchy(t *testing.T) {
+	Convey("Given a result with a hierarchy node that has an order", t, func() {
+		res := &models.HierarchyResponse{}
+		mapper := Hierarchy(res, "")
+
+		Convey("When the mapper is called", func() {
+			err := mapper(&Result{Data: []interface{}{hierarchyNode("a", int64(3))}})
+
+			Convey("Then the order is copied to the response", func() {
+				So(err, ShouldBeNil)
+				So(res.ID, ShouldEqual, "a")
+				So(*res.Order, ShouldEqual, 3)
+			})
+		})
+	})
+
+	Convey("Given a result with a hierarchy node that does not have an order", t, func() {
+		res := &models.HierarchyResponse{}
+		mapper := Hierarchy(res, "")
+
+		Convey("When the mapper is called", func() {
+			err := mapper(&Result{Data: []interface{}{hierarchyNode("a", nil)}})
+
+			Convey("Then the order is nil", func() {
+				So(err, ShouldBeNil)
+				So(res.Order, ShouldBeNil)
+			})
+		})
+	})
+}
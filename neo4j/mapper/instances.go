@@ -0,0 +1,103 @@
+package mapper
+
+import (
+	"strings"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/pkg/errors"
+)
+
+// Instance returns a dpbolt.ResultMapper which converts a dpbolt.Result holding an instance node to a models.Instance
+func Instance(instance *models.Instance) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 1 {
+			return errors.Errorf("get instance error: expecting single result value but %d returned", len(r.Data))
+		}
+
+		i, err := nodeToInstance(r.Data[0])
+		if err != nil {
+			return err
+		}
+
+		*instance = *i
+		return nil
+	}
+}
+
+// Instances returns a dpbolt.ResultMapper which appends the instance node of each dpbolt.Result to a list of instances
+func Instances(instances *[]*models.Instance) ResultMapper {
+	return func(r *Result) error {
+		if len(r.Data) != 1 {
+			return errors.Errorf("list instances error: expecting single result value but %d returned", len(r.Data))
+		}
+
+		i, err := nodeToInstance(r.Data[0])
+		if err != nil {
+			return err
+		}
+
+		*instances = append(*instances, i)
+		return nil
+	}
+}
+
+func nodeToInstance(val interface{}) (*models.Instance, error) {
+	node, err := getNode(val)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &models.Instance{}
+	for _, label := range node.Labels {
+		// retrieve the instance id from the `_<id>_Instance` label
+		if strings.HasPrefix(label, "_") && strings.HasSuffix(label, "_Instance") {
+			instance.InstanceID = strings.TrimSuffix(strings.TrimPrefix(label, "_"), "_Instance")
+			break
+		}
+	}
+	if len(instance.InstanceID) == 0 {
+		return nil, errors.Errorf("node with labels %v is not an instance", node.Labels)
+	}
+
+	header, err := getStringProperty("header", node.Properties)
+	if err != nil {
+		return nil, err
+	}
+	instance.CSVHeader = strings.Split(header, ",")
+
+	if dimensions, ok := node.Properties["dimensions"]; ok {
+		if instance.Dimensions, ok = dimensions.([]interface{}); !ok {
+			return nil, castingError([]interface{}{}, dimensions)
+		}
+	}
+
+	if instance.DatasetID, err = getStringProperty("dataset_id", node.Properties); err != nil {
+		return nil, err
+	}
+
+	if instance.Edition, err = getStringProperty("edition", node.Properties); err != nil {
+		return nil, err
+	}
+
+	version, err := getint64Property("version", node.Properties)
+	if err != nil {
+		return nil, err
+	}
+	instance.Version = int(version)
+
+	if instance.IsPublished, err = getBoolProperty("is_published", node.Properties); err != nil {
+		return nil, err
+	}
+
+	state, err := getStringProperty("publish_state", node.Properties)
+	if err != nil {
+		return nil, err
+	}
+	instance.PublishState = models.PublishState(state)
+	// instances published before publish states were introduced only have the is_published flag
+	if len(instance.PublishState) == 0 && instance.IsPublished {
+		instance.PublishState = models.PublishStatePublished
+	}
+
+	return instance, nil
+}
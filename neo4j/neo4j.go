@@ -26,33 +26,51 @@ type Neo4j struct {
 	timeout    int
 }
 
-// New sets reasonable neo4j specific defaults and instantiates a driver
-func New(dbAddr string, size, timeout, retries int) (n *Neo4j, err error) {
+// New sets reasonable neo4j specific defaults for the options not provided and instantiates a driver
+func New(dbAddr string, opts ...Option) (n *Neo4j, err error) {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	//set defaults if not provided
-	if size == 0 {
-		size = 30
+	if options.PoolSize == 0 {
+		options.PoolSize = 30
 	}
 
-	if timeout == 0 {
-		timeout = 60
+	if options.Timeout == 0 {
+		options.Timeout = 60
 	}
 
-	if retries == 0 {
-		retries = 5
+	if options.MaxRetries == 0 {
+		options.MaxRetries = 5
 	}
 
-	d, err := driver.New(dbAddr, size, timeout)
+	d, err := driver.New(dbAddr, options.PoolSize, options.Timeout,
+		driver.WithMaxLifetime(options.PoolMaxLifetime),
+		driver.WithIdleTimeout(options.PoolIdleTimeout))
 	if err != nil {
 		return nil, err
 	}
 
 	return &Neo4j{
 		d,
-		retries,
-		timeout,
+		options.MaxRetries,
+		options.Timeout,
 	}, nil
 }
 
+// Capabilities returns the optional features supported by neo4j. Codes are not counted
+// and statements are each executed in their own transaction.
+func (n *Neo4j) Capabilities() graph.Capabilities {
+	return graph.Capabilities{
+		graph.CapabilityOrderedCodes,
+		graph.CapabilitySparseHierarchy,
+		graph.CapabilityStreaming,
+		graph.CapabilityBulkLoad,
+	}
+}
+
 func (n *Neo4j) checkAttempts(err error, instanceID string, attempt int) error {
 	ctx := context.Background()
 	if !isTransientError(err) {
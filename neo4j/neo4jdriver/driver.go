@@ -3,6 +3,8 @@ package neo4jdriver
 import (
 	"context"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
@@ -42,29 +44,112 @@ type Neo4jDriver interface {
 
 // NeoDriver contains a connection pool and allows basic interaction with the database
 type NeoDriver struct {
-	pool ClosableDriverPool // TODO Use 'bolt.ClosableDriverPool' if it exports only public fields in the future
+	pools *driver.PoolRotator[ClosableDriverPool] // TODO Use 'bolt.ClosableDriverPool' if it exports only public fields in the future
+	stats *driver.PoolStatsRecorder
+}
+
+// PoolOptions holds the options that can be provided to New
+type PoolOptions struct {
+	// IdleTimeout is the time after which the connections of the pool are closed if none have been used, 0 meaning never
+	IdleTimeout time.Duration
+	// MaxLifetime is the time after which the connections of the pool are replaced by new ones, 0 meaning never
+	MaxLifetime time.Duration
+}
+
+// Option sets an option of New
+type Option func(*PoolOptions)
+
+// WithIdleTimeout closes the connections of the pool once none have been used for the provided time
+func WithIdleTimeout(idleTimeout time.Duration) Option {
+	return func(o *PoolOptions) {
+		o.IdleTimeout = idleTimeout
+	}
+}
+
+// WithMaxLifetime replaces the connections of the pool by new ones once they have been open for the provided time.
+// The connections replaced are closed once none of them are in use.
+func WithMaxLifetime(maxLifetime time.Duration) Option {
+	return func(o *PoolOptions) {
+		o.MaxLifetime = maxLifetime
+	}
 }
 
 // New neo4j closeable connection pool configured with the provided address,
 // connection pool size and timeout
-func New(dbAddr string, size, timeout int) (n *NeoDriver, err error) {
-	pool, err := bolt.NewClosableDriverPoolWithTimeout(dbAddr, size, timeout)
+func New(dbAddr string, size, timeout int, opts ...Option) (n *NeoDriver, err error) {
+	options := &PoolOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	newPool := func() (ClosableDriverPool, error) {
+		return bolt.NewClosableDriverPoolWithTimeout(dbAddr, size, timeout)
+	}
+	pool, err := newPool()
 	if err != nil {
 		return nil, err
 	}
-	return NewWithPool(pool), nil
+
+	pools := driver.NewPoolRotator(pool, newPool, closePool, options.IdleTimeout, options.MaxLifetime)
+	return &NeoDriver{
+		pools: pools,
+		stats: driver.NewPoolStatsRecorder(size, pools.Open),
+	}, nil
 }
 
 // NewWithPool : New NeoDriver structure containing the pool passed as parameter and a new Check
 func NewWithPool(pool ClosableDriverPool) (n *NeoDriver) {
+	pools := driver.NewPoolRotator(pool, nil, closePool, 0, 0)
 	return &NeoDriver{
-		pool: pool,
+		pools: pools,
+		stats: driver.NewPoolStatsRecorder(0, pools.Open),
 	}
 }
 
+func closePool(pool ClosableDriverPool) error {
+	return pool.Close()
+}
+
 //Close the contained connection pool
 func (n *NeoDriver) Close(ctx context.Context) error {
-	return n.pool.Close()
+	return n.pools.Close()
+}
+
+// Stats returns statistics about the usage of the connection pool
+func (n *NeoDriver) Stats() driver.PoolStats {
+	return n.stats.Stats()
+}
+
+// openConn gets a connection from the pool, recording its usage. Closing the connection releases it back into the pool.
+func (n *NeoDriver) openConn() (bolt.Conn, error) {
+	pool, release := n.pools.Acquire()
+
+	var c bolt.Conn
+	err := n.stats.Acquire(func() (err error) {
+		c, err = pool.OpenPool()
+		return err
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &pooledConn{Conn: c, release: func() {
+		n.stats.Release()
+		release()
+	}}, nil
+}
+
+// pooledConn is a connection of the pool which records that it is no longer in use when closed
+type pooledConn struct {
+	bolt.Conn
+	release func()
+	once    sync.Once
+}
+
+// Close the connection, releasing it back into the pool
+func (c *pooledConn) Close() error {
+	defer c.once.Do(c.release)
+	return c.Conn.Close()
 }
 
 // ReadWithParams takes a query, a map of parameters and an indicator of whether
@@ -80,7 +165,7 @@ func (n *NeoDriver) Read(query string, mapp mapper.ResultMapper, single bool) er
 }
 
 func (n *NeoDriver) read(query string, params map[string]interface{}, mapp mapper.ResultMapper, single bool) error {
-	c, err := n.pool.OpenPool()
+	c, err := n.openConn()
 	if err != nil {
 		return err
 	}
@@ -128,7 +213,7 @@ results:
 // The Reader will contain reference to the database connection and must be closed
 // by the caller.
 func (n *NeoDriver) StreamRows(query string) (*BoltRowReader, error) {
-	conn, err := n.pool.OpenPool()
+	conn, err := n.openConn()
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +232,7 @@ func (n *NeoDriver) StreamRows(query string) (*BoltRowReader, error) {
 
 // Count nodes returned by the provided query
 func (n *NeoDriver) Count(query string) (count int64, err error) {
-	c, err := n.pool.OpenPool()
+	c, err := n.openConn()
 	if err != nil {
 		return
 	}
@@ -175,7 +260,7 @@ func (n *NeoDriver) Count(query string) (count int64, err error) {
 
 // Exec executes the provided query with relevant parameters and returns the response directly
 func (n *NeoDriver) Exec(query string, params map[string]interface{}) (bolt.Result, error) {
-	c, err := n.pool.OpenPool()
+	c, err := n.openConn()
 	if err != nil {
 		return nil, err
 	}
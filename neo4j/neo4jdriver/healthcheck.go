@@ -4,6 +4,7 @@ import (
 	"context"
 
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/log.go/v2/log"
 )
 
 // ServiceName : neo4j
@@ -12,11 +13,14 @@ const ServiceName = "neo4j"
 // MsgHealthy Check message returned when Neo4j is healthy
 const MsgHealthy = "Neo4j is healthy"
 
+// MsgPoolExhausted Check message returned when Neo4j is healthy but all the connections of the pool are in use
+const MsgPoolExhausted = "Neo4j is healthy but all the connections of the pool are in use"
+
 const pingStmt = "MATCH (i) RETURN i LIMIT 1"
 
 // Healthcheck calls neo4j to check its health status.
 func (n *NeoDriver) Healthcheck() (string, error) {
-	conn, err := n.pool.OpenPool()
+	conn, err := n.openConn()
 	if err != nil {
 		return ServiceName, err
 	}
@@ -35,6 +39,10 @@ func (n *NeoDriver) Healthcheck() (string, error) {
 // Checker hecks health of Neo4j and updates the provided CheckState accordingly
 func (n *NeoDriver) Checker(ctx context.Context, state *health.CheckState) error {
 
+	// Get the pool statistics before the healthcheck uses a connection
+	stats := n.Stats()
+	log.Info(ctx, "neo4j connection pool statistics", stats.LogData())
+
 	// Perform healthcheck
 	_, err := n.Healthcheck()
 
@@ -44,6 +52,12 @@ func (n *NeoDriver) Checker(ctx context.Context, state *health.CheckState) error
 		return nil
 	}
 
+	// Exhausted pool is mapped to Warning status, as queries are waiting for connections
+	if stats.IsExhausted() {
+		state.Update(health.StatusWarning, MsgPoolExhausted, 0)
+		return nil
+	}
+
 	// Success healthcheck is mapped to OK status
 	state.Update(health.StatusOK, MsgHealthy, 0)
 	return nil
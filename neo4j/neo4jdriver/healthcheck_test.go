@@ -115,3 +115,35 @@ func TestNeo4jHealthQueryFailed(t *testing.T) {
 		})
 	})
 }
+
+func TestNeo4jStats(t *testing.T) {
+	Convey("Given a Neo4j driver with a healthy connection", t, func() {
+		conn := &internal.BoltConnMock{
+			CloseFunc:    closeSuccess,
+			QueryNeoFunc: queryNeoSuccess,
+		}
+
+		mockPool := &internal.ClosableDriverPoolMock{
+			OpenPoolFunc: func() (bolt.Conn, error) {
+				return conn, nil
+			},
+		}
+		d := driver.NewWithPool(mockPool)
+
+		Convey("When a stream of rows is opened", func() {
+			reader, err := d.StreamRows("MATCH (i) RETURN i")
+			So(err, ShouldBeNil)
+
+			Convey("Then its connection is in use until the stream is closed", func() {
+				So(d.Stats().InUse, ShouldEqual, 1)
+
+				So(reader.Close(context.Background()), ShouldBeNil)
+				stats := d.Stats()
+				So(stats.InUse, ShouldEqual, 0)
+				So(stats.Open, ShouldEqual, 1)
+				So(stats.Idle, ShouldEqual, 1)
+				So(len(conn.CloseCalls()), ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -6,7 +6,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/query"
 	"github.com/ONSdigital/dp-graph/v2/observation"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/pkg/errors"
@@ -15,7 +18,15 @@ import (
 // StreamCSVRows returns a reader allowing individual CSV rows to be read.
 // Rows returned can be limited, to stop this pass in nil. If filter.DimensionFilters
 // is nil, empty or contains only empty values then a StreamRowReader for the entire dataset will be returned.
+// driver.ErrInstanceUnpublished is returned if the instance has been unpublished.
 func (n *Neo4j) StreamCSVRows(ctx context.Context, instanceID, filterID string, filters *observation.DimensionFilters, limit *int) (observation.StreamRowReader, error) {
+	unpublished, err := n.Count(fmt.Sprintf(query.CountUnpublishedInstance, instanceID))
+	if err != nil {
+		return nil, errors.Wrap(err, "neo4j.Count returned an error")
+	}
+	if unpublished > 0 {
+		return nil, driver.ErrInstanceUnpublished
+	}
 
 	headerRowQuery := fmt.Sprintf("MATCH (i:`_%s_Instance`) RETURN i.header as row", instanceID)
 
@@ -76,11 +87,12 @@ func createOptionList(name string, opts []string) string {
 	return fmt.Sprintf("`%s`.value IN [%s]", name, strings.Join(q, ","))
 }
 
-// InsertObservationBatch creates a batch query based on a provided list of
-// observations and attempts to insert them by bulk to the database
+// InsertObservationBatch inserts a batch of observations using a single parameterised
+// statement. The statement does not depend on the dimensions of the observations, so the
+// same query is sent for every batch of an instance and can be cached by the database.
+// Observations are merged on their row index, so a batch can safely be inserted again.
 func (n *Neo4j) InsertObservationBatch(ctx context.Context, attempt int, instanceID string, observations []*models.Observation, dimensionIDs map[string]string) error {
-	query := buildInsertObservationQuery(instanceID, observations)
-	if len(query) == 0 {
+	if len(instanceID) == 0 || len(observations) == 0 {
 		return errors.New("failed to create query for batch")
 	}
 
@@ -89,7 +101,9 @@ func (n *Neo4j) InsertObservationBatch(ctx context.Context, attempt int, instanc
 		return errors.Wrap(err, "failed to create query parameters for batch query")
 	}
 
-	queryResult, err := n.Exec(query, queryParameters)
+	stmt := fmt.Sprintf(query.InsertObservationBatch, instanceID)
+
+	queryResult, err := n.Exec(stmt, queryParameters)
 	if err != nil {
 		if neoErr := n.checkAttempts(err, instanceID, attempt); neoErr != nil {
 			return errors.Wrap(err, "observation batch save failed")
@@ -113,17 +127,15 @@ func (n *Neo4j) InsertObservationBatch(ctx context.Context, attempt int, instanc
 	return nil
 }
 
-// createParams creates parameters to inject into an insert query for each observation.
+// createParams creates the rows parameter for the insert query. Each row holds the
+// observation value, its row index and the node IDs of its dimension options.
 func createParams(observations []*models.Observation, dimensionIDs map[string]string) (map[string]interface{}, error) {
 
 	rows := make([]interface{}, 0)
 
 	for _, observation := range observations {
 
-		row := map[string]interface{}{
-			"v": observation.Row,
-			"i": observation.RowIndex,
-		}
+		nodeIDs := make([]string, 0, len(observation.DimensionOptions))
 
 		for _, option := range observation.DimensionOptions {
 
@@ -136,45 +148,46 @@ func createParams(observations []*models.Observation, dimensionIDs map[string]st
 				return nil, fmt.Errorf("No nodeId found for %s", dimensionLookUp)
 			}
 
-			row[dimensionName] = nodeID
+			nodeIDs = append(nodeIDs, nodeID)
 		}
 
-		rows = append(rows, row)
+		rows = append(rows, map[string]interface{}{
+			"v": observation.Row,
+			"i": observation.RowIndex,
+			"d": nodeIDs,
+		})
 	}
 
 	return map[string]interface{}{"rows": rows}, nil
 }
 
-// buildInsertObservationQuery creates an instance specific insert query.
-func buildInsertObservationQuery(instanceID string, observations []*models.Observation) string {
-	if len(instanceID) == 0 || len(observations) == 0 {
-		return ""
+// RecordImportCheckpoint adds a checkpoint for an inserted batch to the instance node,
+// unless the same checkpoint has already been recorded
+func (n *Neo4j) RecordImportCheckpoint(ctx context.Context, instanceID string, checkpoint *models.ImportCheckpoint) error {
+	if len(instanceID) == 0 {
+		return errors.New("instance id is required but was empty")
+	}
+	if err := checkpoint.Validate(); err != nil {
+		return err
 	}
 
-	query := "UNWIND $rows AS row"
-
-	match := " MATCH "
-	where := " WHERE "
-	create := fmt.Sprintf(" CREATE (o:`_%s_observation` { value:row.v, rowIndex:row.i }), ", instanceID)
-
-	index := 0
+	stmt := fmt.Sprintf(query.AddImportCheckpoint, instanceID)
+	params := map[string]interface{}{"checkpoint": checkpoint.String()}
 
-	for _, option := range observations[0].DimensionOptions {
+	if _, err := n.Exec(stmt, params); err != nil {
+		return errors.Wrap(err, "neo4j.Exec returned an error")
+	}
 
-		if index != 0 {
-			match += ", "
-			where += " AND "
-			create += ", "
-		}
-		optionName := strings.ToLower(option.DimensionName)
+	log.Info(ctx, "recorded import checkpoint", log.Data{"instance_id": instanceID, "checkpoint": checkpoint.String()})
+	return nil
+}
 
-		match += fmt.Sprintf("(`%s`:`_%s_%s`)", optionName, instanceID, optionName)
-		where += fmt.Sprintf("id(`%s`) = toInt(row.`%s`)", optionName, optionName)
-		create += fmt.Sprintf("(o)-[:isValueOf]->(`%s`)", optionName)
-		index++
+// GetImportProgress returns the checkpoints recorded for the instance
+func (n *Neo4j) GetImportProgress(ctx context.Context, instanceID string) (*models.ImportProgress, error) {
+	var checkpoints []string
+	if err := n.Read(fmt.Sprintf(query.GetImportCheckpoints, instanceID), mapper.StringList(&checkpoints), true); err != nil {
+		return nil, err
 	}
 
-	query += match + where + create
-
-	return query
+	return models.NewImportProgress(instanceID, checkpoints)
 }
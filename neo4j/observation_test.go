@@ -9,6 +9,7 @@ import (
 	graph "github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neo4j/internal"
+	"github.com/ONSdigital/dp-graph/v2/neo4j/mapper"
 	driver "github.com/ONSdigital/dp-graph/v2/neo4j/neo4jdriver"
 	"github.com/ONSdigital/dp-graph/v2/observation"
 	bolt "github.com/ONSdigital/golang-neo4j-bolt-driver"
@@ -55,6 +56,9 @@ func Test_StreamCSVRows(t *testing.T) {
 		}
 
 		driver := &internal.Neo4jDriverMock{
+			CountFunc: func(query string) (int64, error) {
+				return 0, nil
+			},
 			StreamRowsFunc: func(query string) (*driver.BoltRowReader, error) {
 				return driver.NewBoltRowReader(mockBoltRows, mockConnNoErr), nil
 			},
@@ -104,6 +108,30 @@ func Test_StreamCSVRows(t *testing.T) {
 	})
 }
 
+func Test_StreamCSVRowsUnpublished(t *testing.T) {
+
+	Convey("Given an store with a mock DB connection and an instance that has been unpublished", t, func() {
+		driver := &internal.Neo4jDriverMock{
+			CountFunc: func(query string) (int64, error) {
+				return 1, nil
+			},
+		}
+
+		db := &Neo4j{driver, 5, 30}
+
+		Convey("When StreamCSVRows is called", func() {
+			rowReader, err := db.StreamCSVRows(context.Background(), "888", filterID, &observation.DimensionFilters{}, nil)
+
+			Convey("Then ErrInstanceUnpublished is returned and no rows are streamed", func() {
+				So(rowReader, ShouldBeNil)
+				So(err, ShouldEqual, graph.ErrInstanceUnpublished)
+				So(driver.CountCalls()[0].Query, ShouldEqual, "MATCH (i:`_888_Instance`) WHERE i.publish_state = 'unpublished' RETURN COUNT(*)")
+				So(driver.StreamRowsCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
 func Test_StreamCSVRowsEmptyFilter(t *testing.T) {
 	filterID := "1234567890"
 	instanceID := "0987654321"
@@ -124,6 +152,9 @@ func Test_StreamCSVRowsEmptyFilter(t *testing.T) {
 		}
 
 		driver := &internal.Neo4jDriverMock{
+			CountFunc: func(query string) (int64, error) {
+				return 0, nil
+			},
 			StreamRowsFunc: func(query string) (*driver.BoltRowReader, error) {
 				return driver.NewBoltRowReader(mockBoltRows, mockConnNoErr), nil
 			},
@@ -192,6 +223,9 @@ func Test_StreamCSVRowsDimensionEmpty(t *testing.T) {
 		}
 
 		driver := &internal.Neo4jDriverMock{
+			CountFunc: func(query string) (int64, error) {
+				return 0, nil
+			},
 			StreamRowsFunc: func(query string) (*driver.BoltRowReader, error) {
 				return driver.NewBoltRowReader(mockBoltRows, mockConnNoErr), nil
 			},
@@ -258,29 +292,27 @@ func Test_InsertObservationBatch(t *testing.T) {
 	instanceID := "123"
 
 	expectedQuery := "UNWIND $rows AS row" +
-		" MATCH (`sex`:`_123_sex`), (`age`:`_123_age`)" +
-		" WHERE id(`sex`) = toInt(row.`sex`) AND id(`age`) = toInt(row.`age`)" +
-		" CREATE (o:`_123_observation` { value:row.v, rowIndex:row.i }), (o)-[:isValueOf]->(`sex`), (o)-[:isValueOf]->(`age`)"
+		" MERGE (o:`_123_observation` { rowIndex:row.i }) SET o.value = row.v" +
+		" WITH o, row UNWIND row.d AS dimensionNodeID" +
+		" MATCH (d) WHERE id(d) = toInt(dimensionNodeID)" +
+		" MERGE (o)-[:isValueOf]->(d)"
 
 	expectedParams := make(map[string]interface{})
 	rows := make([]interface{}, 0)
 	rows = append(rows, map[string]interface{}{
-		"v":   "the,row,content",
-		"i":   int64(5678),
-		"sex": "333",
-		"age": "666",
+		"v": "the,row,content",
+		"i": int64(5678),
+		"d": []string{"333", "666"},
 	},
 		map[string]interface{}{
-			"v":   "the,row,content",
-			"i":   int64(5678),
-			"sex": "333",
-			"age": "666",
+			"v": "the,row,content",
+			"i": int64(5678),
+			"d": []string{"333", "666"},
 		},
 		map[string]interface{}{
-			"v":   "the,row,content",
-			"i":   int64(5678),
-			"sex": "333",
-			"age": "666",
+			"v": "the,row,content",
+			"i": int64(5678),
+			"d": []string{"333", "666"},
 		},
 	)
 
@@ -467,10 +499,9 @@ func Test_createParams(t *testing.T) {
 			Convey("Then the returned map should contain new rows", func() {
 				rows := make([]interface{}, 0)
 				rows = append(rows, map[string]interface{}{
-					"v":   "the,row,content",
-					"i":   int64(5678),
-					"sex": "333",
-					"age": "666",
+					"v": "the,row,content",
+					"i": int64(5678),
+					"d": []string{"333", "666"},
 				})
 
 				So(err, ShouldBeNil)
@@ -490,22 +521,19 @@ func Test_createParams(t *testing.T) {
 			Convey("Then the returned map should contain 3 new rows", func() {
 				rows := make([]interface{}, 0)
 				rows = append(rows, map[string]interface{}{
-					"v":   "the,row,content",
-					"i":   int64(5678),
-					"sex": "333",
-					"age": "666",
+					"v": "the,row,content",
+					"i": int64(5678),
+					"d": []string{"333", "666"},
 				},
 					map[string]interface{}{
-						"v":   "the,row,content",
-						"i":   int64(5678),
-						"sex": "333",
-						"age": "666",
+						"v": "the,row,content",
+						"i": int64(5678),
+						"d": []string{"333", "666"},
 					},
 					map[string]interface{}{
-						"v":   "the,row,content",
-						"i":   int64(5678),
-						"sex": "333",
-						"age": "666",
+						"v": "the,row,content",
+						"i": int64(5678),
+						"d": []string{"333", "666"},
 					})
 
 				So(err, ShouldBeNil)
@@ -558,116 +586,102 @@ func Test_createParams(t *testing.T) {
 
 }
 
-func Test_buildInsertObservationQuery(t *testing.T) {
-	Convey("Given an instance ID and observations for one dimension", t, func() {
-		id := "123"
-		obs := []*models.Observation{
-			&models.Observation{
-				InstanceID: "123",
-				DimensionOptions: []*models.DimensionOption{
-					&models.DimensionOption{
-						DimensionName: "age",
-					},
-				},
-			},
-		}
-
-		Convey("When buildInsertObservationQuery is called", func() {
-			r := buildInsertObservationQuery(id, obs)
+func assertEmptyFilterResults(reader observation.StreamRowReader, expectedCSVRow string, err error) {
+	Convey("The expected result is returned with no error", func() {
+		So(err, ShouldBeNil)
+		row, _ := reader.Read()
+		So(row, ShouldEqual, expectedCSVRow+"\n")
+	})
+}
 
-			Convey("Then a valid query string is returned", func() {
-				So(r, ShouldNotBeEmpty)
-				So(r, ShouldEqual, "UNWIND $rows AS row MATCH (`age`:`_123_age`) WHERE id(`age`) = toInt(row.`age`) CREATE (o:`_123_observation` { value:row.v, rowIndex:row.i }), (o)-[:isValueOf]->(`age`)")
-			})
-		})
+func assertEmptyFilterQueryInvocations(d *internal.Neo4jDriverMock, expectedQuery string) {
+	Convey("Then the expected query is sent to the database one time", func() {
+		So(len(d.StreamRowsCalls()), ShouldEqual, 1)
+		So(d.StreamRowsCalls()[0].Query, ShouldEqual, expectedQuery)
 	})
+}
 
-	Convey("Given an instance ID and observations for two dimensions", t, func() {
-		id := "123"
-		obs := []*models.Observation{
-			&models.Observation{
-				InstanceID: "123",
-				DimensionOptions: []*models.DimensionOption{
-					&models.DimensionOption{
-						DimensionName: "age",
-					},
-					&models.DimensionOption{
-						DimensionName: "time",
-					},
-				},
+func Test_RecordImportCheckpoint(t *testing.T) {
+	checkpoint := &models.ImportCheckpoint{MinRowIndex: 1, MaxRowIndex: 100, Count: 100, Checksum: 1234}
+
+	Convey("Given a valid database connection and checkpoint", t, func() {
+		driver := &internal.Neo4jDriverMock{
+			ExecFunc: func(q string, params map[string]interface{}) (bolt.Result, error) {
+				return &internal.ResultMock{}, nil
 			},
 		}
 
-		Convey("When buildInsertObservationQuery is called", func() {
-			r := buildInsertObservationQuery(id, obs)
+		db := &Neo4j{driver, 5, 30}
+
+		Convey("When RecordImportCheckpoint is called", func() {
+			err := db.RecordImportCheckpoint(context.Background(), "123", checkpoint)
 
-			Convey("Then a valid query string is returned", func() {
-				So(r, ShouldNotBeEmpty)
-				So(r, ShouldEqual, "UNWIND $rows AS row MATCH (`age`:`_123_age`), (`time`:`_123_time`) WHERE id(`age`) = toInt(row.`age`) AND id(`time`) = toInt(row.`time`) CREATE (o:`_123_observation` { value:row.v, rowIndex:row.i }), (o)-[:isValueOf]->(`age`), (o)-[:isValueOf]->(`time`)")
+			Convey("Then the checkpoint is added to the instance node if not already present", func() {
+				So(err, ShouldBeNil)
+				So(driver.ExecCalls(), ShouldHaveLength, 1)
+				So(driver.ExecCalls()[0].Query, ShouldEqual, "MATCH (i:`_123_Instance`) SET i.import_checkpoints ="+
+					" CASE WHEN {checkpoint} IN coalesce(i.import_checkpoints, []) THEN i.import_checkpoints"+
+					" ELSE coalesce(i.import_checkpoints, []) + {checkpoint} END")
+				So(driver.ExecCalls()[0].Params, ShouldResemble, map[string]interface{}{"checkpoint": "1:100:100:1234"})
 			})
 		})
 	})
 
-	Convey("Given no instance ID and a valid list of observations", t, func() {
-		id := ""
-		obs := []*models.Observation{
-			&models.Observation{
-				InstanceID: "123",
-				DimensionOptions: []*models.DimensionOption{
-					&models.DimensionOption{
-						DimensionName: "age",
-					},
-				},
-			},
-		}
+	Convey("Given an empty instance ID", t, func() {
+		driver := &internal.Neo4jDriverMock{}
+		db := &Neo4j{driver, 5, 30}
 
-		Convey("When buildInsertObservationQuery is called", func() {
-			r := buildInsertObservationQuery(id, obs)
+		Convey("When RecordImportCheckpoint is called", func() {
+			err := db.RecordImportCheckpoint(context.Background(), "", checkpoint)
 
-			Convey("Then an empty string is returned", func() {
-				So(r, ShouldBeEmpty)
+			Convey("Then an error is returned and the database is not called", func() {
+				So(err.Error(), ShouldEqual, "instance id is required but was empty")
+				So(driver.ExecCalls(), ShouldHaveLength, 0)
 			})
 		})
 	})
+}
+
+func Test_GetImportProgress(t *testing.T) {
+
+	Convey("Given an instance with recorded checkpoints", t, func() {
+		driver := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return mapp(&mapper.Result{Data: []interface{}{[]interface{}{"101:200:100:2", "1:100:100:1"}}})
+			},
+		}
 
-	Convey("Given an instance ID but no observations", t, func() {
-		id := "123"
-		obs := []*models.Observation{}
+		db := &Neo4j{driver, 5, 30}
 
-		Convey("When buildInsertObservationQuery is called", func() {
-			r := buildInsertObservationQuery(id, obs)
+		Convey("When GetImportProgress is called", func() {
+			progress, err := db.GetImportProgress(context.Background(), "123")
 
-			Convey("Then an empty string is returned", func() {
-				So(r, ShouldBeEmpty)
+			Convey("Then the checkpoints are read from the instance node", func() {
+				So(err, ShouldBeNil)
+				So(driver.ReadCalls()[0].Query, ShouldEqual, "MATCH (i:`_123_Instance`) RETURN coalesce(i.import_checkpoints, [])")
+				So(progress.InstanceID, ShouldEqual, "123")
+				So(progress.Checkpoints, ShouldHaveLength, 2)
+				So(progress.ResumeFrom(1), ShouldEqual, 201)
 			})
 		})
 	})
 
-	Convey("Given no instance ID or observations", t, func() {
-		id := "123"
-		obs := []*models.Observation{}
+	Convey("Given an instance that does not exist", t, func() {
+		driver := &internal.Neo4jDriverMock{
+			ReadFunc: func(query string, mapp mapper.ResultMapper, single bool) error {
+				return graph.ErrNotFound
+			},
+		}
 
-		Convey("When buildInsertObservationQuery is called", func() {
-			r := buildInsertObservationQuery(id, obs)
+		db := &Neo4j{driver, 5, 30}
 
-			Convey("Then an empty string is returned", func() {
-				So(r, ShouldBeEmpty)
+		Convey("When GetImportProgress is called", func() {
+			progress, err := db.GetImportProgress(context.Background(), "123")
+
+			Convey("Then a not found error is returned", func() {
+				So(progress, ShouldBeNil)
+				So(err, ShouldEqual, graph.ErrNotFound)
 			})
 		})
 	})
 }
-
-func assertEmptyFilterResults(reader observation.StreamRowReader, expectedCSVRow string, err error) {
-	Convey("The expected result is returned with no error", func() {
-		So(err, ShouldBeNil)
-		row, _ := reader.Read()
-		So(row, ShouldEqual, expectedCSVRow+"\n")
-	})
-}
-
-func assertEmptyFilterQueryInvocations(d *internal.Neo4jDriverMock, expectedQuery string) {
-	Convey("Then the expected query is sent to the database one time", func() {
-		So(len(d.StreamRowsCalls()), ShouldEqual, 1)
-		So(d.StreamRowsCalls()[0].Query, ShouldEqual, expectedQuery)
-	})
-}
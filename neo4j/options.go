@@ -0,0 +1,55 @@
+package neo4j
+
+import "time"
+
+// Options holds the options that can be provided to New. Zero values are replaced by defaults.
+type Options struct {
+	// PoolSize is the size of the connection pool, 30 by default
+	PoolSize int
+	// PoolMaxLifetime is the time after which the connections of the pool are replaced by new ones, never by default
+	PoolMaxLifetime time.Duration
+	// PoolIdleTimeout is the time after which the connections of the pool are closed if none have been used, never by default
+	PoolIdleTimeout time.Duration
+	// Timeout is the maximum number of seconds allowed for a query, 60 by default
+	Timeout int
+	// MaxRetries is the maximum number of attempts for transient query failures, 5 by default
+	MaxRetries int
+}
+
+// Option sets an option of New
+type Option func(*Options)
+
+// WithPoolSize sets the size of the connection pool
+func WithPoolSize(size int) Option {
+	return func(o *Options) {
+		o.PoolSize = size
+	}
+}
+
+// WithPoolMaxLifetime replaces the connections of the pool by new ones once they have been open for the provided time
+func WithPoolMaxLifetime(maxLifetime time.Duration) Option {
+	return func(o *Options) {
+		o.PoolMaxLifetime = maxLifetime
+	}
+}
+
+// WithPoolIdleTimeout closes the connections of the pool once none have been used for the provided time
+func WithPoolIdleTimeout(idleTimeout time.Duration) Option {
+	return func(o *Options) {
+		o.PoolIdleTimeout = idleTimeout
+	}
+}
+
+// WithTimeout sets the maximum number of seconds allowed for a query
+func WithTimeout(timeout int) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithMaxRetries sets the maximum number of attempts for transient query failures
+func WithMaxRetries(retries int) Option {
+	return func(o *Options) {
+		o.MaxRetries = retries
+	}
+}
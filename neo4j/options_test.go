@@ -0,0 +1,37 @@
+package neo4j
+
+import (
+	"testing"
+
+	graph "github.com/ONSdigital/dp-graph/v2/graph/driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNew(t *testing.T) {
+	Convey("When New is called without options", t, func() {
+		db, err := New("")
+
+		Convey("Then the defaults are used", func() {
+			So(err, ShouldBeNil)
+			So(db.maxRetries, ShouldEqual, 5)
+			So(db.timeout, ShouldEqual, 60)
+			So(db.Stats().MaxOpenConnections, ShouldEqual, 30)
+		})
+
+		Convey("Then the capabilities of neo4j are reported, without code counts", func() {
+			So(db.Capabilities().Has(graph.CapabilitySparseHierarchy), ShouldBeTrue)
+			So(db.Capabilities().Has(graph.CapabilityCodeCounts), ShouldBeFalse)
+		})
+	})
+
+	Convey("When New is called with options", t, func() {
+		db, err := New("", WithPoolSize(10), WithTimeout(15), WithMaxRetries(2))
+
+		Convey("Then the options are used", func() {
+			So(err, ShouldBeNil)
+			So(db.maxRetries, ShouldEqual, 2)
+			So(db.timeout, ShouldEqual, 15)
+			So(db.Stats().MaxOpenConnections, ShouldEqual, 10)
+		})
+	})
+}
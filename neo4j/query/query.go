@@ -2,17 +2,31 @@ package query
 
 const (
 	// codelists
-	GetCodeLists       = "MATCH (i) WHERE i:_code_list%s RETURN distinct labels(i) as labels"
-	GetCodeList        = "MATCH (i:_code_list:`_code_list_%s`) RETURN i"
-	GetCodeListEdition = "MATCH (i:_code_list:`_code_list_%s` {edition:" + `"%s"` + "}) RETURN i"
-	CountEditions      = "MATCH (cl:_code_list:`_code_list_%s`) WHERE cl.edition = %q RETURN count(*)"
-	GetCodes           = "MATCH (c:_code) -[r:usedBy]->(cl:_code_list: `_code_list_%s`) WHERE cl.edition = %q RETURN c, r"
-	GetCode            = "MATCH (c:_code) -[r:usedBy]->(cl:_code_list: `_code_list_%s`) WHERE cl.edition = %q AND c.value = %q RETURN c, r"
-	GetCodeDatasets    = "MATCH (d)<-[inDataset]-(c:_code)-[r:usedBy]->(cl:_code_list:`_code_list_%s`) WHERE (cl.edition=" + `"%s"` + ") AND (c.value=" + `"%s"` + ") AND (d.is_published=true) RETURN d,r"
+	GetCodeLists          = "MATCH (i) WHERE i:_code_list%s RETURN distinct labels(i) as labels"
+	GetCodeList           = "MATCH (i:_code_list:`_code_list_%s`) RETURN i"
+	GetCodeListDetail     = "MATCH (cl:_code_list:`_code_list_%s`) OPTIONAL MATCH (cl)<-[:usedBy]-(c:_code) RETURN cl, count(c)"
+	GetCodeListEdition    = "MATCH (i:_code_list:`_code_list_%s` {edition:" + `"%s"` + "}) RETURN i"
+	CountEditions         = "MATCH (cl:_code_list:`_code_list_%s`) WHERE cl.edition = %q RETURN count(*)"
+	GetCodes              = "MATCH (c:_code) -[r:usedBy]->(cl:_code_list: `_code_list_%s`) WHERE cl.edition = %q RETURN c, r ORDER BY %s"
+	CodesByOrder          = "r.order, c.value"
+	CodesByLabel          = "r.label, c.value"
+	CodesByLocalisedLabel = "coalesce(r.`%s`, r.label), c.value"
+	CodesByCode           = "c.value"
+	GetCode               = "MATCH (c:_code) -[r:usedBy]->(cl:_code_list: `_code_list_%s`) WHERE cl.edition = %q AND c.value = %q RETURN c, r"
+	GetCodeDatasets       = "MATCH (d)<-[inDataset]-(c:_code)-[r:usedBy]->(cl:_code_list:`_code_list_%s`) WHERE (cl.edition=" + `"%s"` + ") AND (c.value=" + `"%s"` + ") AND (d.is_published=true) AND (coalesce(d.publish_state, 'published') <> 'superseded') RETURN d,r"
+	GetCodesBatch         = "UNWIND {refs} AS ref MATCH (c:_code {value: ref.code})-[r:usedBy]->(cl:_code_list {edition: ref.edition}) WHERE ('_code_list_' + ref.code_list_id) IN labels(cl) RETURN ref.code_list_id, ref.edition, c.value, r.label, r.order"
+	GetCodesOrder         = "MATCH (c:_code)-[r:usedBy]->(:`_code_list_%s`) WHERE c.value IN {codes} RETURN c.value, min(r.order)"
+	GetEditionsCodes      = "MATCH (c:_code)-[r:usedBy]->(cl:_code_list:`_code_list_%s`) WHERE cl.edition = {from_edition} OR cl.edition = {to_edition} RETURN cl.edition, c.value, r.label, r.order"
+
+	// datasets
+	GetDatasetDimensions = "MATCH (i)<-[:inDataset]-(c:_code)-[:usedBy]->(cl:_code_list) WHERE i.dataset_id = {dataset_id} AND i.edition = {edition} AND i.version = {version} RETURN labels(cl), cl.edition, count(c)"
+	GetDatasetCodes      = "MATCH (i)<-[:inDataset]-(c:_code)-[r:usedBy]->(cl:_code_list:`_code_list_%s`) WHERE i.dataset_id = {dataset_id} AND i.edition = {edition} AND i.version = {version} WITH c, head(collect(r)) AS r RETURN c, r ORDER BY c.value"
 
 	// hierarchy write
 	CreateHierarchyConstraint    = "CREATE CONSTRAINT ON (n:`_hierarchy_node_%s_%s`) ASSERT n.code IS UNIQUE;"
-	CloneHierarchyNodes          = "MATCH (n:`_generic_hierarchy_node_%s`) WITH n MERGE (:`_hierarchy_node_%s_%s` { code:n.code,label:n.label,code_list:{code_list}, hasData:false });"
+	GetLocalisedLabelKeys        = "MATCH (n:`_generic_hierarchy_node_%s`) UNWIND keys(n) AS key WITH key WHERE key STARTS WITH 'label_' RETURN collect(DISTINCT key)"
+	CloneHierarchyNodes          = "MATCH (n:`_generic_hierarchy_node_%s`) WITH n MERGE (h:`_hierarchy_node_%s_%s` { code:n.code,label:n.label,code_list:{code_list}, hasData:false })%s;"
+	CloneLocalisedLabelPart      = " SET h.`%s` = n.`%s`"
 	CountHierarchyNodes          = "MATCH (n:`_hierarchy_node_%s_%s`) RETURN COUNT(n);"
 	CloneHierarchyRelationships  = "MATCH (genericNode:`_generic_hierarchy_node_%s`)-[r:hasParent]->(genericParent:`_generic_hierarchy_node_%s`) WITH genericNode, genericParent MATCH (node:`_hierarchy_node_%s_%s` { code:genericNode.code }), (parent:`_hierarchy_node_%s_%s` { code:genericParent.code }) MERGE (node)-[r:hasParent]->(parent);"
 	SetNumberOfChildren          = "MATCH (n:`_hierarchy_node_%s_%s`) with n SET n.numberOfChildren = size((n)<-[:hasParent]-(:`_hierarchy_node_%s_%s`))"
@@ -21,12 +35,55 @@ const (
 	RemoveNodesNotMarkedToRemain = "MATCH (node:`_hierarchy_node_%s_%s`) WHERE NOT EXISTS(node.remain) DETACH DELETE node"
 	RemoveRemainMarker           = "MATCH (node:`_hierarchy_node_%s_%s`) REMOVE node.remain"
 
+	// SetRollupCounts counts the dimension options matching the code of each node or of its descendants, and their observations
+	SetRollupCounts = "MATCH (n:`_hierarchy_node_%s_%s`) OPTIONAL MATCH (c:`_hierarchy_node_%s_%s`)-[:hasParent*0..]->(n) " +
+		"OPTIONAL MATCH (d:`_%s_%s` {value: c.code}) WITH n, collect(DISTINCT d) AS options " +
+		"SET n.numberOfOptions = size(options), n.numberOfObservations = reduce(total = 0, d IN options | total + size(()-[:isValueOf]->(d)))"
+
+	// sparse hierarchy build, cloning only the generic hierarchy nodes with the provided IDs
+	GetCodesWithData                   = "MATCH (d:`_%s_%s`) RETURN collect(d.value)"
+	GetGenericHierarchyNodeIDs         = "MATCH (n:`_generic_hierarchy_node_%s`) WHERE n.code IN {codes} RETURN id(n), n.code"
+	GetGenericHierarchyAncestryIDs     = "MATCH (n:`_generic_hierarchy_node_%s`)-[:hasParent*]->(p) WHERE n.code IN {codes} RETURN DISTINCT id(p), p.code"
+	GetHierarchyNodeIDs                = "MATCH (n:`_hierarchy_node_%s_%s`) RETURN collect(id(n))"
+	CreateHasCodeEdges                 = "UNWIND {nodes} AS node MATCH (n) WHERE id(n) = node.id MATCH (c:_code {value: node.code})-[:usedBy]->(:`_code_list_%s`) WITH DISTINCT n, c MERGE (n)-[:hasCode]->(c)"
+	CloneHierarchyNodesFromIDs         = "MATCH (n:`_generic_hierarchy_node_%s`) WHERE id(n) IN {ids} MERGE (h:`_hierarchy_node_%s_%s` { code:n.code }) ON CREATE SET h.label = n.label, h.code_list = {code_list}, h.hasData = {has_data} ON MATCH SET h.hasData = h.hasData OR {has_data}%s MERGE (h)-[:clone_of]->(n)"
+	CloneHierarchyRelationshipsFromIDs = "MATCH (n)-[:hasParent]->(p) WHERE id(n) IN {ids} MATCH (h:`_hierarchy_node_%s_%s`)-[:clone_of]->(n), (hp:`_hierarchy_node_%s_%s`)-[:clone_of]->(p) MERGE (h)-[:hasParent]->(hp)"
+	CloneOrderFromIDs                  = "MATCH (n)-[:hasCode]->(:_code)-[r:usedBy]->(:`_code_list_%s`) WHERE id(n) IN {ids} AND exists(r.order) WITH n, min(r.order) AS order MATCH (h)-[:clone_of]->(n) SET h.order = order"
+	RemoveCloneMarkers                 = "MATCH (:`_hierarchy_node_%s_%s`)-[r:clone_of]->() DELETE r"
+	RemoveCloneMarkersFromSourceIDs    = "MATCH (n)-[r:clone_of]->() WHERE id(n) IN {ids} DELETE r"
+	SetNumberOfChildrenFromIDs         = "MATCH (n) WHERE id(n) IN {ids} SET n.numberOfChildren = size((n)<-[:hasParent]-())"
+
 	// hierarchy read
-	HierarchyExists     = "MATCH (i:`_hierarchy_node_%s_%s`) RETURN i LIMIT 1"
-	GetHierarchyRoot    = "MATCH (i:`_hierarchy_node_%s_%s`) WHERE NOT (i)-[:hasParent]->() RETURN i LIMIT 1"
-	GetHierarchyElement = "MATCH (i:`_hierarchy_node_%s_%s` {code:{code}}) RETURN i"
-	GetChildren         = "MATCH (i:`_hierarchy_node_%s_%s` {code:{code}})<-[r:hasParent]-(child) RETURN child ORDER BY child.label"
-	GetAncestry         = "MATCH (i:`_hierarchy_node_%s_%s` {code:{code}})-[r:hasParent *]->(parent) RETURN parent"
+	HierarchyExists          = "MATCH (i:`_hierarchy_node_%s_%s`) RETURN i LIMIT 1"
+	GetHierarchyRoot         = "MATCH (i:`_hierarchy_node_%s_%s`) WHERE NOT (i)-[:hasParent]->() RETURN i LIMIT 1"
+	GetHierarchyRoots        = "MATCH (i:`_hierarchy_node_%s_%s`) WHERE NOT (i)-[:hasParent]->() RETURN i ORDER BY i.label"
+	GetHierarchyElement      = "MATCH (i:`_hierarchy_node_%s_%s` {code:{code}}) RETURN i"
+	GetChildren              = "MATCH (i:`_hierarchy_node_%s_%s` {code:{code}})<-[r:hasParent]-(child) RETURN child ORDER BY %s%s"
+	ChildrenByLabel          = "child.label"
+	ChildrenByLocalisedLabel = "coalesce(child.`%s`, child.label)"
+	ChildrenByCode           = "child.code"
+	ChildrenByOrder          = "child.order, child.label"
+	ChildrenSkip             = " SKIP {offset}"
+	ChildrenLimit            = " LIMIT {limit}"
+	GetAncestry              = "MATCH (i:`_hierarchy_node_%s_%s` {code:{code}})-[r:hasParent *]->(parent) RETURN parent"
+	SearchHierarchy          = "MATCH (i:`_hierarchy_node_%s_%s`) WITH i, %s AS label WHERE toLower(label) CONTAINS toLower({text}) RETURN i " +
+		"ORDER BY CASE WHEN toLower(label) STARTS WITH toLower({text}) THEN 0 ELSE 1 END, label LIMIT {limit}"
+	SearchLabel          = "i.label"
+	SearchLocalisedLabel = "coalesce(i.`%s`, i.label)"
+
+	// hierarchy integrity, for the nodes with the provided label
+	GetHierarchyIntegrityNodes = "MATCH (n:`%s`) RETURN n.code, n.numberOfChildren"
+	GetHierarchyEdges          = "MATCH (n:`%s`)-[:hasParent]->(p:`%s`) RETURN n.code, p.code"
+	GetCodeListCodes           = "MATCH (c:_code)-[:usedBy]->(cl:`_code_list_%s`) WHERE cl.edition = %q RETURN collect(DISTINCT c.value)"
+
+	// hierarchy sync, the order of a generic hierarchy node is the order of its code in the code list
+	GetGenericHierarchySyncNodes = "MATCH (n:`_generic_hierarchy_node_%s`) OPTIONAL MATCH (n)-[:hasParent]->(p:`_generic_hierarchy_node_%s`) " +
+		"OPTIONAL MATCH (:_code {value: n.code})-[r:usedBy]->(:`_code_list_%s`) RETURN n, collect(DISTINCT p.code), min(r.order)"
+	GetInstanceHierarchySyncNodes = "MATCH (n:`_hierarchy_node_%s_%s`) OPTIONAL MATCH (n)-[:hasParent]->(p:`_hierarchy_node_%s_%s`) RETURN n, collect(p.code), n.order"
+	SyncHierarchyLabels           = "UNWIND {nodes} AS node MATCH (n:`_hierarchy_node_%s_%s` {code: node.code}) SET n += node.labels"
+	SyncHierarchyParents          = "UNWIND {nodes} AS node MATCH (n:`_hierarchy_node_%s_%s` {code: node.code}) OPTIONAL MATCH (n)-[r:hasParent]->() DELETE r " +
+		"WITH DISTINCT n, node UNWIND node.parents AS parentCode MATCH (p:`_hierarchy_node_%s_%s` {code: parentCode}) MERGE (n)-[:hasParent]->(p)"
+	SyncHierarchyOrders = "UNWIND {nodes} AS node MATCH (n:`_hierarchy_node_%s_%s` {code: node.code}) SET n.order = node.order"
 
 	// instance - import process
 	CreateInstanceObservationConstraint = "CREATE CONSTRAINT ON (o:`_%s_observation`) ASSERT o.rowIndex IS UNIQUE"
@@ -36,7 +93,28 @@ const (
 	CreateInstanceToCodeRelationship    = "MATCH (i:`_%s_Instance`), (c:_code {value:{code}})-[:usedBy]->(cl:`_code_list_%s`) CREATE (c)-[:inDataset]->(i)"
 	AddVersionDetailsToInstance         = "MATCH (i:`_%s_Instance`) SET i.dataset_id = {dataset_id}, i.edition = {edition}, i.version = {version} RETURN i"
 	SetInstanceIsPublished              = "MATCH (i:`_%s_Instance`) SET i.is_published = true"
+	SetInstancePublishState             = "MATCH (i:`_%s_Instance`) SET i.is_published = {is_published}, i.publish_state = {publish_state}"
+	CountUnpublishedInstance            = "MATCH (i:`_%s_Instance`) WHERE i.publish_state = 'unpublished' RETURN COUNT(*)"
+	SupersedePublishedInstances         = "MATCH (i:`_%s_Instance`) MATCH (p) WHERE exists(p.header) AND any(l IN labels(p) WHERE l STARTS WITH '_' AND l ENDS WITH '_Instance') AND p <> i AND p.dataset_id = i.dataset_id AND p.edition = i.edition AND p.is_published = true AND coalesce(p.publish_state, 'published') = 'published' SET p.publish_state = 'superseded'"
 	CountObservations                   = "MATCH (o:`_%s_observation`) RETURN COUNT(o)"
+	InsertObservationBatch              = "UNWIND $rows AS row MERGE (o:`_%s_observation` { rowIndex:row.i }) SET o.value = row.v WITH o, row UNWIND row.d AS dimensionNodeID MATCH (d) WHERE id(d) = toInt(dimensionNodeID) MERGE (o)-[:isValueOf]->(d)"
+	AddImportCheckpoint                 = "MATCH (i:`_%s_Instance`) SET i.import_checkpoints = CASE WHEN {checkpoint} IN coalesce(i.import_checkpoints, []) THEN i.import_checkpoints ELSE coalesce(i.import_checkpoints, []) + {checkpoint} END"
+	GetImportCheckpoints                = "MATCH (i:`_%s_Instance`) RETURN coalesce(i.import_checkpoints, [])"
+
+	// instance - metadata
+	GetInstance                    = "MATCH (i:`_%s_Instance`) RETURN i"
+	ListInstances                  = "MATCH (i) WHERE exists(i.header) AND any(l IN labels(i) WHERE l STARTS WITH '_' AND l ENDS WITH '_Instance')%s RETURN i ORDER BY i.dataset_id, i.edition, i.version"
+	InstanceDatasetIDFilterPart    = "i.dataset_id = {dataset_id}"
+	InstanceEditionFilterPart      = "i.edition = {edition}"
+	InstancePublishedFilterPart    = "i.is_published = true"
+	InstanceNotPublishedFilterPart = "coalesce(i.is_published, false) = false"
+
+	// instance - validation
+	GetInstanceDimensions                  = "MATCH (i:`_%s_Instance`) RETURN coalesce(i.dimensions, [])"
+	CountObservationsMissingDimension      = "MATCH (o:`_%s_observation`) WHERE NOT (o)-[:isValueOf]->(:`_%s_%s`) RETURN count(o)"
+	GetDimensionOptionsWithoutObservations = "MATCH (d:`_%s_%s`) WHERE NOT ()-[:isValueOf]->(d) RETURN collect(d.value)"
+	GetOrphanDimensionOptions              = "MATCH (d:`_%s_%s`) WHERE NOT (:`_%s_Instance`)-[:HAS_DIMENSION]->(d) RETURN collect(d.value)"
+	GetHasDataMismatches                   = "MATCH (n:`_hierarchy_node_%s_%s`) OPTIONAL MATCH (d:`_%s_%s` {value: n.code}) WITH n, count(d) > 0 AS withData WHERE coalesce(n.hasData, false) <> withData RETURN collect(n.code)"
 
 	// dimension
 	CreateDimensionConstraint             = "CREATE CONSTRAINT ON (d:`_%s_%s`) ASSERT d.value IS UNIQUE" //dimensionLabel "_%s_%s" = i.InstanceID, d.DimensionID
@@ -0,0 +1,19 @@
+package neo4j
+
+import "github.com/ONSdigital/dp-graph/v2/graph/driver"
+
+func init() {
+	driver.Register("neo4j", func(cfg driver.Config, errs chan error) (driver.Driver, error) {
+		n, err := New(
+			cfg.Address,
+			WithPoolSize(cfg.PoolSize),
+			WithPoolMaxLifetime(cfg.PoolMaxLifetime),
+			WithPoolIdleTimeout(cfg.PoolIdleTimeout),
+			WithTimeout(cfg.QueryTimeout),
+			WithMaxRetries(cfg.MaxRetries))
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	})
+}
@@ -0,0 +1,240 @@
+package neptune
+
+/*
+This module provides an alternative to InsertObservationBatch for large datasets.
+Rather than sending Gremlin statements to the database, observations, dimension
+nodes and their edges are written as Neptune bulk loader (Gremlin CSV) files,
+along with a manifest describing them, so they can be loaded in one go by the
+Neptune bulk loader.
+*/
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-graph/v2/internal/bulkfile"
+	"github.com/ONSdigital/dp-graph/v2/models"
+)
+
+// Names of the files generated by a BulkLoadWriter
+const (
+	BulkLoadDimensionsFile   = "dimensions.csv"
+	BulkLoadObservationsFile = "observations.csv"
+	BulkLoadHasDimensionFile = "has_dimension.csv"
+	BulkLoadIsValueOfFile    = "is_value_of.csv"
+	BulkLoadManifestFile     = "manifest.json"
+)
+
+// Gremlin CSV headers, as expected by the Neptune bulk loader
+var (
+	bulkLoadDimensionsHeader   = []string{"~id", "~label", "value:String"}
+	bulkLoadObservationsHeader = []string{"~id", "~label", "value:String(single)"}
+	bulkLoadEdgesHeader        = []string{"~id", "~from", "~to", "~label"}
+)
+
+// ErrBulkLoadWriterClosed is returned when writing to a BulkLoadWriter that has already been closed
+var ErrBulkLoadWriterClosed = errors.New("bulk load writer is closed")
+
+// FileCreator creates (or truncates) the named file and returns a writer for it.
+// It allows the bulk load files to be written to a local directory, or to any other
+// destination, such as an S3 bucket that the Neptune bulk loader can read from.
+type FileCreator = bulkfile.FileCreator
+
+// DirFileCreator returns a FileCreator that creates files in the provided directory
+func DirFileCreator(dir string) FileCreator {
+	return bulkfile.DirFileCreator(dir)
+}
+
+// BulkLoadManifest describes the files generated for an instance
+type BulkLoadManifest struct {
+	InstanceID string         `json:"instance_id"`
+	Format     string         `json:"format"`
+	Files      []BulkLoadFile `json:"files"`
+}
+
+// BulkLoadFile describes a single generated file and the number of records it holds
+type BulkLoadFile struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// bulkLoadFile is an open CSV file with the type and label of the vertices or edges it holds
+type bulkLoadFile struct {
+	*bulkfile.File
+	fileType string
+	label    string
+}
+
+// manifest returns the description of the file in the manifest
+func (f *bulkLoadFile) manifest() BulkLoadFile {
+	return BulkLoadFile{Name: f.Name, Type: f.fileType, Label: f.label, Count: f.Count}
+}
+
+// BulkLoadWriter writes the nodes and edges of an instance import as Neptune bulk loader files.
+// The instance node itself is expected to exist (see CreateInstance) before the files are loaded.
+// It is safe for concurrent use.
+type BulkLoadWriter struct {
+	instanceID string
+	create     FileCreator
+
+	mu           sync.Mutex
+	closed       bool
+	dimensions   *bulkLoadFile
+	observations *bulkLoadFile
+	hasDimension *bulkLoadFile
+	isValueOf    *bulkLoadFile
+}
+
+// NewBulkLoadWriter creates the bulk load files for the provided instance using the FileCreator
+func NewBulkLoadWriter(instanceID string, create FileCreator) (*BulkLoadWriter, error) {
+	if len(instanceID) == 0 {
+		return nil, errors.New("instance id is required but was empty")
+	}
+	if create == nil {
+		return nil, errors.New("a file creator is required but was nil")
+	}
+
+	w := &BulkLoadWriter{
+		instanceID: instanceID,
+		create:     create,
+	}
+
+	files := []struct {
+		dest     **bulkLoadFile
+		name     string
+		fileType string
+		label    string
+		header   []string
+	}{
+		{&w.dimensions, BulkLoadDimensionsFile, "vertices", "", bulkLoadDimensionsHeader},
+		{&w.observations, BulkLoadObservationsFile, "vertices", fmt.Sprintf("_%s_observation", instanceID), bulkLoadObservationsHeader},
+		{&w.hasDimension, BulkLoadHasDimensionFile, "edges", "HAS_DIMENSION", bulkLoadEdgesHeader},
+		{&w.isValueOf, BulkLoadIsValueOfFile, "edges", "isValueOf", bulkLoadEdgesHeader},
+	}
+
+	for _, f := range files {
+		opened, err := w.open(f.name, f.fileType, f.label, f.header)
+		if err != nil {
+			// close any files opened before the failure
+			w.closeFiles()
+			return nil, err
+		}
+		*f.dest = opened
+	}
+
+	return w, nil
+}
+
+func (w *BulkLoadWriter) open(name, fileType, label string, header []string) (*bulkLoadFile, error) {
+	f, err := bulkfile.Open(w.create, "bulk load file", name, header)
+	if err != nil {
+		return nil, err
+	}
+	return &bulkLoadFile{File: f, fileType: fileType, label: label}, nil
+}
+
+// WriteDimension writes a dimension option node and its 'HAS_DIMENSION' edge to the instance node.
+// Node IDs follow the same convention as InsertDimension, and NodeID is set on the provided dimension.
+func (w *BulkLoadWriter) WriteDimension(d *models.Dimension) (*models.Dimension, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, ErrBulkLoadWriterClosed
+	}
+
+	dimID := fmt.Sprintf("_%s_%s_%s", w.instanceID, d.DimensionID, d.Option)
+	dimLabel := fmt.Sprintf("_%s_%s", w.instanceID, d.DimensionID)
+	instanceNodeID := fmt.Sprintf("_%s_Instance", w.instanceID)
+
+	if err := w.dimensions.Write([]string{dimID, dimLabel, d.Option}); err != nil {
+		return nil, errors.Wrap(err, "failed to write dimension node")
+	}
+
+	edgeID := dimID + "_HAS_DIMENSION"
+	if err := w.hasDimension.Write([]string{edgeID, dimID, instanceNodeID, "HAS_DIMENSION"}); err != nil {
+		return nil, errors.Wrap(err, "failed to write dimension edge")
+	}
+
+	d.NodeID = dimID
+	return d, nil
+}
+
+// WriteObservationBatch writes observation nodes and their 'isValueOf' edges to the dimension option nodes.
+// Node IDs follow the same convention as InsertObservationBatch.
+func (w *BulkLoadWriter) WriteObservationBatch(observations []*models.Observation) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrBulkLoadWriterClosed
+	}
+
+	obsLabel := fmt.Sprintf("_%s_observation", w.instanceID)
+
+	for _, obs := range observations {
+		obsID := fmt.Sprintf("_%s_observation_%d", w.instanceID, obs.RowIndex)
+
+		if err := w.observations.Write([]string{obsID, obsLabel, obs.Row}); err != nil {
+			return errors.Wrap(err, "failed to write observation node")
+		}
+
+		for _, dim := range obs.DimensionOptions {
+			dimID := createDimensionId(dim, w.instanceID)
+			edgeID := obsID + "_isValueOf_" + dimID
+			if err := w.isValueOf.Write([]string{edgeID, obsID, dimID, "isValueOf"}); err != nil {
+				return errors.Wrap(err, "failed to write observation edge")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close flushes and closes all bulk load files, then writes and returns the manifest.
+// The writer cannot be used after it has been closed.
+func (w *BulkLoadWriter) Close() (*BulkLoadManifest, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, ErrBulkLoadWriterClosed
+	}
+
+	if err := w.closeFiles(); err != nil {
+		return nil, err
+	}
+
+	manifest := &BulkLoadManifest{
+		InstanceID: w.instanceID,
+		Format:     "csv",
+		Files: []BulkLoadFile{
+			w.dimensions.manifest(),
+			w.observations.manifest(),
+			w.hasDimension.manifest(),
+			w.isValueOf.manifest(),
+		},
+	}
+
+	if err := bulkfile.WriteManifest(w.create, "bulk load manifest", BulkLoadManifestFile, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// closeFiles closes any open file, returning the first error encountered
+func (w *BulkLoadWriter) closeFiles() error {
+	w.closed = true
+	var files []*bulkfile.File
+	for _, f := range []*bulkLoadFile{w.dimensions, w.observations, w.hasDimension, w.isValueOf} {
+		if f != nil {
+			files = append(files, f.File)
+		}
+	}
+	return bulkfile.CloseAll("bulk load file", files...)
+}
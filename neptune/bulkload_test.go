@@ -0,0 +1,160 @@
+package neptune
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// memFile is an in-memory io.WriteCloser used to capture bulk load files
+type memFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *memFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func memFileCreator(files map[string]*memFile) FileCreator {
+	return func(name string) (io.WriteCloser, error) {
+		f := &memFile{}
+		files[name] = f
+		return f, nil
+	}
+}
+
+func TestNewBulkLoadWriter(t *testing.T) {
+
+	Convey("Given an empty instance ID", t, func() {
+		files := map[string]*memFile{}
+
+		Convey("When NewBulkLoadWriter is called", func() {
+			w, err := NewBulkLoadWriter("", memFileCreator(files))
+
+			Convey("Then the expected error is returned and no files are created", func() {
+				So(w, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "instance id is required but was empty")
+				So(files, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a file creator that fails on the third file", t, func() {
+		files := map[string]*memFile{}
+		create := func(name string) (io.WriteCloser, error) {
+			if name == BulkLoadHasDimensionFile {
+				return nil, errors.New("disk full")
+			}
+			return memFileCreator(files)(name)
+		}
+
+		Convey("When NewBulkLoadWriter is called", func() {
+			w, err := NewBulkLoadWriter("instanceID", create)
+
+			Convey("Then the error is returned and the files opened so far are closed", func() {
+				So(w, ShouldBeNil)
+				So(err.Error(), ShouldEqual, `failed to create bulk load file "has_dimension.csv": disk full`)
+				So(files, ShouldHaveLength, 2)
+				So(files[BulkLoadDimensionsFile].closed, ShouldBeTrue)
+				So(files[BulkLoadObservationsFile].closed, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestBulkLoadWriter(t *testing.T) {
+
+	Convey("Given a bulk load writer for an instance", t, func() {
+		files := map[string]*memFile{}
+		w, err := NewBulkLoadWriter("instanceID", memFileCreator(files))
+		So(err, ShouldBeNil)
+
+		Convey("When dimensions and observations are written and the writer is closed", func() {
+			d, err := w.WriteDimension(&models.Dimension{DimensionID: "age", Option: "29"})
+			So(err, ShouldBeNil)
+			So(d.NodeID, ShouldEqual, "_instanceID_age_29")
+
+			_, err = w.WriteDimension(&models.Dimension{DimensionID: "sex", Option: "male"})
+			So(err, ShouldBeNil)
+
+			err = w.WriteObservationBatch([]*models.Observation{
+				{
+					Row:      `1,"quoted, value",29,male`,
+					RowIndex: 1,
+					DimensionOptions: []*models.DimensionOption{
+						{DimensionName: "Age", Name: "29"},
+						{DimensionName: "sex", Name: "male"},
+					},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			manifest, err := w.Close()
+			So(err, ShouldBeNil)
+
+			Convey("Then the dimension nodes and edges are written in Gremlin CSV format", func() {
+				So(files[BulkLoadDimensionsFile].String(), ShouldEqual,
+					"~id,~label,value:String\n"+
+						"_instanceID_age_29,_instanceID_age,29\n"+
+						"_instanceID_sex_male,_instanceID_sex,male\n")
+				So(files[BulkLoadHasDimensionFile].String(), ShouldEqual,
+					"~id,~from,~to,~label\n"+
+						"_instanceID_age_29_HAS_DIMENSION,_instanceID_age_29,_instanceID_Instance,HAS_DIMENSION\n"+
+						"_instanceID_sex_male_HAS_DIMENSION,_instanceID_sex_male,_instanceID_Instance,HAS_DIMENSION\n")
+			})
+
+			Convey("Then the observation nodes and edges are written in Gremlin CSV format", func() {
+				So(files[BulkLoadObservationsFile].String(), ShouldEqual,
+					"~id,~label,value:String(single)\n"+
+						`_instanceID_observation_1,_instanceID_observation,"1,""quoted, value"",29,male"`+"\n")
+				So(files[BulkLoadIsValueOfFile].String(), ShouldEqual,
+					"~id,~from,~to,~label\n"+
+						"_instanceID_observation_1_isValueOf__instanceID_age_29,_instanceID_observation_1,_instanceID_age_29,isValueOf\n"+
+						"_instanceID_observation_1_isValueOf__instanceID_sex_male,_instanceID_observation_1,_instanceID_sex_male,isValueOf\n")
+			})
+
+			Convey("Then all files are closed", func() {
+				for _, f := range files {
+					So(f.closed, ShouldBeTrue)
+				}
+			})
+
+			Convey("Then the manifest describes the generated files", func() {
+				So(manifest.InstanceID, ShouldEqual, "instanceID")
+				So(manifest.Files, ShouldResemble, []BulkLoadFile{
+					{Name: BulkLoadDimensionsFile, Type: "vertices", Count: 2},
+					{Name: BulkLoadObservationsFile, Type: "vertices", Label: "_instanceID_observation", Count: 1},
+					{Name: BulkLoadHasDimensionFile, Type: "edges", Label: "HAS_DIMENSION", Count: 2},
+					{Name: BulkLoadIsValueOfFile, Type: "edges", Label: "isValueOf", Count: 2},
+				})
+
+				written := &BulkLoadManifest{}
+				So(json.Unmarshal(files[BulkLoadManifestFile].Bytes(), written), ShouldBeNil)
+				So(written, ShouldResemble, manifest)
+			})
+
+			Convey("Then the writer cannot be used any more", func() {
+				So(w.WriteObservationBatch(nil), ShouldEqual, ErrBulkLoadWriterClosed)
+				_, err := w.WriteDimension(&models.Dimension{DimensionID: "age", Option: "30"})
+				So(err, ShouldEqual, ErrBulkLoadWriterClosed)
+				_, err = w.Close()
+				So(err, ShouldEqual, ErrBulkLoadWriterClosed)
+			})
+		})
+
+		Convey("When an invalid dimension is written", func() {
+			_, err := w.WriteDimension(&models.Dimension{DimensionID: "age"})
+
+			Convey("Then the validation error is returned", func() {
+				So(err.Error(), ShouldEqual, "dimension value is required but was empty")
+			})
+		})
+	})
+}
@@ -10,7 +10,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -18,6 +21,7 @@ import (
 	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neptune/query"
 	"github.com/ONSdigital/graphson"
+	gremgo "github.com/ONSdigital/gremgo-neptune"
 )
 
 // Type check to ensure that NeptuneDB implements the driver.CodeList interface
@@ -32,12 +36,24 @@ fully qualify the embedded Links field afterwards. It returns an error if:
 - A CodeList is encountered that does not have *listID* property.
 */
 func (n *NeptuneDB) GetCodeLists(ctx context.Context, filterBy string) (*models.CodeListResults, error) {
-	// Use differing Gremlin queries - depending on if a filterBy string is specified.
-	var qry string
-	if filterBy == "" {
-		qry = fmt.Sprintf(query.GetCodeLists)
-	} else {
-		qry = fmt.Sprintf(query.GetCodeListsFiltered, filterBy)
+	var flags []string
+	if filterBy != "" {
+		flags = append(flags, filterBy)
+	}
+	return n.GetCodeListsByFlags(ctx, flags)
+}
+
+/*
+GetCodeListsByFlags provides a list of the Code Lists having a boolean property
+set to true for each of the provided flags, or all Code Lists if no flags are provided.
+It returns an error if:
+- The Gremlin query failed to execute.
+- A CodeList is encountered that does not have *listID* property.
+*/
+func (n *NeptuneDB) GetCodeListsByFlags(ctx context.Context, flags []string) (*models.CodeListResults, error) {
+	qry := query.GetCodeLists
+	for _, flag := range flags {
+		qry += fmt.Sprintf(query.CodeListFlagPart, flag)
 	}
 	codeListVertices, err := n.getVertices(qry)
 	if err != nil {
@@ -86,6 +102,52 @@ func (n *NeptuneDB) GetCodeList(ctx context.Context, codeListID string) (
 	}, nil
 }
 
+/*
+GetCodeListDetail provides the metadata of a Code List and its editions, read from
+all the Code List vertices with the provided codeListID, and the number of codes used
+by each of them.
+It returns an error if:
+- A Gremlin query failed to execute. (wrapped error)
+- No CodeLists are found of the requested codeListID (error is ErrNotFound')
+- A CodeList is found that does not have the "edition" property (error is 'ErrNoSuchProperty')
+*/
+func (n *NeptuneDB) GetCodeListDetail(ctx context.Context, codeListID string) (*models.CodeListDetail, error) {
+	qry := fmt.Sprintf(query.GetCodeList, codeListID)
+	codeLists, err := n.getVertices(qry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+	}
+	if len(codeLists) == 0 {
+		return nil, driver.ErrNotFound
+	}
+
+	// the codes of all the editions are counted in a single traversal
+	qry = fmt.Sprintf(query.CountCodesByEdition, codeListID)
+	res, err := n.exec(qry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+	}
+	codeCounts, err := getEditionCodeCounts(res)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]models.CodeListNode, 0, len(codeLists))
+	for _, codeList := range codeLists {
+		edition, err := codeList.GetProperty("edition")
+		if err != nil {
+			return nil, errors.Wrapf(err, `Error reading "edition" property on Code List vertex`)
+		}
+
+		nodes = append(nodes, models.CodeListNode{
+			Properties: getVertexProperties(codeList),
+			CodeCount:  codeCounts[edition],
+		})
+	}
+
+	return models.NewCodeListDetail(codeListID, nodes), nil
+}
+
 /*
 GetEditions provides a models.Editions structure populated based on the
 the values in the Code List vertices in the database, that have the provided
@@ -170,8 +232,14 @@ Code List.  It raises a wrapped error if the database raises a non-transient err
 query).  It raises driver.ErrNotFound if the graph traversal above produces an empty list of codes -
 including the case of a short-circuit early termination of the query, because no such qualifying code
 list exists. It returns a wrapped error if a Code is found that does not have a "value" property.
+Codes are sorted by label or code if requested. Otherwise they are sorted by their order in the edition
+if any code has one, or by code if none do. The order of each code is returned if it has one.
 */
-func (n *NeptuneDB) GetCodes(ctx context.Context, codeListID, edition string) (*models.CodeResults, error) {
+func (n *NeptuneDB) GetCodes(ctx context.Context, codeListID, edition string, opts ...driver.ReadOption) (*models.CodeResults, error) {
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if order is defined
 	qry := fmt.Sprintf(query.CountOrderedEdges, codeListID, edition)
@@ -181,11 +249,14 @@ func (n *NeptuneDB) GetCodes(ctx context.Context, codeListID, edition string) (*
 	}
 	hasOrder := orderedCount > 0
 
-	// query depending on the presence of order in usedBy edges
-	if hasOrder {
-		qry = fmt.Sprintf(query.GetCodesWithOrder, codeListID, edition)
-	} else {
-		qry = fmt.Sprintf(query.GetCodesAlphabetically, codeListID, edition)
+	// query depending on the requested sort, or on the presence of order in usedBy edges
+	switch {
+	case options.Sort == driver.SortByLabel:
+		qry = fmt.Sprintf(query.GetCodesByLabel, codeListID, edition, labelPart(options), labelPart(options))
+	case options.Sort == driver.SortByCode || !hasOrder:
+		qry = fmt.Sprintf(query.GetCodesAlphabetically, codeListID, edition, labelPart(options))
+	default:
+		qry = fmt.Sprintf(query.GetCodesWithOrder, codeListID, edition, labelPart(options))
 	}
 	values, err := n.getStringList(qry)
 	if err != nil {
@@ -203,6 +274,23 @@ func (n *NeptuneDB) GetCodes(ctx context.Context, codeListID, edition string) (*
 
 	codes := createCodes(records)
 
+	if hasOrder {
+		qry = fmt.Sprintf(query.GetOrderedEdges, codeListID, edition)
+		res, err := n.exec(qry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+		}
+
+		codeOrders, err := getCodeOrders(res)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range codes.Items {
+			codes.Items[i].Order = codeOrders[codes.Items[i].Code]
+		}
+	}
+
 	return codes, nil
 }
 
@@ -223,16 +311,31 @@ func createCodes(records [][]string) *models.CodeResults {
 	return codes
 }
 
+// labelPart returns the projection of the label of a 'usedBy' edge or a hierarchy node in the language of
+// the provided options, falling back to the default label if it has no label in that language
+func labelPart(options *driver.ReadOptions) string {
+	if options.IsDefaultLanguage() {
+		return query.LabelPart
+	}
+	return fmt.Sprintf(query.LocalisedLabelPart, options.LabelProperty())
+}
+
 /*
 GetCode provides a Code struct to represent the requested code list, edition and code string.
 E.g. ashe-earnings|one-off|hourly-pay-gross.
-It doesn't need to access the database to form the response, but does so to validate the
-query. Specifically it can return errors as follows:
+The code is returned with its label in the requested language, falling back to the default label,
+and its order in the edition if it has one.
+It can return errors as follows:
 - The Gremlin query failed to execute.
 - The query parameter values do not successfully navigate to a Code node. (error is `ErrNotFound`)
 - Duplicate Code(s) exist that satisfy the search criteria (error is `ErrMultipleFound`)
 */
-func (n *NeptuneDB) GetCode(ctx context.Context, codeListID, edition string, code string) (*models.Code, error) {
+func (n *NeptuneDB) GetCode(ctx context.Context, codeListID, edition string, code string, opts ...driver.ReadOption) (*models.Code, error) {
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	qry := fmt.Sprintf(query.CodeExists, codeListID, edition, code)
 	nFound, err := n.getNumber(qry)
 	if err != nil {
@@ -245,9 +348,32 @@ func (n *NeptuneDB) GetCode(ctx context.Context, codeListID, edition string, cod
 		return nil, driver.ErrMultipleFound
 	}
 
-	// Missing ID and Label fields that exist in Neo4j response
+	// the label falls back to the default label, and is left empty if the code has none
+	qry = fmt.Sprintf(query.GetCodeLabel, codeListID, edition, code, options.LabelProperty())
+	labels, err := n.getStringList(qry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+	}
+	var label string
+	if len(labels) > 0 {
+		label = labels[0]
+	}
+
+	qry = fmt.Sprintf(query.GetCodeOrder, codeListID, edition, code)
+	res, err := n.exec(qry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+	}
+
+	codeOrders, err := getCodeOrders(res)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.Code{
-		Code: code,
+		Code:  code,
+		Label: label,
+		Order: codeOrders[code],
 	}, nil
 }
 
@@ -279,18 +405,8 @@ func (n *NeptuneDB) GetCodesOrder(ctx context.Context, codeListID string, codes
 			return codeOrders, err
 		}
 
-		// each item is a map of {'code': <code>, 'usedBy': <usedBy edge>}, obtain the order from all the code edges
-		for _, val := range orderEdgesMaps {
-			codeEdgeMap, err := graphson.DeserializeMapFromBytes(val)
-			if err != nil {
-				return make(map[string]*int), err
-			}
-
-			code, order, err := getCodeOrderFromMap(codeEdgeMap)
-			if err != nil {
-				return make(map[string]*int), err
-			}
-			codeOrders[code] = order
+		if err := addCodeOrders(codeOrders, orderEdgesMaps); err != nil {
+			return make(map[string]*int), err
 		}
 
 		// if not all 'usedBy' edges were found, we need to return ErrNotFound
@@ -302,6 +418,77 @@ func (n *NeptuneDB) GetCodesOrder(ctx context.Context, codeListID string, codes
 	return codeOrders, nil
 }
 
+// getCodeOrders obtains the order of each code from the responses of a query returning maps of {'code': <code>, 'usedBy': <usedBy edge>}
+func getCodeOrders(res []gremgo.Response) (map[string]*int, error) {
+	codeOrders := make(map[string]*int)
+
+	// responses are batched by gremgo library, hence we need to iterate them
+	for _, result := range res {
+		orderEdgesMaps, err := graphson.DeserializeListFromBytes(result.Result.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := addCodeOrders(codeOrders, orderEdgesMaps); err != nil {
+			return nil, err
+		}
+	}
+
+	return codeOrders, nil
+}
+
+// addCodeOrders adds the order of each item, a map of {'code': <code>, 'usedBy': <usedBy edge>}, to the provided code orders
+func addCodeOrders(codeOrders map[string]*int, orderEdgesMaps []json.RawMessage) error {
+	for _, val := range orderEdgesMaps {
+		codeEdgeMap, err := graphson.DeserializeMapFromBytes(val)
+		if err != nil {
+			return err
+		}
+
+		code, order, err := getCodeOrderFromMap(codeEdgeMap)
+		if err != nil {
+			return err
+		}
+		codeOrders[code] = order
+	}
+	return nil
+}
+
+// getEditionCodeCounts returns the number of codes of each edition, from the map of {<edition>: <count>}
+// of each response
+func getEditionCodeCounts(res []gremgo.Response) (map[string]int64, error) {
+	codeCounts := make(map[string]int64)
+
+	// responses are batched by gremgo library, hence we need to iterate them
+	for _, result := range res {
+		countMaps, err := graphson.DeserializeListFromBytes(result.Result.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rawCountMap := range countMaps {
+			countMap, err := graphson.DeserializeMapFromBytes(rawCountMap)
+			if err != nil {
+				return nil, err
+			}
+
+			for edition, rawCount := range countMap {
+				var count graphson.GenericValue
+				if err := json.Unmarshal(rawCount, &count); err != nil {
+					return nil, err
+				}
+				c, ok := count.Value.(float64)
+				if !ok {
+					return nil, errors.Errorf("unexpected %s code count for edition %q", count.Type, edition)
+				}
+				codeCounts[edition] = int64(c)
+			}
+		}
+	}
+
+	return codeCounts, nil
+}
+
 // getCodeOrderFromMap obtains the code and order value from the provided map of {'code': <code>, 'usedBy': <usedBy edge>}
 // order will be nil if not defined
 func getCodeOrderFromMap(codeEdgeMap map[string]json.RawMessage) (code string, order *int, err error) {
@@ -344,6 +531,211 @@ func getCodeOrderFromMap(codeEdgeMap map[string]json.RawMessage) (code string, o
 	return code, &orderPropertyInt, nil
 }
 
+// codesBatchSize is the number of code references looked up in each query by GetCodesBatch. Each reference adds
+// its code and code list edition to the query, so batches are much smaller than those of code IDs.
+var codesBatchSize = 250
+
+// GetCodesBatch obtains the label and order of the 'usedBy' edges between the provided codes and code list editions.
+// The codes are split in batches which are queried concurrently, each batch in a single query.
+// Codes that are not found are omitted from the result.
+func (n *NeptuneDB) GetCodesBatch(ctx context.Context, refs []models.CodeRef) (map[models.CodeRef]models.CodeLookup, error) {
+	codes := make(map[models.CodeRef]models.CodeLookup)
+
+	// if no codes are provided, nothing needs to be done
+	if len(refs) == 0 {
+		return codes, nil
+	}
+
+	// items are keyed by the index of the reference, as references are not strings
+	items := make(map[string]string, len(refs))
+	for i := range refs {
+		items[strconv.Itoa(i)] = ""
+	}
+
+	lockCodes := sync.Mutex{}
+	processBatch := func(chunk map[string]string) (map[string]string, error) {
+		indexes := make([]int, 0, len(chunk))
+		for key := range chunk {
+			i, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, err
+			}
+			indexes = append(indexes, i)
+		}
+
+		// keep the order of the references, so that queries are deterministic
+		sort.Ints(indexes)
+		chunkRefs := make([]models.CodeRef, 0, len(indexes))
+		for _, i := range indexes {
+			chunkRefs = append(chunkRefs, refs[i])
+		}
+
+		chunkCodes, err := n.getCodesBatch(chunkRefs)
+		if err != nil {
+			return nil, err
+		}
+
+		lockCodes.Lock()
+		defer lockCodes.Unlock()
+		for ref, code := range chunkCodes {
+			codes[ref] = code
+		}
+		return nil, nil
+	}
+
+	_, _, errs := processInConcurrentBatches(items, processBatch, codesBatchSize, n.maxWorkers)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return codes, nil
+}
+
+// getCodesBatch obtains the label and order of the provided codes in a single query
+func (n *NeptuneDB) getCodesBatch(refs []models.CodeRef) (map[models.CodeRef]models.CodeLookup, error) {
+	requested := make(map[models.CodeRef]bool, len(refs))
+	added := make(map[string]bool)
+	var editionParts, codeValues []string
+	for _, ref := range refs {
+		requested[ref] = true
+
+		editionPart := fmt.Sprintf(query.CodeListEditionPart, stringLiteral(ref.CodeListID), stringLiteral(ref.Edition))
+		if !added[editionPart] {
+			added[editionPart] = true
+			editionParts = append(editionParts, editionPart)
+		}
+
+		codeValue := fmt.Sprintf("'%s'", stringLiteral(ref.Code))
+		if !added[codeValue] {
+			added[codeValue] = true
+			codeValues = append(codeValues, codeValue)
+		}
+	}
+
+	qry := fmt.Sprintf(query.GetCodesBatch, strings.Join(editionParts, ","), strings.Join(codeValues, ","))
+
+	res, err := n.exec(qry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+	}
+
+	codes := make(map[models.CodeRef]models.CodeLookup)
+
+	// responses are batched by gremgo library, hence we need to iterate them
+	for _, result := range res {
+		codeEdgesMaps, err := graphson.DeserializeListFromBytes(result.Result.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		// each item is a map of {'listID': <listID>, 'edition': <edition>, 'code': <code>, 'usedBy': <usedBy edge>}
+		for _, val := range codeEdgesMaps {
+			codeEdgeMap, err := graphson.DeserializeMapFromBytes(val)
+			if err != nil {
+				return nil, err
+			}
+
+			rawListID, ok := codeEdgeMap["listID"]
+			if !ok {
+				return nil, driver.ErrNotFound
+			}
+			var codeListID string
+			if err := json.Unmarshal(rawListID, &codeListID); err != nil {
+				return nil, err
+			}
+
+			edition, entry, err := getCodeListEntryFromMap(codeEdgeMap)
+			if err != nil {
+				return nil, err
+			}
+
+			// the query matches any of the codes in any of the editions, so only requested pairs are returned
+			ref := models.CodeRef{CodeListID: codeListID, Edition: edition, Code: entry.Code}
+			if requested[ref] {
+				codes[ref] = models.CodeLookup{Label: entry.Label, Order: entry.Order}
+			}
+		}
+	}
+
+	return codes, nil
+}
+
+// DiffEditions returns the codes added to, removed from, relabelled or reordered in an edition of a code list
+// compared to another edition, from the label and order of the 'usedBy' edges of both editions.
+// driver.ErrNotFound is returned if either edition does not exist.
+func (n *NeptuneDB) DiffEditions(ctx context.Context, codeListID, fromEdition, toEdition string) (*models.CodeListDiff, error) {
+	for _, edition := range []string{fromEdition, toEdition} {
+		if _, err := n.GetEdition(ctx, codeListID, edition); err != nil {
+			return nil, err
+		}
+	}
+
+	qry := fmt.Sprintf(query.GetEditionsUsedByEdges, codeListID, fromEdition, toEdition)
+	res, err := n.exec(qry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+	}
+
+	entries := make(map[string][]models.CodeListEntry)
+
+	// responses are batched by gremgo library, hence we need to iterate them
+	for _, result := range res {
+		editionEdgesMaps, err := graphson.DeserializeListFromBytes(result.Result.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		// each item is a map of {'edition': <edition>, 'code': <code>, 'usedBy': <usedBy edge>}
+		for _, val := range editionEdgesMaps {
+			editionEdgeMap, err := graphson.DeserializeMapFromBytes(val)
+			if err != nil {
+				return nil, err
+			}
+
+			edition, entry, err := getCodeListEntryFromMap(editionEdgeMap)
+			if err != nil {
+				return nil, err
+			}
+			entries[edition] = append(entries[edition], *entry)
+		}
+	}
+
+	return models.NewCodeListDiff(codeListID, fromEdition, toEdition, entries[fromEdition], entries[toEdition]), nil
+}
+
+// getCodeListEntryFromMap obtains the edition, code, label and order from the provided map of
+// {'edition': <edition>, 'code': <code>, 'usedBy': <usedBy edge>}. Label will be empty if not defined.
+func getCodeListEntryFromMap(editionEdgeMap map[string]json.RawMessage) (edition string, entry *models.CodeListEntry, err error) {
+	rawEdition, ok := editionEdgeMap["edition"]
+	if !ok {
+		return "", nil, driver.ErrNotFound
+	}
+
+	if err := json.Unmarshal(rawEdition, &edition); err != nil {
+		return "", nil, err
+	}
+
+	code, order, err := getCodeOrderFromMap(editionEdgeMap)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the edge has been validated by getCodeOrderFromMap
+	var edge graphson.Edge
+	if err := json.Unmarshal(editionEdgeMap["usedBy"], &edge); err != nil {
+		return "", nil, err
+	}
+
+	entry = &models.CodeListEntry{Code: code, Order: order}
+	if l, ok := edge.Value.Properties["label"]; ok {
+		if err := json.Unmarshal(l.Value.Value, &entry.Label); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return edition, entry, nil
+}
+
 // convert a flat array of record values into  a 2d array of records
 func createRecords(values []string, valuesPerRecord int) ([][]string, error) {
 	var records [][]string
@@ -9,6 +9,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
 	"github.com/ONSdigital/graphson"
 	"github.com/ONSdigital/gremgo-neptune"
@@ -229,6 +230,107 @@ func TestGetEdition(t *testing.T) {
 	})
 }
 
+func TestGetCodeListsByFlags(t *testing.T) {
+	Convey("Given a database that will return a hard-coded CodeListResults that contains 3 Code Lists", t, func() {
+		poolMock := &internal.NeptunePoolMock{GetFunc: internal.ReturnThreeCodeLists}
+		db := mockDB(poolMock)
+
+		Convey("When GetCodeListsByFlags() is called with several flags", func() {
+			codeLists, err := db.GetCodeListsByFlags(context.Background(), []string{"geography", "hierarchy"})
+
+			Convey("Then the code lists are returned without error", func() {
+				So(err, ShouldBeNil)
+				So(codeLists.Items, ShouldHaveLength, 3)
+			})
+
+			Convey("Then the driver GetVertices function should be called once with a query filtering by all flags", func() {
+				So(poolMock.GetCalls(), ShouldHaveLength, 1)
+				So(poolMock.GetCalls()[0].Query, ShouldEqual, `g.V().hasLabel('_code_list').has('geography', true).has('hierarchy', true)`)
+			})
+		})
+	})
+}
+
+func TestGetCodeListDetail(t *testing.T) {
+	Convey("Given a database containing two editions of a code list", t, func() {
+		count := func(c int64) json.RawMessage {
+			rawCount, err := json.Marshal(graphson.GenericValue{Type: "g:Int64", Value: c})
+			So(err, ShouldBeNil)
+			return rawCount
+		}
+		rawCountMap, err := SerializeMap(map[string]json.RawMessage{"2020": count(3), "2019": count(2)})
+		So(err, ShouldBeNil)
+		rawCounts, err := json.Marshal(graphson.RawSlice{Type: "g:List", Value: []json.RawMessage{rawCountMap}})
+		So(err, ShouldBeNil)
+
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{
+					internal.MakeCodeListEditionVertex("mmm", "2020", map[string]interface{}{
+						"name":         "Months",
+						"label":        "Months 2020",
+						"release_date": "2020-01-01",
+						"geography":    false,
+						"order":        map[string]interface{}{"@type": "g:Int32", "@value": float64(2)},
+					}),
+					internal.MakeCodeListEditionVertex("mmm", "2019", map[string]interface{}{
+						"label":     "Months 2019",
+						"geography": true,
+					}),
+				}, nil
+			},
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return []gremgo.Response{{Status: gremgo.Status{Code: 200}, Result: gremgo.Result{Data: rawCounts}}}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetCodeListDetail() is called", func() {
+			detail, err := db.GetCodeListDetail(context.Background(), "mmm")
+
+			Convey("Then the code list vertices are queried", func() {
+				So(poolMock.GetCalls(), ShouldHaveLength, 1)
+				So(poolMock.GetCalls()[0].Query, ShouldEqual, `g.V().hasLabel('_code_list').has('listID', 'mmm')`)
+			})
+
+			Convey("Then the codes of all the editions are counted in a single query", func() {
+				So(poolMock.GetCountCalls(), ShouldHaveLength, 0)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 1)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldEqual, `g.V().has('_code_list','listID', 'mmm')`+
+					`.group().by('edition').by(__.in('usedBy').count())`)
+			})
+
+			Convey("Then the expected metadata is returned", func() {
+				So(err, ShouldBeNil)
+				So(detail, ShouldResemble, &models.CodeListDetail{
+					ID:    "mmm",
+					Name:  "Months",
+					Flags: map[string]bool{"geography": false},
+					Editions: []models.EditionDetail{
+						{ID: "2019", Label: "Months 2019", CodeCount: 2},
+						{ID: "2020", Label: "Months 2020", ReleaseDate: "2020-01-01", CodeCount: 3},
+					},
+					Properties: map[string]interface{}{"order": int64(2)},
+				})
+			})
+		})
+	})
+
+	Convey("Given a database that does not contain the code list", t, func() {
+		poolMock := &internal.NeptunePoolMock{GetFunc: internal.ReturnZeroVertices}
+		db := mockDB(poolMock)
+
+		Convey("When GetCodeListDetail() is called", func() {
+			detail, err := db.GetCodeListDetail(context.Background(), "mmm")
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(detail, ShouldBeNil)
+				So(err, ShouldEqual, driver.ErrNotFound)
+			})
+		})
+	})
+}
+
 func TestGetEditions(t *testing.T) {
 	Convey("Given a database that raises a non-transient error", t, func() {
 		poolMock := &internal.NeptunePoolMock{
@@ -450,9 +552,19 @@ func TestGetCodes(t *testing.T) {
 	})
 
 	Convey("Given a database with order that returns three code vertices", t, func() {
+		order := 2
+		rawOrders, err := json.Marshal(graphson.RawSlice{
+			Type:  "g:List",
+			Value: []json.RawMessage{mockCodeEdgeMapResponse("code_1", &order)},
+		})
+		So(err, ShouldBeNil)
+
 		poolMock := &internal.NeptunePoolMock{
 			GetCountFunc:      internal.ReturnThree,
 			GetStringListFunc: internal.ReturnThreeCodes,
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return []gremgo.Response{{Status: gremgo.Status{Code: 200}, Result: gremgo.Result{Data: rawOrders}}}, nil
+			},
 		}
 		db := mockDB(poolMock)
 		Convey("When GetCodes() is called", func() {
@@ -477,17 +589,100 @@ func TestGetCodes(t *testing.T) {
 						Convey("Then set right", func() {
 							sampleCode := codesResponse.Items[1]
 							So(sampleCode.Code, ShouldEqual, "code_1")
+							So(sampleCode.Order, ShouldResemble, &order)
+							So(codesResponse.Items[0].Order, ShouldBeNil)
 						})
 					})
 				})
 			})
+			Convey("Then the orders of the codes are queried", func() {
+				calls := poolMock.ExecuteCalls()
+				So(len(calls), ShouldEqual, 1)
+				So(calls[0].Query, ShouldEqual, `g.V().has('_code_list','listID', 'unused-id').has('edition', 'unused-edition').`+
+					`inE('usedBy').has('order').as('usedBy').outV().values('value').as('code').union(select('code', 'usedBy'))`)
+			})
+		})
+
+		Convey("When GetCodes() is called sorted by label", func() {
+			_, err := db.GetCodes(context.Background(), unusedCodeListID, unusedEdition, driver.WithSort(driver.SortByLabel))
+			Convey("Then the codes are queried sorted by label", func() {
+				So(err, ShouldBeNil)
+				calls := poolMock.GetStringListCalls()
+				So(len(calls), ShouldEqual, 1)
+				So(calls[0].Query, ShouldEqual, `g.V().has('_code_list', 'listID', 'unused-id').has('edition', 'unused-edition').`+
+					`inE('usedBy').order().by('label',asc).as('usedBy').outV().as('code').`+
+					`select('usedBy', 'code').by('label').by('value').unfold().select(values)`)
+			})
+		})
+
+		Convey("When GetCodes() is called sorted by code", func() {
+			_, err := db.GetCodes(context.Background(), unusedCodeListID, unusedEdition, driver.WithSort(driver.SortByCode))
+			Convey("Then the codes are queried sorted by code", func() {
+				So(err, ShouldBeNil)
+				calls := poolMock.GetStringListCalls()
+				So(len(calls), ShouldEqual, 1)
+				So(calls[0].Query, ShouldEqual, `g.V().has('_code_list','listID', 'unused-id').has('edition', 'unused-edition').`+
+					`inE('usedBy').as('usedBy').outV().order().by('value',asc).as('code').`+
+					`select('usedBy', 'code').by('label').by('value').unfold().select(values)`)
+			})
+		})
+	})
+
+	Convey("Given a database with order that returns three code vertices", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc:      internal.ReturnThree,
+			GetStringListFunc: internal.ReturnThreeCodes,
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return []gremgo.Response{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+		Convey("When GetCodes() is called with a language", func() {
+			_, err := db.GetCodes(context.Background(), unusedCodeListID, unusedEdition, driver.WithLanguage("cy"))
+			Convey("Then no error should be returned", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Then the labels are queried in the requested language, falling back to the default label", func() {
+				calls := poolMock.GetStringListCalls()
+				So(len(calls), ShouldEqual, 1)
+				expectedQry := `g.V().has('_code_list', 'listID', 'unused-id').has('edition', 'unused-edition').` +
+					`inE('usedBy').order().by('order',asc).as('usedBy').` +
+					`outV().as('code').` +
+					`select('usedBy', 'code').by(coalesce(values('label_cy'),values('label'))).by('value').` +
+					`unfold().select(values)`
+				So(calls[0].Query, ShouldEqual, expectedQry)
+			})
+		})
+
+		Convey("When GetCodes() is called with an invalid language", func() {
+			_, err := db.GetCodes(context.Background(), unusedCodeListID, unusedEdition, driver.WithLanguage("Welsh"))
+			Convey("Then the expected error is returned and the database is not queried", func() {
+				So(err.Error(), ShouldEqual, `invalid language "Welsh"`)
+				So(poolMock.GetCountCalls(), ShouldHaveLength, 0)
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 0)
+			})
 		})
 	})
 }
 
 func TestGetCode(t *testing.T) {
-	Convey("Given a database that will return that the Code exists", t, func() {
-		poolMock := &internal.NeptunePoolMock{GetCountFunc: internal.ReturnOne}
+	Convey("Given a database that will return that the Code exists with a label and an order", t, func() {
+		order := 3
+		rawOrders, err := json.Marshal(graphson.RawSlice{
+			Type:  "g:List",
+			Value: []json.RawMessage{mockCodeEdgeMapResponse("unused-code", &order)},
+		})
+		So(err, ShouldBeNil)
+
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc: internal.ReturnOne,
+			GetStringListFunc: func(q string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{"Unused"}, nil
+			},
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return []gremgo.Response{{Status: gremgo.Status{Code: 200}, Result: gremgo.Result{Data: rawOrders}}}, nil
+			},
+		}
 		db := mockDB(poolMock)
 		Convey("When GetCode() is called", func() {
 			unusedCodeList := "unused-code-list"
@@ -507,8 +702,21 @@ func TestGetCode(t *testing.T) {
 					So(actualQry, ShouldEqual, expectedQry)
 				})
 			})
-			Convey("Then a non nil structure returned", func() {
-				So(code, ShouldNotBeNil)
+			Convey("Then the label is queried in the default language", func() {
+				calls := poolMock.GetStringListCalls()
+				So(len(calls), ShouldEqual, 1)
+				So(calls[0].Query, ShouldEqual, `g.V().hasLabel('_code_list').has('listID', 'unused-code-list').has('edition', 'unused-edition')`+
+					`.inE('usedBy').where(otherV().has('value', "unused-code")).coalesce(values('label'),values('label'))`)
+			})
+			Convey("Then the order is queried for the edition", func() {
+				calls := poolMock.ExecuteCalls()
+				So(len(calls), ShouldEqual, 1)
+				So(calls[0].Query, ShouldEqual, `g.V().hasLabel('_code_list').has('listID', 'unused-code-list').has('edition', 'unused-edition')`+
+					`.inE('usedBy').where(otherV().has('value', "unused-code")).as('usedBy')`+
+					`.outV().values('value').as('code').union(select('code', 'usedBy'))`)
+			})
+			Convey("Then the code is returned with its label and order", func() {
+				So(code, ShouldResemble, &models.Code{Code: "unused-code", Label: "Unused", Order: &order})
 			})
 		})
 	})
@@ -560,6 +768,64 @@ func TestGetCode(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a database that will return that the Code exists and has a label in the requested language", t, func() {
+		rawOrders, err := json.Marshal(graphson.RawSlice{
+			Type:  "g:List",
+			Value: []json.RawMessage{mockCodeEdgeMapResponse("unused-code", nil)},
+		})
+		So(err, ShouldBeNil)
+
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc: internal.ReturnOne,
+			GetStringListFunc: func(q string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{"Cymru"}, nil
+			},
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return []gremgo.Response{{Status: gremgo.Status{Code: 200}, Result: gremgo.Result{Data: rawOrders}}}, nil
+			},
+		}
+		db := mockDB(poolMock)
+		Convey("When GetCode() is called with a language", func() {
+			code, err := db.GetCode(context.Background(), "unused-code-list", "unused-edition", "unused-code", driver.WithLanguage("cy"))
+			Convey("Then no error should be returned", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Then the label is queried in the requested language, falling back to the default label", func() {
+				calls := poolMock.GetStringListCalls()
+				So(len(calls), ShouldEqual, 1)
+				So(calls[0].Query, ShouldEqual, `g.V().hasLabel('_code_list').has('listID', 'unused-code-list').has('edition', 'unused-edition')`+
+					`.inE('usedBy').where(otherV().has('value', "unused-code")).coalesce(values('label_cy'),values('label'))`)
+			})
+			Convey("Then the code is returned with the label", func() {
+				So(code, ShouldResemble, &models.Code{Code: "unused-code", Label: "Cymru"})
+			})
+		})
+	})
+
+	Convey("Given a database that will return that the Code exists without a label", t, func() {
+		rawOrders, err := json.Marshal(graphson.RawSlice{
+			Type:  "g:List",
+			Value: []json.RawMessage{mockCodeEdgeMapResponse("unused-code", nil)},
+		})
+		So(err, ShouldBeNil)
+
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc:      internal.ReturnOne,
+			GetStringListFunc: internal.ReturnEmptyCodesList,
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return []gremgo.Response{{Status: gremgo.Status{Code: 200}, Result: gremgo.Result{Data: rawOrders}}}, nil
+			},
+		}
+		db := mockDB(poolMock)
+		Convey("When GetCode() is called with a language", func() {
+			code, err := db.GetCode(context.Background(), "unused-code-list", "unused-edition", "unused-code", driver.WithLanguage("cy"))
+			Convey("Then the code is returned with an empty label", func() {
+				So(err, ShouldBeNil)
+				So(code, ShouldResemble, &models.Code{Code: "unused-code"})
+			})
+		})
+	})
 }
 
 func TestGetCodeOrderFromMap(t *testing.T) {
@@ -825,6 +1091,262 @@ func TestGetCodesOrder(t *testing.T) {
 	})
 }
 
+func TestGetCodesBatch(t *testing.T) {
+
+	one, two := 1, 2
+	mar := models.CodeRef{CodeListID: "mmm", Edition: "2019", Code: "mar"}
+	apr := models.CodeRef{CodeListID: "mmm", Edition: "2019", Code: "apr"}
+	female := models.CodeRef{CodeListID: "sex", Edition: "one-off", Code: "female"}
+
+	mockGremgoResponse := func(values ...json.RawMessage) []gremgo.Response {
+		rawTestData, err := json.Marshal(graphson.RawSlice{
+			Type:  "g:List",
+			Value: values,
+		})
+		So(err, ShouldBeNil)
+
+		return []gremgo.Response{
+			{
+				RequestID: "89ed2475-6eb8-452b-a955-7f7697de2ff9",
+				Status:    gremgo.Status{Message: "", Code: 200},
+				Result: gremgo.Result{
+					Data: rawTestData,
+				},
+			},
+		}
+	}
+
+	Convey("Given a database containing codes of several code lists", t, func() {
+		response := mockGremgoResponse(
+			mockCodeListEditionEdgeMapResponse("mmm", "2019", "mar", "March", &one),
+			mockCodeListEditionEdgeMapResponse("sex", "one-off", "female", "Female", nil),
+			// not requested, but matched by the query
+			mockCodeListEditionEdgeMapResponse("sex", "one-off", "mar", "Married", &two),
+		)
+
+		// batches are queried concurrently, so the response is built beforehand
+		poolMock := &internal.NeptunePoolMock{
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return response, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetCodesBatch() is called", func() {
+			codes, err := db.GetCodesBatch(context.Background(), []models.CodeRef{mar, apr, female})
+
+			Convey("Then the driver Execute function should be called once with the expected query", func() {
+				expectedQry := `g.V().hasLabel('_code_list').or(has('listID','mmm').has('edition','2019'),has('listID','sex').has('edition','one-off')).as('listID','edition')` +
+					`.inE('usedBy').where(otherV().has('value', within('mar','apr','female'))).as('usedBy')` +
+					`.outV().values('value').as('code')` +
+					`.select('listID', 'edition', 'code', 'usedBy').by('listID').by('edition').by().by()`
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 1)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldEqual, expectedQry)
+			})
+
+			Convey("Then only the requested codes that were found are returned", func() {
+				So(err, ShouldBeNil)
+				So(codes, ShouldResemble, map[models.CodeRef]models.CodeLookup{
+					mar:    {Label: "March", Order: &one},
+					female: {Label: "Female"},
+				})
+			})
+		})
+
+		Convey("When GetCodesBatch() is called with more codes than the batch size", func() {
+			defer func(size int) { codesBatchSize = size }(codesBatchSize)
+			codesBatchSize = 2
+			codes, err := db.GetCodesBatch(context.Background(), []models.CodeRef{mar, apr, female})
+
+			Convey("Then a query is executed for each batch and the results are aggregated", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 2)
+				So(codes, ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When GetCodesBatch() is called with codes containing quotes", func() {
+			_, err := db.GetCodesBatch(context.Background(), []models.CodeRef{{CodeListID: "it's", Edition: `a\b`, Code: "o'clock"}})
+
+			Convey("Then the values are escaped in the query", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 1)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldContainSubstring, `.or(has('listID','it\'s').has('edition','a\\b'))`)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldContainSubstring, `within('o\'clock')`)
+			})
+		})
+
+		Convey("When GetCodesBatch() is called with no codes", func() {
+			codes, err := db.GetCodesBatch(context.Background(), []models.CodeRef{})
+
+			Convey("Then an empty map is returned and the driver Execute function should not be called", func() {
+				So(err, ShouldBeNil)
+				So(codes, ShouldBeEmpty)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given a database that raises a non-transient error", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return nil, internal.NonTransientErr
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetCodesBatch() is called", func() {
+			codes, err := db.GetCodesBatch(context.Background(), []models.CodeRef{mar})
+
+			Convey("Then the error is returned", func() {
+				So(codes, ShouldBeNil)
+				So(errors.Cause(err), ShouldEqual, internal.NonTransientErr)
+			})
+		})
+	})
+}
+
+func TestDiffEditions(t *testing.T) {
+
+	testCodeListID := "mmm"
+	one, two := 1, 2
+
+	mockGremgoResponse := func(values ...json.RawMessage) []gremgo.Response {
+		testData := graphson.RawSlice{
+			Type:  "g:List",
+			Value: values,
+		}
+		rawTestData, err := json.Marshal(testData)
+		So(err, ShouldBeNil)
+
+		return []gremgo.Response{
+			{
+				RequestID: "89ed2475-6eb8-452b-a955-7f7697de2ff9",
+				Status:    gremgo.Status{Message: "", Code: 200},
+				Result: gremgo.Result{
+					Data: rawTestData,
+				},
+			},
+		}
+	}
+
+	Convey("Given a database containing the 'usedBy' edges of two editions of a code list", t, func() {
+		response := mockGremgoResponse(
+			mockEditionEdgeMapResponse("2019", "jan", "January", &one),
+			mockEditionEdgeMapResponse("2019", "feb", "Feb", &two),
+			mockEditionEdgeMapResponse("2019", "dec", "December", nil),
+			mockEditionEdgeMapResponse("2020", "jan", "January", &two),
+			mockEditionEdgeMapResponse("2020", "feb", "February", &two),
+			mockEditionEdgeMapResponse("2020", "mar", "March", nil),
+		)
+
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc: internal.ReturnOne,
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return response, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When DiffEditions() is called", func() {
+			diff, err := db.DiffEditions(context.Background(), testCodeListID, "2019", "2020")
+
+			Convey("Then both editions are checked to exist", func() {
+				So(poolMock.GetCountCalls(), ShouldHaveLength, 2)
+				So(poolMock.GetCountCalls()[0].Q, ShouldEqual, `g.V().hasLabel('_code_list').has('listID', 'mmm').has('edition', '2019').count()`)
+				So(poolMock.GetCountCalls()[1].Q, ShouldEqual, `g.V().hasLabel('_code_list').has('listID', 'mmm').has('edition', '2020').count()`)
+			})
+
+			Convey("Then the driver Execute function should be called once with the expected query", func() {
+				expectedQry := `g.V().hasLabel('_code_list').has('_code_list', 'listID', 'mmm').has('edition', within('2019','2020')).as('edition').inE('usedBy').as('usedBy').outV().values('value').as('code').select('edition', 'code', 'usedBy').by('edition').by().by()`
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 1)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldEqual, expectedQry)
+			})
+
+			Convey("Then the expected differences are returned", func() {
+				So(err, ShouldBeNil)
+				So(diff.CodeListID, ShouldEqual, testCodeListID)
+				So(diff.Added, ShouldResemble, []models.CodeListEntry{{Code: "mar", Label: "March"}})
+				So(diff.Removed, ShouldResemble, []models.CodeListEntry{{Code: "dec", Label: "December"}})
+				So(diff.Relabelled, ShouldResemble, []models.CodeChange{
+					{Code: "feb", FromLabel: "Feb", ToLabel: "February", FromOrder: &two, ToOrder: &two},
+				})
+				So(diff.Reordered, ShouldResemble, []models.CodeChange{
+					{Code: "jan", FromLabel: "January", ToLabel: "January", FromOrder: &one, ToOrder: &two},
+				})
+			})
+		})
+	})
+
+	Convey("Given a database that does not contain the edition to compare to", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc: func(q string, bindings, rebindings map[string]string) (int64, error) {
+				if q == `g.V().hasLabel('_code_list').has('listID', 'mmm').has('edition', '2020').count()` {
+					return 0, nil
+				}
+				return 1, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When DiffEditions() is called", func() {
+			diff, err := db.DiffEditions(context.Background(), testCodeListID, "2019", "2020")
+
+			Convey("Then ErrNotFound is returned and the edges are not queried", func() {
+				So(diff, ShouldBeNil)
+				So(err, ShouldEqual, driver.ErrNotFound)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+// mockEditionEdgeMap generates an edition-code-edge map with the expected edition, code, and label and order
+// properties for the usedBy edge
+func mockEditionEdgeMap(expectedEdition, expectedCode, expectedLabel string, expectedOrder *int) map[string]json.RawMessage {
+	m := mockCodeEdgeMap(expectedCode, expectedOrder)
+
+	rawEdition, err := json.Marshal(expectedEdition)
+	So(err, ShouldBeNil)
+	m["edition"] = rawEdition
+
+	edge := mockUsedByEdge(expectedOrder)
+	labelValue, err := json.Marshal(expectedLabel)
+	So(err, ShouldBeNil)
+	edge.Value.Properties["label"] = graphson.EdgeProperty{
+		Type: "g.Property",
+		Value: graphson.EdgePropertyValue{
+			Label: "label",
+			Value: labelValue,
+		},
+	}
+	m["usedBy"], err = json.Marshal(edge)
+	So(err, ShouldBeNil)
+
+	return m
+}
+
+// mockEditionEdgeMapResponse generates an edition-code-edge map as returned by Neptune before being processed by graphson into a map
+func mockEditionEdgeMapResponse(expectedEdition, expectedCode, expectedLabel string, expectedOrder *int) json.RawMessage {
+	rawMap, err := SerializeMap(mockEditionEdgeMap(expectedEdition, expectedCode, expectedLabel, expectedOrder))
+	So(err, ShouldBeNil)
+	return rawMap
+}
+
+// mockCodeListEditionEdgeMapResponse generates an edition-code-edge map that also contains the expected code list ID,
+// as returned by Neptune before being processed by graphson into a map
+func mockCodeListEditionEdgeMapResponse(expectedCodeListID, expectedEdition, expectedCode, expectedLabel string, expectedOrder *int) json.RawMessage {
+	m := mockEditionEdgeMap(expectedEdition, expectedCode, expectedLabel, expectedOrder)
+
+	rawCodeListID, err := json.Marshal(expectedCodeListID)
+	So(err, ShouldBeNil)
+	m["listID"] = rawCodeListID
+
+	rawMap, err := SerializeMap(m)
+	So(err, ShouldBeNil)
+	return rawMap
+}
+
 // mockUsedByEdge generates an Edge struct fort testing
 // if order is not nil, it will be encoded as an 'order' edge property
 func mockUsedByEdge(order *int) graphson.Edge {
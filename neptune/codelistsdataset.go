@@ -28,6 +28,7 @@ satisfy all of:
     3) codes that match the requested code value.
     4) datasets that are related to qualifying codes by *inDataset* edges.
     5) datasets that have the *isPublished* state true.
+    6) datasets that have not been superseded by a later version.
 
 Each such result from the database (potentially) has the properties:
     - dimensionName (what the dataset calls this dimension)
@@ -223,7 +223,7 @@ func TestGetCodeDatasetsAtAPILevel(t *testing.T) {
 								select('d').values('edition').as('de').
 								select('d').values('version').as('dv').
 								select('d').values('dataset_id').as('did'),
-							__.as('d').has('is_published',true)).
+							__.as('d').has('is_published',true).not(has('publish_state','superseded'))).
 						union(select('rl', 'de', 'dv', 'did')).unfold().select(values)
                     `)
 					actualQry := calls[0].Query
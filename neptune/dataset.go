@@ -0,0 +1,108 @@
+package neptune
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neptune/query"
+)
+
+// Type check to ensure that NeptuneDB implements the driver.Dataset interface
+var _ driver.Dataset = (*NeptuneDB)(nil)
+
+/*
+GetDatasetDimensions provides the code list editions used by a version of a dataset, ordered by code
+list ID, along with the number of their codes used by the dataset. The instance of the version is
+found from its dataset_id, edition and version properties, and the code lists are those reached by
+following the 'inDataset' edges back to the codes, and the 'usedBy' edges from the codes. It raises
+driver.ErrNotFound if the version does not exist or uses no code lists.
+*/
+func (n *NeptuneDB) GetDatasetDimensions(ctx context.Context, datasetID, edition string, version int) (*models.DatasetDimensions, error) {
+	qry := fmt.Sprintf(query.GetDatasetDimensions, datasetID, edition, version)
+	values, err := n.getStringList(qry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+	}
+	if len(values) == 0 {
+		return nil, driver.ErrNotFound
+	}
+
+	// one [listID, edition] record is returned for each code used by the dataset
+	const valuesPerRecord = 2
+	records, err := createRecords(values, valuesPerRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := &models.DatasetDimensions{
+		Items: []models.DatasetDimension{},
+	}
+	index := make(map[[2]string]int)
+	for _, record := range records {
+		key := [2]string{record[0], record[1]}
+		i, ok := index[key]
+		if !ok {
+			i = len(dimensions.Items)
+			index[key] = i
+			dimensions.Items = append(dimensions.Items, models.DatasetDimension{
+				CodeListID: record[0],
+				Edition:    record[1],
+			})
+		}
+		dimensions.Items[i].CodeCount++
+	}
+
+	sort.Slice(dimensions.Items, func(i, j int) bool {
+		a, b := dimensions.Items[i], dimensions.Items[j]
+		if a.CodeListID == b.CodeListID {
+			return a.Edition < b.Edition
+		}
+		return a.CodeListID < b.CodeListID
+	})
+
+	return dimensions, nil
+}
+
+/*
+GetDatasetCodes provides the codes of a code list used by a version of a dataset, ordered by code.
+A code used by more than one edition of the code list is only returned once. It raises
+driver.ErrNotFound if the version does not exist or uses no codes of the code list.
+*/
+func (n *NeptuneDB) GetDatasetCodes(ctx context.Context, datasetID, edition string, version int, codeListID string) (*models.CodeResults, error) {
+	qry := fmt.Sprintf(query.GetDatasetCodes, datasetID, edition, version, codeListID)
+	values, err := n.getStringList(qry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", qry)
+	}
+	if len(values) == 0 {
+		return nil, driver.ErrNotFound
+	}
+
+	const valuesPerRecord = 2
+	records, err := createRecords(values, valuesPerRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(records))
+	unique := make([][]string, 0, len(records))
+	for _, record := range records {
+		if seen[record[1]] {
+			continue
+		}
+		seen[record[1]] = true
+		unique = append(unique, record)
+	}
+
+	codes := createCodes(unique)
+	sort.Slice(codes.Items, func(i, j int) bool {
+		return codes.Items[i].Code < codes.Items[j].Code
+	})
+
+	return codes, nil
+}
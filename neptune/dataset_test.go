@@ -0,0 +1,141 @@
+package neptune
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNeptuneDB_GetDatasetDimensions(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a dataset version using codes of several code lists", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{
+					"sex", "one-off",
+					"age", "2019",
+					"sex", "one-off",
+					"age", "2018",
+					"sex", "one-off",
+				}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetDatasetDimensions is called", func() {
+			dimensions, err := db.GetDatasetDimensions(ctx, "cpih01", "time-series", 3)
+
+			Convey("Then the expected query is sent to the database", func() {
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 1)
+				So(poolMock.GetStringListCalls()[0].Query, ShouldEqual,
+					"g.V().has('dataset_id','cpih01').has('edition','time-series').has('version','3')"+
+						".in('inDataset').out('usedBy').hasLabel('_code_list').as('listID','edition')"+
+						".select('listID','edition').by('listID').by('edition')"+
+						".unfold().select(values)")
+			})
+
+			Convey("Then the code list editions are returned in order with the number of codes used", func() {
+				So(err, ShouldBeNil)
+				So(dimensions.Items, ShouldResemble, []models.DatasetDimension{
+					{CodeListID: "age", Edition: "2018", CodeCount: 1},
+					{CodeListID: "age", Edition: "2019", CodeCount: 1},
+					{CodeListID: "sex", Edition: "one-off", CodeCount: 3},
+				})
+			})
+		})
+	})
+
+	Convey("Given a dataset version that does not exist", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetStringListFunc: internal.ReturnEmptyCodesList,
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetDatasetDimensions is called", func() {
+			dimensions, err := db.GetDatasetDimensions(ctx, "cpih01", "time-series", 3)
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(dimensions, ShouldBeNil)
+				So(err, ShouldEqual, driver.ErrNotFound)
+			})
+		})
+	})
+
+	Convey("Given a database that returns a malformed response", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{"sex", "one-off", "age"}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetDatasetDimensions is called", func() {
+			dimensions, err := db.GetDatasetDimensions(ctx, "cpih01", "time-series", 3)
+
+			Convey("Then an error is returned", func() {
+				So(dimensions, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "list length is not divisible by 2")
+			})
+		})
+	})
+}
+
+func TestNeptuneDB_GetDatasetCodes(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a dataset version using codes of a code list", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{
+					"Male", "male",
+					"Female", "female",
+					"Males", "male",
+				}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetDatasetCodes is called", func() {
+			codes, err := db.GetDatasetCodes(ctx, "cpih01", "time-series", 3, "sex")
+
+			Convey("Then the expected query is sent to the database", func() {
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 1)
+				So(poolMock.GetStringListCalls()[0].Query, ShouldEqual,
+					"g.V().has('dataset_id','cpih01').has('edition','time-series').has('version','3')"+
+						".in('inDataset').as('code')"+
+						".outE('usedBy').where(inV().has('_code_list','listID','sex')).as('usedBy')"+
+						".select('usedBy', 'code').by('label').by('value')"+
+						".unfold().select(values)")
+			})
+
+			Convey("Then each code is returned once, ordered by code", func() {
+				So(err, ShouldBeNil)
+				So(codes.Items, ShouldResemble, []models.Code{
+					{Code: "female", Label: "Female"},
+					{Code: "male", Label: "Male"},
+				})
+			})
+		})
+	})
+
+	Convey("Given a dataset version that uses no codes of the code list", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetStringListFunc: internal.ReturnEmptyCodesList,
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetDatasetCodes is called", func() {
+			codes, err := db.GetDatasetCodes(ctx, "cpih01", "time-series", 3, "sex")
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(codes, ShouldBeNil)
+				So(err, ShouldEqual, driver.ErrNotFound)
+			})
+		})
+	})
+}
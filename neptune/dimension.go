@@ -82,7 +82,7 @@ func (n *NeptuneDB) removeExistingDimension(dimID string) error {
 
 	getDim := fmt.Sprintf(query.GetDimension, dimID)
 
-	existingDimIDs, err := n.getStringList(getDim)
+	existingDimIDs, err := n.writer().getStringList(getDim)
 	if err != nil {
 		return err
 	}
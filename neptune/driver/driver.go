@@ -3,9 +3,10 @@ package driver
 import (
 	"context"
 	"crypto/tls"
+	"time"
 
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
-
+	"github.com/ONSdigital/dp-graph/v2/observation"
 	gremgo "github.com/ONSdigital/gremgo-neptune"
 )
 
@@ -14,17 +15,84 @@ var _ driver.Driver = (*NeptuneDriver)(nil)
 
 type NeptuneDriver struct {
 	Pool NeptunePool // Defined with an interface to support mocking.
+	// ReaderPool is the pool of connections to a reader endpoint used for read-only queries, nil to use Pool
+	ReaderPool NeptunePool
+}
+
+// New returns a NeptuneDriver with a pool of connections to the provided writer address, and a pool of connections
+// to the provided reader address if not empty. Each pool opens at most poolSize connections, 0 meaning unlimited.
+// Connections are closed once they have been open for maxLifetime, and all the connections of
+// a pool once none have been used for idleTimeout, if provided.
+func New(ctx context.Context, dbAddr, readerAddr string, errs chan error, tlsSkip bool, poolSize int, maxLifetime, idleTimeout time.Duration) (*NeptuneDriver, error) {
+	n := &NeptuneDriver{
+		Pool: newPool(ctx, dbAddr, errs, tlsSkip, poolSize, maxLifetime, idleTimeout),
+	}
+	if readerAddr != "" {
+		n.ReaderPool = newPool(ctx, readerAddr, errs, tlsSkip, poolSize, maxLifetime, idleTimeout)
+	}
+	return n, nil
 }
 
-func New(ctx context.Context, dbAddr string, errs chan error, tlsSkip bool) (*NeptuneDriver, error) {
-	tConf := &tls.Config{InsecureSkipVerify: tlsSkip}
-	pool := gremgo.NewPoolWithDialerCtx(ctx, dbAddr, errs, gremgo.SetTLSClientConfig(tConf))
-	return &NeptuneDriver{
-		Pool: pool,
-	}, nil
+func newPool(ctx context.Context, addr string, errs chan error, tlsSkip bool, poolSize int, maxLifetime, idleTimeout time.Duration) NeptunePool {
+	create := func() (NeptunePool, error) {
+		tConf := &tls.Config{InsecureSkipVerify: tlsSkip}
+		pool := gremgo.NewPoolWithDialerCtx(ctx, addr, errs, gremgo.SetTLSClientConfig(tConf))
+		pool.MaxOpen = poolSize
+		pool.MaxLifetime = maxLifetime
+		return pool, nil
+	}
+	pool, _ := create() // creating a gremgo pool does not fail, connections are dialled on first use
+	return newStatsPool(pool, create, poolSize, idleTimeout)
+}
+
+// OpenStream opens a stream of the results of the provided query on the provided pool,
+// which keeps its connection in use until the stream is closed
+func (n *NeptuneDriver) OpenStream(ctx context.Context, pool NeptunePool, query string) (observation.StreamRowReader, error) {
+	if p, ok := pool.(*statsPool); ok {
+		return p.openStream(ctx, query)
+	}
+	stream, err := pool.OpenStreamCursor(ctx, query, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Reader returns the pool to use for read-only queries, which is the writer pool if no reader is configured
+func (n *NeptuneDriver) Reader() NeptunePool {
+	if n.ReaderPool != nil {
+		return n.ReaderPool
+	}
+	return n.Pool
 }
 
 func (n *NeptuneDriver) Close(ctx context.Context) error {
 	n.Pool.Close()
+	if n.ReaderPool != nil {
+		n.ReaderPool.Close()
+	}
 	return nil
 }
+
+// Stats returns statistics about the usage of the connection pools, added up if a reader pool is configured,
+// The statistics are empty if the pools do not record them.
+func (n *NeptuneDriver) Stats() driver.PoolStats {
+	stats := poolStats(n.Pool)
+	if n.ReaderPool != nil {
+		reader := poolStats(n.ReaderPool)
+		stats.MaxOpenConnections += reader.MaxOpenConnections
+		stats.Open += reader.Open
+		stats.InUse += reader.InUse
+		stats.Idle += reader.Idle
+		stats.WaitCount += reader.WaitCount
+		stats.WaitDuration += reader.WaitDuration
+	}
+	return stats
+}
+
+func poolStats(pool NeptunePool) driver.PoolStats {
+	if p, ok := pool.(interface{ Stats() driver.PoolStats }); ok {
+		return p.Stats()
+	}
+	return driver.PoolStats{}
+}
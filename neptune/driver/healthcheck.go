@@ -4,25 +4,37 @@ import (
 	"context"
 
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/log.go/v2/log"
 )
 
 const (
 	serviceName = "neptune"
 	pingStmt    = "g.V().limit(1)"
 	msgHealthy  = "Neptune is healthy"
+
+	msgPoolExhausted = "Neptune is healthy but all the connections of the pool are in use"
 )
 
-// Healthcheck calls neptune to check its health status
+// Healthcheck calls neptune to check its health status, on the reader endpoint as well if one is configured
 func (n *NeptuneDriver) Healthcheck() (s string, err error) {
 	if _, err = n.Pool.Get(pingStmt, nil, nil); err != nil {
 		return serviceName, err
 	}
+	if n.ReaderPool != nil {
+		if _, err = n.ReaderPool.Get(pingStmt, nil, nil); err != nil {
+			return serviceName, err
+		}
+	}
 	return serviceName, nil
 }
 
 // Checker checks health of Neptune and updates the provided CheckState accordingly
 func (n *NeptuneDriver) Checker(ctx context.Context, state *health.CheckState) error {
 
+	// Get the pool statistics before the healthcheck uses a connection
+	stats := n.Stats()
+	log.Info(ctx, "neptune connection pool statistics", stats.LogData())
+
 	// Perform healthcheck
 	_, err := n.Healthcheck()
 
@@ -32,6 +44,12 @@ func (n *NeptuneDriver) Checker(ctx context.Context, state *health.CheckState) e
 		return nil
 	}
 
+	// Exhausted pool is mapped to Warning status, as queries are waiting for connections
+	if stats.IsExhausted() {
+		state.Update(health.StatusWarning, msgPoolExhausted, 0)
+		return nil
+	}
+
 	// Success healthcheck is mapped to OK status
 	state.Update(health.StatusOK, msgHealthy, 0)
 	return nil
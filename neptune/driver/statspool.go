@@ -0,0 +1,125 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/observation"
+	"github.com/ONSdigital/graphson"
+	gremgo "github.com/ONSdigital/gremgo-neptune"
+)
+
+/*
+statsPool wraps a NeptunePool, recording the usage of its connections. The gremgo pool opens a
+connection for each concurrent request, up to its maximum number of connections, and keeps it open
+to be reused, so a connection is in use while a request is made. Requests made while all connections
+are in use wait for one within the gremgo pool, so they are counted as waits but the time spent
+waiting is not recorded.
+
+The gremgo pool does not close idle connections, so the wrapped pool is replaced by a new one
+returned by newPool once it has been idle for the idle timeout, if provided.
+*/
+type statsPool struct {
+	pools *driver.PoolRotator[NeptunePool]
+	stats *driver.PoolStatsRecorder
+}
+
+// newStatsPool returns a statsPool wrapping the provided pool of at most maxOpen connections, 0 meaning unlimited, which
+// is replaced by one returned by newPool once it has been idle for idleTimeout, 0 meaning never.
+func newStatsPool(pool NeptunePool, newPool func() (NeptunePool, error), maxOpen int, idleTimeout time.Duration) *statsPool {
+	pools := driver.NewPoolRotator(pool, newPool, closePool, idleTimeout, 0)
+	return &statsPool{
+		pools: pools,
+		stats: driver.NewPoolStatsRecorder(maxOpen, pools.Open),
+	}
+}
+
+func closePool(pool NeptunePool) error {
+	pool.Close()
+	return nil
+}
+
+// Stats returns statistics about the usage of the connections of the pool
+func (p *statsPool) Stats() driver.PoolStats {
+	return p.stats.Stats()
+}
+
+// acquire returns the pool to make a request with, and a function to call once the request is complete
+func (p *statsPool) acquire() (NeptunePool, func()) {
+	_ = p.stats.Acquire(func() error { return nil })
+	pool, release := p.pools.Acquire()
+
+	return pool, func() {
+		release()
+		p.stats.Release()
+	}
+}
+
+func (p *statsPool) Close() {
+	p.pools.Close()
+}
+
+func (p *statsPool) Execute(query string, bindings, rebindings map[string]string) ([]gremgo.Response, error) {
+	pool, release := p.acquire()
+	defer release()
+	return pool.Execute(query, bindings, rebindings)
+}
+
+func (p *statsPool) Get(query string, bindings, rebindings map[string]string) ([]graphson.Vertex, error) {
+	pool, release := p.acquire()
+	defer release()
+	return pool.Get(query, bindings, rebindings)
+}
+
+func (p *statsPool) GetCount(q string, bindings, rebindings map[string]string) (int64, error) {
+	pool, release := p.acquire()
+	defer release()
+	return pool.GetCount(q, bindings, rebindings)
+}
+
+func (p *statsPool) GetE(q string, bindings, rebindings map[string]string) (interface{}, error) {
+	pool, release := p.acquire()
+	defer release()
+	return pool.GetE(q, bindings, rebindings)
+}
+
+// OpenStreamCursor opens a stream, which keeps reading from its connection until it is closed.
+// Only the opening of the stream is recorded as the connection being in use, openStream should
+// be used instead to record it until the stream is closed.
+func (p *statsPool) OpenStreamCursor(ctx context.Context, query string, bindings, rebindings map[string]string) (*gremgo.Stream, error) {
+	pool, release := p.acquire()
+	defer release()
+	return pool.OpenStreamCursor(ctx, query, bindings, rebindings)
+}
+
+// openStream opens a stream, recording its connection as in use until the stream is closed
+func (p *statsPool) openStream(ctx context.Context, query string) (observation.StreamRowReader, error) {
+	pool, release := p.acquire()
+	stream, err := pool.OpenStreamCursor(ctx, query, nil, nil)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &pooledStream{StreamRowReader: stream, release: release}, nil
+}
+
+func (p *statsPool) GetStringList(query string, bindings, rebindings map[string]string) ([]string, error) {
+	pool, release := p.acquire()
+	defer release()
+	return pool.GetStringList(query, bindings, rebindings)
+}
+
+// pooledStream is a stream of results which records that its connection is no longer in use when closed
+type pooledStream struct {
+	observation.StreamRowReader
+	release func()
+	once    sync.Once
+}
+
+// Close the stream, releasing its connection
+func (s *pooledStream) Close(ctx context.Context) error {
+	defer s.once.Do(s.release)
+	return s.StreamRowReader.Close(ctx)
+}
@@ -0,0 +1,113 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/graphson"
+	gremgo "github.com/ONSdigital/gremgo-neptune"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fakePool is a NeptunePool blocking requests until unblocked, and counting the times it is closed
+type fakePool struct {
+	block  chan struct{}
+	closed int32
+}
+
+func (p *fakePool) Close() { atomic.AddInt32(&p.closed, 1) }
+func (p *fakePool) Execute(query string, bindings, rebindings map[string]string) ([]gremgo.Response, error) {
+	<-p.block
+	return nil, nil
+}
+func (p *fakePool) Get(query string, bindings, rebindings map[string]string) ([]graphson.Vertex, error) {
+	<-p.block
+	return nil, nil
+}
+func (p *fakePool) GetCount(q string, bindings, rebindings map[string]string) (int64, error) {
+	<-p.block
+	return 0, nil
+}
+func (p *fakePool) GetE(q string, bindings, rebindings map[string]string) (interface{}, error) {
+	<-p.block
+	return nil, nil
+}
+func (p *fakePool) OpenStreamCursor(ctx context.Context, query string, bindings, rebindings map[string]string) (*gremgo.Stream, error) {
+	return &gremgo.Stream{}, nil
+}
+func (p *fakePool) GetStringList(query string, bindings, rebindings map[string]string) ([]string, error) {
+	<-p.block
+	return nil, nil
+}
+
+func TestStatsPool(t *testing.T) {
+	Convey("Given a pool", t, func() {
+		pool := &fakePool{block: make(chan struct{})}
+		p := newStatsPool(pool, nil, 0, 0)
+
+		Convey("When many requests are made at the same time", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.GetCount("g.V().count()", nil, nil)
+				}()
+			}
+			for p.Stats().InUse < 50 {
+				time.Sleep(time.Millisecond)
+			}
+			close(pool.block)
+			wg.Wait()
+
+			Convey("Then none of them wait for a connection, and the connections opened are idle", func() {
+				stats := p.Stats()
+				So(stats.Open, ShouldEqual, 50)
+				So(stats.InUse, ShouldEqual, 0)
+				So(stats.Idle, ShouldEqual, 50)
+				So(stats.WaitCount, ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("Given a pool with a stream of results opened", t, func() {
+		pool := &fakePool{}
+		p := newStatsPool(pool, nil, 0, 0)
+		n := &NeptuneDriver{Pool: p}
+
+		stream, err := n.OpenStream(context.Background(), n.Pool, "g.V()")
+		So(err, ShouldBeNil)
+
+		Convey("Then its connection is in use until the stream is closed", func() {
+			So(p.Stats().InUse, ShouldEqual, 1)
+			So(stream.Close(context.Background()), ShouldBeNil)
+			So(stream.Close(context.Background()), ShouldBeNil)
+			So(p.Stats().InUse, ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a pool that has been idle for the idle timeout", t, func() {
+		first := &fakePool{block: make(chan struct{})}
+		close(first.block)
+		second := &fakePool{}
+		p := newStatsPool(first, func() (NeptunePool, error) { return second, nil }, 0, 10*time.Millisecond)
+		defer p.Close()
+
+		_, err := p.GetCount("g.V().count()", nil, nil)
+		So(err, ShouldBeNil)
+
+		Convey("Then its connections are closed and it is replaced by a new pool", func() {
+			for i := 0; i < 100 && atomic.LoadInt32(&first.closed) == 0; i++ {
+				time.Sleep(5 * time.Millisecond)
+			}
+			So(atomic.LoadInt32(&first.closed), ShouldEqual, 1)
+
+			pool, release := p.pools.Acquire()
+			release()
+			So(pool, ShouldEqual, second)
+		})
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -37,7 +39,7 @@ func (n *NeptuneDB) GetCodesWithData(ctx context.Context, attempt int, instanceI
 
 	log.Info(ctx, "getting instance dimension codes that have data", logData)
 
-	codes, err = n.getStringList(codesWithDataStmt)
+	codes, err = n.writer().getStringList(codesWithDataStmt)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Gremlin query failed: %q", codesWithDataStmt)
 	}
@@ -181,12 +183,19 @@ func (n *NeptuneDB) CreateHasCodeEdges(ctx context.Context, attempt int, codeLis
 }
 
 func (n *NeptuneDB) CloneNodes(ctx context.Context, attempt int, instanceID, codeListID, dimensionName string) (err error) {
+	cloneLabels, err := n.cloneLocalisedLabelsPart(codeListID)
+	if err != nil {
+		log.Error(ctx, "cannot get the labels in other languages during cloning", err, log.Data{"code_list_id": codeListID})
+		return
+	}
+
 	gremStmt := fmt.Sprintf(
 		query.CloneHierarchyNodes,
 		codeListID,
 		instanceID,
 		dimensionName,
 		codeListID,
+		cloneLabels,
 	)
 	logData := log.Data{"fn": "CloneNodes",
 		"gremlin":        gremStmt,
@@ -217,6 +226,16 @@ func (n *NeptuneDB) CloneNodesFromIDs(ctx context.Context, attempt int, instance
 	}
 	log.Info(ctx, "cloning necessary nodes from the generic hierarchy", logData)
 
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cloneLabels, err := n.cloneLocalisedLabelsPart(codeListID)
+	if err != nil {
+		log.Error(ctx, "cannot get the labels in other languages during cloning", err, logData)
+		return err
+	}
+
 	processBatch := func(chunkIDs map[string]string) (ret map[string]string, err error) {
 		idsStr := `'` + strings.Join(createArray(chunkIDs), `','`) + `'`
 		gremStmt := fmt.Sprintf(
@@ -226,6 +245,7 @@ func (n *NeptuneDB) CloneNodesFromIDs(ctx context.Context, attempt int, instance
 			dimensionName,
 			hasData,
 			codeListID,
+			cloneLabels,
 		)
 
 		if _, err = n.exec(gremStmt); err != nil {
@@ -242,6 +262,42 @@ func (n *NeptuneDB) CloneNodesFromIDs(ctx context.Context, attempt int, instance
 	return nil
 }
 
+// cloneLocalisedLabelsPart returns the part of a clone query that copies the labels in languages other
+// than the default from the generic hierarchy nodes of the provided code list, if they have any
+func (n *NeptuneDB) cloneLocalisedLabelsPart(codeListID string) (string, error) {
+	keys, err := n.getLabelKeys(codeListID)
+	if err != nil {
+		return "", err
+	}
+
+	var part string
+	for _, key := range keys {
+		if key != "label" {
+			part += fmt.Sprintf(query.CloneLocalisedLabelPart, key, key, key)
+		}
+	}
+	return part, nil
+}
+
+// getLabelKeys returns the sorted keys of the label properties ('label', 'label_cy', ...) of the generic
+// hierarchy nodes of the provided code list
+func (n *NeptuneDB) getLabelKeys(codeListID string) ([]string, error) {
+	gremStmt := fmt.Sprintf(query.GetPropertyKeys, codeListID)
+	keys, err := n.getStringList(gremStmt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", gremStmt)
+	}
+
+	labelKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key == "label" || strings.HasPrefix(key, "label_") {
+			labelKeys = append(labelKeys, key)
+		}
+	}
+	sort.Strings(labelKeys)
+	return labelKeys, nil
+}
+
 // CloneOrderFromIDs copies the order property from the 'usedBy' edge that goes from the code node to the provided codelist node
 // where the code node is the determined by the 'hasCode' edge of the generic hierarchy nodes.
 // The order property is stored as a property of the clone node (assumes a clone_of edge exists from a hierarchy node to the generic hierarchy node)
@@ -373,7 +429,7 @@ func (n *NeptuneDB) GetHierarchyNodeIDs(ctx context.Context, attempt int, instan
 	}
 	log.Info(ctx, "getting ids of cloned hierarchy nodes", logData)
 
-	idList, err := n.getStringList(stmt)
+	idList, err := n.writer().getStringList(stmt)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Gremlin query failed: %q", stmt)
 	}
@@ -502,7 +558,7 @@ func (n *NeptuneDB) SetHasData(ctx context.Context, attempt int, instanceID, dim
 
 	log.Info(ctx, "getting instance dimension codes that have data", logData)
 
-	codes, err := n.getStringList(codesWithDataStmt)
+	codes, err := n.writer().getStringList(codesWithDataStmt)
 	if err != nil {
 		return errors.Wrapf(err, "Gremlin query failed: %q", codesWithDataStmt)
 	}
@@ -526,6 +582,143 @@ func (n *NeptuneDB) SetHasData(ctx context.Context, attempt int, instanceID, dim
 	return
 }
 
+// SetRollupCounts stores on each node the number of dimension options matching its code and the codes of its
+// descendants, and the number of observations of those options. Hierarchy nodes are not linked to dimension
+// options, so the counts are computed from the hierarchy edges and the observation count of each option.
+func (n *NeptuneDB) SetRollupCounts(ctx context.Context, attempt int, instanceID, dimensionName string) (err error) {
+	logData := log.Data{
+		"instance_id":    instanceID,
+		"dimension_name": dimensionName,
+		"max_workers":    n.maxWorkers,
+		"batch_size":     n.batchSizeWriter,
+	}
+
+	// the hierarchy has just been built, so it is read from the writer
+	w := n.writer()
+
+	codesStmt := fmt.Sprintf(query.GetHierarchyCodes, instanceID, dimensionName)
+	codes, err := w.getStringList(codesStmt)
+	if err != nil {
+		return errors.Wrapf(err, "Gremlin query failed: %q", codesStmt)
+	}
+
+	edges, err := w.getHierarchyEdges(fmt.Sprintf("_hierarchy_node_%s_%s", instanceID, dimensionName))
+	if err != nil {
+		return err
+	}
+
+	observationCounts, err := n.getObservationCountsByOption(instanceID, dimensionName)
+	if err != nil {
+		return err
+	}
+
+	rollups := rollupCounts(codes, edges, observationCounts)
+	log.Info(ctx, "setting rollup counts on the instance hierarchy nodes", logData)
+
+	items := make(map[string]string, len(codes))
+	for _, code := range codes {
+		items[code] = code
+	}
+
+	processBatch := func(chunk map[string]string) (ret map[string]string, err error) {
+		gremStmt := "g"
+		for code := range chunk {
+			r := rollups[code]
+			gremStmt += fmt.Sprintf(query.SetRollupCountsPart, instanceID, dimensionName, stringLiteral(code), r.options, r.observations)
+		}
+
+		if _, err = n.exec(gremStmt); err != nil {
+			log.Error(ctx, "cannot set rollup counts on hierarchy nodes", err, logData)
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	_, _, errs := processInConcurrentBatches(items, processBatch, n.batchSizeWriter, n.maxWorkers)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// getObservationCountsByOption returns the number of observations of each option of an instance dimension, by option value
+func (n *NeptuneDB) getObservationCountsByOption(instanceID, dimensionName string) (map[string]int64, error) {
+	gremStmt := fmt.Sprintf(query.CountObservationsByOption, instanceID, dimensionName)
+	res, err := n.exec(gremStmt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", gremStmt)
+	}
+
+	counts := make(map[string]int64)
+
+	// responses are batched by gremgo library, hence we need to iterate them
+	for _, result := range res {
+		maps, err := graphson.DeserializeListFromBytes(result.Result.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rawMap := range maps {
+			countsByOption, err := graphson.DeserializeMapFromBytes(rawMap)
+			if err != nil {
+				return nil, err
+			}
+
+			for option, rawCount := range countsByOption {
+				var count graphson.GenericValue
+				if err := json.Unmarshal(rawCount, &count); err != nil {
+					return nil, err
+				}
+				value, ok := count.Value.(float64)
+				if count.Type != "g:Int64" || !ok {
+					return nil, errors.Errorf("expected `g:Int64` observation count for option %q, but got %q", option, count.Type)
+				}
+				counts[option] = int64(value)
+			}
+		}
+	}
+	return counts, nil
+}
+
+type rollup struct {
+	options      int64
+	observations int64
+}
+
+// rollupCounts returns the rollup counts of each code, from the codes of its descendants, found by following
+// the provided edges from parent to children. Descendants reached through more than one path are counted once.
+func rollupCounts(codes []string, edges []models.HierarchyEdge, observationCounts map[string]int64) map[string]rollup {
+	children := make(map[string][]string)
+	for _, e := range edges {
+		children[e.ParentCode] = append(children[e.ParentCode], e.Code)
+	}
+
+	rollups := make(map[string]rollup, len(codes))
+	for _, code := range codes {
+		var r rollup
+		visited := map[string]bool{code: true}
+		pending := []string{code}
+		for len(pending) > 0 {
+			current := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+
+			if count, ok := observationCounts[current]; ok {
+				r.options++
+				r.observations += count
+			}
+
+			for _, child := range children[current] {
+				if !visited[child] {
+					visited[child] = true
+					pending = append(pending, child)
+				}
+			}
+		}
+		rollups[code] = r
+	}
+	return rollups
+}
+
 func (n *NeptuneDB) MarkNodesToRemain(ctx context.Context, attempt int, instanceID, dimensionName string) (err error) {
 	gremStmt := fmt.Sprintf(query.MarkNodesToRemain,
 		instanceID,
@@ -603,7 +796,12 @@ func (n *NeptuneDB) GetHierarchyCodelist(ctx context.Context, instanceID, dimens
 	return
 }
 
-func (n *NeptuneDB) GetHierarchyRoot(ctx context.Context, instanceID, dimension string) (node *models.HierarchyResponse, err error) {
+func (n *NeptuneDB) GetHierarchyRoot(ctx context.Context, instanceID, dimension string, opts ...driver.ReadOption) (node *models.HierarchyResponse, err error) {
+	var options *driver.ReadOptions
+	if options, err = driver.NewReadOptions(opts...); err != nil {
+		return
+	}
+
 	gremStmt := fmt.Sprintf(query.GetHierarchyRoot, instanceID, dimension)
 	logData := log.Data{
 		"fn":             "GetHierarchyRoot",
@@ -624,6 +822,14 @@ func (n *NeptuneDB) GetHierarchyRoot(ctx context.Context, instanceID, dimension
 	}
 	if len(vertices) > 1 {
 		err = driver.ErrMultipleFound
+		if options.SingleRoot {
+			multipleRoots := driver.ErrMultipleRoots{}
+			for _, v := range vertices {
+				code, _ := v.GetProperty("code")
+				multipleRoots.Codes = append(multipleRoots.Codes, code)
+			}
+			err = multipleRoots
+		}
 		log.Error(ctx, "Cannot identify hierarchy root node because are multiple candidates", err, logData)
 		return
 	}
@@ -632,13 +838,53 @@ func (n *NeptuneDB) GetHierarchyRoot(ctx context.Context, instanceID, dimension
 	// including launching new queries in of itself to fetch child nodes, and
 	// breadcrumb nodes.
 	wantBreadcrumbs := false // Because meaningless for a root node
-	if node, err = n.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs); err != nil {
+	if node, err = n.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, options); err != nil {
 		log.Error(ctx, "Cannot extract related information needed from hierarchy node", err, logData)
 		return
 	}
 	return
 }
 
+// GetHierarchyRoots returns all the nodes without a parent for a given hierarchy, ordered by label
+func (n *NeptuneDB) GetHierarchyRoots(ctx context.Context, instanceID, dimension string, opts ...driver.ReadOption) (roots []*models.HierarchyElement, err error) {
+	var options *driver.ReadOptions
+	if options, err = driver.NewReadOptions(opts...); err != nil {
+		return
+	}
+
+	gremStmt := fmt.Sprintf(query.GetHierarchyRoots, instanceID, dimension)
+	logData := log.Data{
+		"fn":             "GetHierarchyRoots",
+		"gremlin":        gremStmt,
+		"instance_id":    instanceID,
+		"dimension_name": dimension,
+	}
+
+	var vertices []graphson.Vertex
+	if vertices, err = n.getVertices(gremStmt); err != nil {
+		log.Error(ctx, "getVertices failed: cannot find hierarchy root nodes", err, logData)
+		return
+	}
+	if len(vertices) == 0 {
+		err = driver.ErrNotFound
+		log.Error(ctx, "Cannot find hierarchy root nodes", err, logData)
+		return
+	}
+
+	roots = make([]*models.HierarchyElement, 0, len(vertices))
+	for _, vertex := range vertices {
+		var root *models.HierarchyElement
+		if root, err = convertVertexToElement(vertex, options.Language); err != nil {
+			log.Error(ctx, "Cannot extract related information needed from hierarchy node", err, logData)
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// HierarchyExists returns true if a hierarchy has been built for the instance dimension. It is read from the writer,
+// as it is used while building hierarchies.
 func (n *NeptuneDB) HierarchyExists(ctx context.Context, instanceID, dimension string) (hierarchyExists bool, err error) {
 	gremStmt := fmt.Sprintf(query.HierarchyExists, instanceID, dimension)
 	logData := log.Data{
@@ -649,7 +895,7 @@ func (n *NeptuneDB) HierarchyExists(ctx context.Context, instanceID, dimension s
 	}
 
 	var vertices []graphson.Vertex
-	if vertices, err = n.getVertices(gremStmt); err != nil {
+	if vertices, err = n.writer().getVertices(gremStmt); err != nil {
 		log.Error(ctx, "getVertices failed when attempting to get a hierarchy node", err, logData)
 		return
 	}
@@ -669,7 +915,12 @@ func (n *NeptuneDB) HierarchyExists(ctx context.Context, instanceID, dimension s
 	return hierarchyExists, nil
 }
 
-func (n *NeptuneDB) GetHierarchyElement(ctx context.Context, instanceID, dimension, code string) (node *models.HierarchyResponse, err error) {
+func (n *NeptuneDB) GetHierarchyElement(ctx context.Context, instanceID, dimension, code string, opts ...driver.ReadOption) (node *models.HierarchyResponse, err error) {
+	var options *driver.ReadOptions
+	if options, err = driver.NewReadOptions(opts...); err != nil {
+		return
+	}
+
 	gremStmt := fmt.Sprintf(query.GetHierarchyElement, instanceID, dimension, code)
 	logData := log.Data{
 		"fn":             "GetHierarchyElement",
@@ -688,9 +939,67 @@ func (n *NeptuneDB) GetHierarchyElement(ctx context.Context, instanceID, dimensi
 	// including launching new queries in of itself to fetch child nodes, and
 	// breadcrumb nodes.
 	wantBreadcrumbs := true // Because we are at depth in the hierarchy
-	if node, err = n.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs); err != nil {
+	if node, err = n.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, options); err != nil {
 		log.Error(ctx, "Cannot extract related information needed from hierarchy node", err, logData)
 		return
 	}
 	return
 }
+
+// SearchHierarchy returns up to limit nodes of a given hierarchy whose label in the requested language contains
+// the provided text, ignoring case, with their breadcrumbs. Nodes whose label starts with the text are returned first.
+// Labels not available in the language fall back to the default label. The search uses TextP.regex, which
+// requires Neptune engine 1.2.1.0 or later and is evaluated on every node of the hierarchy.
+func (n *NeptuneDB) SearchHierarchy(ctx context.Context, instanceID, dimension, text string, limit int, opts ...driver.ReadOption) (elements []*models.HierarchyElement, err error) {
+	if len(text) == 0 {
+		return nil, errors.New("search text is required but was empty")
+	}
+	if limit < 1 {
+		return nil, errors.New("search limit must be greater than zero")
+	}
+	options, err := driver.NewReadOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	label := query.SearchLabelPart
+	if !options.IsDefaultLanguage() {
+		label = fmt.Sprintf(query.LocalisedLabelPart, options.LabelProperty())
+	}
+	gremStmt := fmt.Sprintf(query.SearchHierarchy, instanceID, dimension, label, regexLiteral(text), limit)
+	logData := log.Data{
+		"fn":             "SearchHierarchy",
+		"gremlin":        gremStmt,
+		"instance_id":    instanceID,
+		"dimension_name": dimension,
+		"language":       options.Language,
+		"limit":          limit,
+	}
+
+	var vertices []graphson.Vertex
+	if vertices, err = n.getVertices(gremStmt); err != nil {
+		log.Error(ctx, "getVertices failed: cannot search hierarchy nodes", err, logData)
+		return
+	}
+
+	elements = make([]*models.HierarchyElement, 0, len(vertices))
+	for _, vertex := range vertices {
+		var element *models.HierarchyElement
+		if element, err = convertVertexToElement(vertex, options.Language); err != nil {
+			log.Error(ctx, "Cannot extract related information needed from hierarchy node", err, logData)
+			return nil, err
+		}
+		if element.Breadcrumbs, err = n.buildBreadcrumbs(instanceID, dimension, element.ID, options.Language); err != nil {
+			log.Error(ctx, "Cannot build breadcrumbs of hierarchy node", err, logData)
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	return elements, nil
+}
+
+// regexLiteral escapes the provided text so that it is matched literally by a regular expression
+// within a single quoted Gremlin string
+func regexLiteral(text string) string {
+	return stringLiteral(regexp.QuoteMeta(text))
+}
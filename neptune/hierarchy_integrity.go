@@ -0,0 +1,99 @@
+package neptune
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neptune/query"
+	"github.com/ONSdigital/graphson"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// CheckGenericHierarchy checks the generic hierarchy of a code list for cycles, nodes with multiple parents,
+// orphan nodes, codes missing from the code list edition and numberOfChildren mismatches
+func (n *NeptuneDB) CheckGenericHierarchy(ctx context.Context, codeListID, edition string) (*models.HierarchyIntegrityReport, error) {
+	log.Info(ctx, "checking generic hierarchy integrity", log.Data{"code_list_id": codeListID, "edition": edition})
+
+	return models.CheckGenericHierarchy(n.readHierarchyIntegrity, codeListID, edition)
+}
+
+// CheckInstanceHierarchy checks the hierarchy of an instance dimension for cycles, nodes with multiple parents,
+// orphan nodes, codes missing from the code list edition and numberOfChildren mismatches
+func (n *NeptuneDB) CheckInstanceHierarchy(ctx context.Context, instanceID, dimension, edition string) (*models.HierarchyIntegrityReport, error) {
+	log.Info(ctx, "checking instance hierarchy integrity", log.Data{"instance_id": instanceID, "dimension_name": dimension, "edition": edition})
+
+	codeListID, err := n.GetHierarchyCodelist(ctx, instanceID, dimension)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.CheckInstanceHierarchy(n.readHierarchyIntegrity, instanceID, dimension, codeListID, edition)
+}
+
+// readHierarchyIntegrity reads the hierarchy nodes with the provided label, the edges between them and
+// the codes of the code list edition. The edges are not traversed recursively, so that cycles cannot
+// cause the queries to loop.
+func (n *NeptuneDB) readHierarchyIntegrity(nodeLabel, codeListID, edition string) ([]models.HierarchyIntegrityNode, []models.HierarchyEdge, []string, error) {
+	gremStmt := fmt.Sprintf(query.GetHierarchyIntegrityNodes, nodeLabel)
+	vertices, err := n.getVertices(gremStmt)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "Gremlin query failed: %q", gremStmt)
+	}
+
+	nodes := make([]models.HierarchyIntegrityNode, 0, len(vertices))
+	for _, v := range vertices {
+		node, err := getHierarchyIntegrityNode(v)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	edges, err := n.getHierarchyEdges(nodeLabel)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	gremStmt = fmt.Sprintf(query.GetCodeListCodes, codeListID, edition)
+	codes, err := n.getStringList(gremStmt)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "Gremlin query failed: %q", gremStmt)
+	}
+
+	return nodes, edges, codes, nil
+}
+
+// getHierarchyEdges returns the 'hasParent' edges between the hierarchy nodes with the provided label
+func (n *NeptuneDB) getHierarchyEdges(nodeLabel string) ([]models.HierarchyEdge, error) {
+	gremStmt := fmt.Sprintf(query.GetHierarchyEdges, nodeLabel, nodeLabel)
+	values, err := n.getStringList(gremStmt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", gremStmt)
+	}
+
+	const valuesPerRecord = 2
+	records, err := createRecords(values, valuesPerRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]models.HierarchyEdge, 0, len(records))
+	for _, record := range records {
+		edges = append(edges, models.HierarchyEdge{Code: record[0], ParentCode: record[1]})
+	}
+	return edges, nil
+}
+
+// getHierarchyIntegrityNode reads the code and the optional numberOfChildren property of a hierarchy node vertex
+func getHierarchyIntegrityNode(v graphson.Vertex) (node models.HierarchyIntegrityNode, err error) {
+	if node.Code, err = v.GetProperty("code"); err != nil {
+		return node, errors.Wrap(err, "bad code property on hierarchy node")
+	}
+	if node.NumberOfChildren, err = getOptionalPropertyInt64(v, "numberOfChildren"); err != nil {
+		return node, errors.Wrap(err, "bad numberOfChildren property on hierarchy node")
+	}
+	return node, nil
+}
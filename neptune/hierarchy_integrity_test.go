@@ -0,0 +1,94 @@
+package neptune
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
+	"github.com/ONSdigital/graphson"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNeptuneDB_CheckInstanceHierarchy(t *testing.T) {
+
+	hierarchyVertex := func(code string, numberOfChildren float64) graphson.Vertex {
+		v, err := internal.MakeHierarchyVertex("_hierarchy_node_123_geography", code, code+"-label", numberOfChildren, true)
+		if err != nil {
+			t.Fail()
+		}
+		internal.SetCodeList(&v, "mmm")
+		return v
+	}
+
+	Convey("Given a neptune DB with an instance hierarchy that has a node with two parents", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				if query == `g.V().hasLabel('_hierarchy_node_123_geography').limit(1)` {
+					return []graphson.Vertex{hierarchyVertex("root", 2)}, nil
+				}
+				return []graphson.Vertex{
+					hierarchyVertex("root", 2),
+					hierarchyVertex("a", 1),
+					hierarchyVertex("b", 1),
+				}, nil
+			},
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				if query == `g.V().has('_code_list', 'listID', 'mmm').has('edition', 'one-off').in('usedBy').values('value').dedup()` {
+					return []string{"root", "a", "b"}, nil
+				}
+				return []string{"a", "root", "b", "root", "b", "a"}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When CheckInstanceHierarchy is called", func() {
+			report, err := db.CheckInstanceHierarchy(ctx, "123", "geography", "one-off")
+
+			Convey("Then the expected queries are executed", func() {
+				So(poolMock.GetCalls(), ShouldHaveLength, 2)
+				So(poolMock.GetCalls()[0].Query, ShouldEqual, `g.V().hasLabel('_hierarchy_node_123_geography').limit(1)`)
+				So(poolMock.GetCalls()[1].Query, ShouldEqual, `g.V().hasLabel('_hierarchy_node_123_geography')`)
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 2)
+				So(poolMock.GetStringListCalls()[0].Query, ShouldEqual,
+					`g.V().hasLabel('_hierarchy_node_123_geography').as('child').out('hasParent').hasLabel('_hierarchy_node_123_geography').as('parent')`+
+						`.select('child', 'parent').by('code').unfold().select(values)`)
+			})
+
+			Convey("Then the expected report is returned", func() {
+				So(err, ShouldBeNil)
+				So(report, ShouldResemble, &models.HierarchyIntegrityReport{
+					CodeListID:           "mmm",
+					CodeListEdition:      "one-off",
+					InstanceID:           "123",
+					Dimension:            "geography",
+					Cycles:               []string{},
+					MultipleParents:      []string{"b"},
+					Orphans:              []string{},
+					MissingCodes:         []string{},
+					ChildCountMismatches: []string{"b"},
+				})
+			})
+		})
+	})
+
+	Convey("Given a neptune DB that returns an odd number of edge values", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{hierarchyVertex("root", 0)}, nil
+			},
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{"a"}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When CheckGenericHierarchy is called", func() {
+			report, err := db.CheckGenericHierarchy(ctx, "mmm", "one-off")
+
+			Convey("Then the expected error is returned", func() {
+				So(report, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "list length is not divisible by 2")
+			})
+		})
+	})
+}
@@ -0,0 +1,249 @@
+package neptune
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neptune/query"
+	"github.com/ONSdigital/graphson"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// SyncInstanceHierarchy compares the hierarchy of an instance dimension with the generic hierarchy it was cloned
+// from, by code, and applies the label, parent and order changes in place. hasData is preserved and
+// numberOfChildren is recomputed if any node has been reparented.
+func (n *NeptuneDB) SyncInstanceHierarchy(ctx context.Context, instanceID, dimension string) (*models.HierarchySyncReport, error) {
+	logData := log.Data{"instance_id": instanceID, "dimension_name": dimension}
+
+	// the hierarchy may just have been built, so it is read from the writer
+	w := n.writer()
+
+	codeListID, err := w.GetHierarchyCodelist(ctx, instanceID, dimension)
+	if err != nil {
+		return nil, err
+	}
+	logData["code_list_id"] = codeListID
+
+	// the labels synchronised are those of the generic hierarchy
+	labelKeys, err := w.getLabelKeys(codeListID)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := w.getHierarchySyncNodes(fmt.Sprintf("_hierarchy_node_%s_%s", instanceID, dimension), labelKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	generic, err := w.getHierarchySyncNodes(fmt.Sprintf("_generic_hierarchy_node_%s", codeListID), labelKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	// the order of a generic hierarchy node is the order of its code in the code list
+	codes := make([]string, 0, len(instance))
+	for _, node := range instance {
+		codes = append(codes, node.Code)
+	}
+	orders, err := n.GetCodesOrder(ctx, codeListID, codes)
+	if err != nil && err != driver.ErrNotFound {
+		return nil, err
+	}
+	for i := range generic {
+		if order, ok := orders[generic[i].Code]; ok && order != nil {
+			o := int64(*order)
+			generic[i].Order = &o
+		}
+	}
+
+	plan := models.NewHierarchySyncPlan(generic, instance)
+	report := plan.Report()
+	report.CodeListID = codeListID
+	report.InstanceID = instanceID
+	report.Dimension = dimension
+
+	logData["report"] = report
+	if plan.IsEmpty() {
+		log.Info(ctx, "instance hierarchy already in line with the generic hierarchy", logData)
+		return report, nil
+	}
+	log.Info(ctx, "syncing instance hierarchy with the generic hierarchy", logData)
+
+	processBatch := func(chunk map[string]string) (map[string]string, error) {
+		gremStmt := syncStatement(instanceID, dimension, chunk)
+		if _, err := n.exec(gremStmt); err != nil {
+			log.Error(ctx, "failed to sync instance hierarchy nodes", err, log.Data{"gremlin": gremStmt})
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	_, _, errs := processInConcurrentBatches(syncParts(instanceID, dimension, plan), processBatch, n.batchSizeWriter, n.maxWorkers)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	if len(plan.Reparent) > 0 {
+		if err := n.SetNumberOfChildren(ctx, 1, instanceID, dimension); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// getHierarchySyncNodes returns the hierarchy nodes with the provided label, with the codes of their parents,
+// projecting only their code, order and the provided label properties
+func (n *NeptuneDB) getHierarchySyncNodes(nodeLabel string, labelKeys []string) ([]models.HierarchySyncNode, error) {
+	keys := append([]string{"code", "order"}, labelKeys...)
+	gremStmt := fmt.Sprintf(query.GetHierarchySyncNodes, nodeLabel, `'`+strings.Join(keys, `','`)+`'`)
+	res, err := n.exec(gremStmt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Gremlin query failed: %q", gremStmt)
+	}
+
+	edges, err := n.getHierarchyEdges(nodeLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make(map[string][]string)
+	for _, e := range edges {
+		parents[e.Code] = append(parents[e.Code], e.ParentCode)
+	}
+
+	nodes := make([]models.HierarchySyncNode, 0)
+
+	// responses are batched by gremgo library, hence we need to iterate them
+	for _, result := range res {
+		valueMaps, err := graphson.DeserializeListFromBytes(result.Result.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rawValueMap := range valueMaps {
+			valueMap, err := graphson.DeserializeMapFromBytes(rawValueMap)
+			if err != nil {
+				return nil, err
+			}
+
+			node, err := getHierarchySyncNode(valueMap)
+			if err != nil {
+				return nil, err
+			}
+			node.ParentCodes = parents[node.Code]
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// getHierarchySyncNode reads the code, the label properties and the optional order property of the value map of a hierarchy node
+func getHierarchySyncNode(valueMap map[string]json.RawMessage) (node models.HierarchySyncNode, err error) {
+	rawCode, ok := valueMap["code"]
+	if !ok {
+		return node, errors.New("missing code property on hierarchy node")
+	}
+	if err = unmarshalSingleValue(rawCode, &node.Code); err != nil {
+		return node, errors.Wrap(err, "bad code property on hierarchy node")
+	}
+
+	node.Labels = make(map[string]string)
+	for key, rawLabel := range valueMap {
+		if key != "label" && !strings.HasPrefix(key, "label_") {
+			continue
+		}
+		var label string
+		if err = unmarshalSingleValue(rawLabel, &label); err != nil {
+			return node, errors.Wrapf(err, "bad %s property on hierarchy node", key)
+		}
+		node.Labels[key] = label
+	}
+
+	if rawOrder, ok := valueMap["order"]; ok {
+		var order graphson.GenericValue
+		if err = unmarshalSingleValue(rawOrder, &order); err != nil {
+			return node, errors.Wrap(err, "bad order property on hierarchy node")
+		}
+		o, ok := order.Value.(float64)
+		if !ok {
+			return node, errors.Errorf("bad order property on hierarchy node: unexpected %s value", order.Type)
+		}
+		i := int64(o)
+		node.Order = &i
+	}
+	return node, nil
+}
+
+// unmarshalSingleValue unmarshals the value of a single cardinality property, as listed in a value map, into v
+func unmarshalSingleValue(rawValues json.RawMessage, v interface{}) error {
+	values, err := graphson.DeserializeListFromBytes(rawValues)
+	if err != nil {
+		return err
+	}
+	if len(values) != 1 {
+		return errors.Errorf("expected a single value, got %d", len(values))
+	}
+	return json.Unmarshal(values[0], v)
+}
+
+// syncParts returns the part of a statement that changes each instance hierarchy node changed by the plan, keyed by code
+func syncParts(instanceID, dimension string, plan *models.HierarchySyncPlan) map[string]string {
+	parts := make(map[string]string)
+
+	for _, node := range plan.Relabel {
+		keys := make([]string, 0, len(node.Labels))
+		for key := range node.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			parts[node.Code] += fmt.Sprintf(query.SyncLabelPart, key, stringLiteral(node.Labels[key]))
+		}
+	}
+
+	for _, node := range plan.Reorder {
+		if node.Order == nil {
+			parts[node.Code] += query.SyncRemoveOrderPart
+			continue
+		}
+		parts[node.Code] += fmt.Sprintf(query.SyncOrderPart, *node.Order)
+	}
+
+	for _, node := range plan.Reparent {
+		parts[node.Code] += query.SyncRemoveParentsPart
+		for _, parent := range node.ParentCodes {
+			parts[node.Code] += fmt.Sprintf(query.SyncAddParentPart, instanceID, dimension, stringLiteral(parent))
+		}
+	}
+
+	return parts
+}
+
+// syncStatement returns a statement that applies the provided parts, keyed by code, to the instance hierarchy nodes,
+// ordered by code
+func syncStatement(instanceID, dimension string, parts map[string]string) string {
+	codes := make([]string, 0, len(parts))
+	for code := range parts {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var nodeParts string
+	for _, code := range codes {
+		nodeParts += fmt.Sprintf(query.SyncHierarchyNodePart, instanceID, dimension, stringLiteral(code), parts[code])
+	}
+	return fmt.Sprintf(query.SyncHierarchyNodes, nodeParts)
+}
+
+// stringLiteral escapes the provided text so that it can be used in a single quoted string of a Gremlin query
+func stringLiteral(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(text)
+}
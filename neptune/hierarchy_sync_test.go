@@ -0,0 +1,126 @@
+package neptune
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-graph/v2/models"
+	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
+	"github.com/ONSdigital/graphson"
+	"github.com/ONSdigital/gremgo-neptune"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNeptuneDB_SyncInstanceHierarchy(t *testing.T) {
+
+	// valueMapsResponse returns the response of a valueMap projection of the provided nodes, as code, label and order
+	valueMapsResponse := func(nodes ...[3]interface{}) []gremgo.Response {
+		singleValue := func(v interface{}) json.RawMessage {
+			rawValue, err := json.Marshal(v)
+			So(err, ShouldBeNil)
+			rawValues, err := json.Marshal(graphson.RawSlice{Type: "g:List", Value: []json.RawMessage{rawValue}})
+			So(err, ShouldBeNil)
+			return rawValues
+		}
+
+		valueMaps := make([]json.RawMessage, 0, len(nodes))
+		for _, node := range nodes {
+			m := map[string]json.RawMessage{
+				"code":  singleValue(node[0]),
+				"label": singleValue(node[1]),
+			}
+			if node[2] != nil {
+				m["order"] = singleValue(graphson.GenericValue{Type: "g:Int32", Value: node[2]})
+			}
+			rawMap, err := SerializeMap(m)
+			So(err, ShouldBeNil)
+			valueMaps = append(valueMaps, rawMap)
+		}
+
+		rawList, err := json.Marshal(graphson.RawSlice{Type: "g:List", Value: valueMaps})
+		So(err, ShouldBeNil)
+		return []gremgo.Response{{Status: gremgo.Status{Code: 200}, Result: gremgo.Result{Data: rawList}}}
+	}
+
+	Convey("Given a neptune DB with an instance hierarchy with a node relabelled, reparented and reordered in the generic hierarchy", t, func() {
+		instanceNodes := valueMapsResponse(
+			[3]interface{}{"root", "Root", nil},
+			[3]interface{}{"x", "X", nil},
+			[3]interface{}{"a", "A", 1},
+		)
+		genericNodes := valueMapsResponse(
+			[3]interface{}{"root", "Root", nil},
+			[3]interface{}{"x", "X", nil},
+			[3]interface{}{"a", "A's new label", nil},
+		)
+
+		codeListVertex, err := internal.MakeHierarchyVertex("_hierarchy_node_123_geography", "root", "Root", 0, true)
+		So(err, ShouldBeNil)
+		internal.SetCodeList(&codeListVertex, "mmm")
+
+		order := 3
+		rawOrders, err := json.Marshal(graphson.RawSlice{
+			Type:  "g:List",
+			Value: []json.RawMessage{mockCodeEdgeMapResponse("a", &order)},
+		})
+		So(err, ShouldBeNil)
+
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{codeListVertex}, nil
+			},
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				switch {
+				case query == `g.V().hasLabel('_generic_hierarchy_node_mmm').properties().key().dedup()`:
+					return []string{"code", "label", "code_list", "numberOfChildren"}, nil
+				case strings.HasPrefix(query, `g.V().hasLabel('_hierarchy_node_123_geography')`):
+					return []string{"x", "root", "a", "root"}, nil
+				}
+				return []string{"x", "root", "a", "x"}, nil
+			},
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				switch query {
+				case `g.V().hasLabel('_hierarchy_node_123_geography').valueMap('code','order','label')`:
+					return instanceNodes, nil
+				case `g.V().hasLabel('_generic_hierarchy_node_mmm').valueMap('code','order','label')`:
+					return genericNodes, nil
+				}
+				if strings.HasPrefix(query, `g.V().hasLabel('_code_list')`) {
+					return []gremgo.Response{{Status: gremgo.Status{Code: 200}, Result: gremgo.Result{Data: rawOrders}}}, nil
+				}
+				return nil, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When SyncInstanceHierarchy is called", func() {
+			report, err := db.SyncInstanceHierarchy(ctx, "123", "geography")
+
+			Convey("Then the expected report is returned", func() {
+				So(err, ShouldBeNil)
+				So(report, ShouldResemble, &models.HierarchySyncReport{
+					CodeListID: "mmm",
+					InstanceID: "123",
+					Dimension:  "geography",
+					Relabelled: []string{"a"},
+					Reparented: []string{"a"},
+					Reordered:  []string{"a"},
+					Unresolved: []string{},
+				})
+			})
+
+			Convey("Then the nodes are updated in a single batched statement and the number of children recomputed", func() {
+				calls := poolMock.ExecuteCalls()
+				So(calls, ShouldHaveLength, 5)
+				So(calls[3].Query, ShouldEqual, `g.inject(0)`+
+					`.sideEffect(V().hasLabel('_hierarchy_node_123_geography').has('code','a')`+
+					`.property(single,'label','A\'s new label')`+
+					`.property(single,'order',3)`+
+					`.sideEffect(outE('hasParent').drop())`+
+					`.sideEffect(addE('hasParent').to(V().hasLabel('_hierarchy_node_123_geography').has('code','x'))))`)
+				So(calls[4].Query, ShouldEqual, `g.V().hasLabel('_hierarchy_node_123_geography').property(single,'numberOfChildren',__.in('hasParent').count())`)
+			})
+		})
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
 	"github.com/ONSdigital/dp-graph/v2/neptune/query"
 	"github.com/ONSdigital/graphson"
@@ -464,6 +465,9 @@ func TestNeptuneDB_CloneNodesFromID(t *testing.T) {
 
 	Convey("Given a neptune DB", t, func() {
 		poolMock := &internal.NeptunePoolMock{
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{"code", "label", "hasData"}, nil
+			},
 			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) (responses []gremgo.Response, err error) {
 				return []gremgo.Response{}, nil
 			},
@@ -500,10 +504,50 @@ func TestNeptuneDB_CloneNodesFromID(t *testing.T) {
 			})
 
 			Convey("Then no query is executed", func() {
+				So(len(poolMock.GetStringListCalls()), ShouldEqual, 0)
 				So(len(poolMock.ExecuteCalls()), ShouldEqual, 0)
 			})
 		})
 	})
+
+	Convey("Given a neptune DB with generic hierarchy nodes that have labels in other languages", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{"code", "label_gd", "label", "label_cy"}, nil
+			},
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) (responses []gremgo.Response, err error) {
+				return []gremgo.Response{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When CloneNodes is called with a map of IDs", func() {
+			err := db.CloneNodesFromIDs(ctx, testAttempt, testInstanceID, testCodeListID, testDimensionName, map[string]string{"cpih1dim1aggid--cpih1dim1S90401": "cpih1dim1S90401"}, false)
+
+			Convey("Then no error is returned", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the property keys of the generic hierarchy nodes are queried", func() {
+				So(len(poolMock.GetStringListCalls()), ShouldEqual, 1)
+				So(poolMock.GetStringListCalls()[0].Query, ShouldEqual, `g.V().hasLabel('_generic_hierarchy_node_cpih1dim1aggid').properties().key().dedup()`)
+			})
+
+			Convey("Then the expected query is sent to Neptune to clone the nodes along with their labels in other languages", func() {
+				expectedQuery := `g.V('cpih1dim1aggid--cpih1dim1S90401').as('old')` +
+					`.addV('_hierarchy_node_f0a2f3f2-cc86-4bbb-a549-ffc99c89292c_aggregate')` +
+					`.property(single,'code',select('old').values('code'))` +
+					`.property(single,'label',select('old').values('label'))` +
+					`.property(single,'hasData', false)` +
+					`.property('code_list','cpih1dim1aggid').as('new')` +
+					`.sideEffect(select('old').has('label_cy').select('new').property(single,'label_cy',select('old').values('label_cy')))` +
+					`.sideEffect(select('old').has('label_gd').select('new').property(single,'label_gd',select('old').values('label_gd')))` +
+					`.addE('clone_of').to('old')`
+				So(len(poolMock.ExecuteCalls()), ShouldEqual, 1)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldEqual, expectedQuery)
+			})
+		})
+	})
 }
 
 func TestNeptuneDB_CountNodes(t *testing.T) {
@@ -778,6 +822,82 @@ func TestNeptuneDB_SetHasData(t *testing.T) {
 	})
 }
 
+func TestNeptuneDB_SetRollupCounts(t *testing.T) {
+
+	Convey("Given a neptune DB with a hierarchy and the observation counts of the dimension options", t, func() {
+		rawCounts, err := SerializeMap(map[string]json.RawMessage{
+			"a": json.RawMessage(`{"@type":"g:Int64","@value":3}`),
+			"b": json.RawMessage(`{"@type":"g:Int64","@value":4}`),
+		})
+		So(err, ShouldBeNil)
+		rawList, err := json.Marshal(graphson.RawSlice{Type: "g:List", Value: []json.RawMessage{rawCounts}})
+		So(err, ShouldBeNil)
+
+		poolMock := &internal.NeptunePoolMock{
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				if query == `g.V().hasLabel('_hierarchy_node_instanceID_dimensionName').values('code')` {
+					return []string{"root", "a", "b"}, nil
+				}
+				return []string{"a", "root", "b", "root"}, nil
+			},
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				if strings.HasPrefix(query, `g.V().hasLabel('_instanceID_dimensionName')`) {
+					return []gremgo.Response{{Status: gremgo.Status{Code: 200}, Result: gremgo.Result{Data: rawList}}}, nil
+				}
+				return []gremgo.Response{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When SetRollupCounts is called", func() {
+			err := db.SetRollupCounts(ctx, 1, "instanceID", "dimensionName")
+
+			Convey("Then no error is returned", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the observation counts are queried and the rollup counts are set on each node", func() {
+				calls := poolMock.ExecuteCalls()
+				So(calls, ShouldHaveLength, 2)
+				So(calls[0].Query, ShouldEqual, `g.V().hasLabel('_instanceID_dimensionName').group().by('value').by(__.in('isValueOf').count())`)
+				So(calls[1].Query, ShouldStartWith, "g.V()")
+				for _, part := range []string{
+					`.V().hasLabel('_hierarchy_node_instanceID_dimensionName').has('code','root').property(single,'numberOfOptions',2).property(single,'numberOfObservations',7)`,
+					`.V().hasLabel('_hierarchy_node_instanceID_dimensionName').has('code','a').property(single,'numberOfOptions',1).property(single,'numberOfObservations',3)`,
+					`.V().hasLabel('_hierarchy_node_instanceID_dimensionName').has('code','b').property(single,'numberOfOptions',1).property(single,'numberOfObservations',4)`,
+				} {
+					So(calls[1].Query, ShouldContainSubstring, part)
+				}
+			})
+		})
+	})
+}
+
+func TestRollupCounts(t *testing.T) {
+	Convey("Given a hierarchy where a node is reachable through two parents", t, func() {
+		codes := []string{"root", "x", "y", "shared", "empty"}
+		edges := []models.HierarchyEdge{
+			{Code: "x", ParentCode: "root"},
+			{Code: "y", ParentCode: "root"},
+			{Code: "shared", ParentCode: "x"},
+			{Code: "shared", ParentCode: "y"},
+		}
+		observationCounts := map[string]int64{"shared": 10, "x": 1, "empty": 0}
+
+		Convey("When rollupCounts is called", func() {
+			rollups := rollupCounts(codes, edges, observationCounts)
+
+			Convey("Then each node counts the options of its descendants once", func() {
+				So(rollups["root"], ShouldResemble, rollup{options: 2, observations: 11})
+				So(rollups["x"], ShouldResemble, rollup{options: 2, observations: 11})
+				So(rollups["y"], ShouldResemble, rollup{options: 1, observations: 10})
+				So(rollups["shared"], ShouldResemble, rollup{options: 1, observations: 10})
+				So(rollups["empty"], ShouldResemble, rollup{options: 1, observations: 0})
+			})
+		})
+	})
+}
+
 // mockCodeEdgeMap generates a code-edge map with the expected code and order property for the usedBy edge
 func mockNodeIdCodeMap(expectedNodeId, expectedCode string) map[string]json.RawMessage {
 	rawNodeId, err := json.Marshal(expectedNodeId)
@@ -800,3 +920,162 @@ func mockNodeIdCodeMapResponse(expectedNodeId, expectedCode string) json.RawMess
 	So(err, ShouldBeNil)
 	return rawMap
 }
+
+func TestNeptuneDB_SearchHierarchy(t *testing.T) {
+
+	hierarchyVertex := func(code, label string) graphson.Vertex {
+		v, err := internal.MakeHierarchyVertex("_hierarchy_node_"+testInstanceID+"_"+testDimensionName, code, label, 0, true)
+		if err != nil {
+			t.Fail()
+		}
+		return v
+	}
+
+	Convey("Given a neptune DB that returns matching nodes and their ancestry", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				if strings.Contains(query, "regex") {
+					return []graphson.Vertex{
+						hierarchyVertex("W06000015", "Cardiff"),
+						hierarchyVertex("E08000001", "North Carlton"),
+					}, nil
+				}
+				if strings.Contains(query, "W06000015") {
+					return []graphson.Vertex{hierarchyVertex("W92000004", "Wales")}, nil
+				}
+				return []graphson.Vertex{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When SearchHierarchy is called", func() {
+			elements, err := db.SearchHierarchy(ctx, testInstanceID, testDimensionName, "car", 2)
+
+			Convey("Then no error is returned", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the hierarchy is searched with a case-insensitive regular expression, ordered and limited in the traversal", func() {
+				So(poolMock.GetCalls()[0].Query, ShouldEqual,
+					`g.V().hasLabel('_hierarchy_node_f0a2f3f2-cc86-4bbb-a549-ffc99c89292c_aggregate')`+
+						`.where(values('label').is(regex('(?i)car')))`+
+						`.order().by(choose(values('label').is(regex('(?i)^car')),constant(0),constant(1))).by(values('label'))`+
+						`.limit(2)`)
+			})
+
+			Convey("Then the nodes are returned in the order of the traversal, with their breadcrumbs", func() {
+				So(elements, ShouldHaveLength, 2)
+				So(elements[0].ID, ShouldEqual, "W06000015")
+				So(elements[0].Breadcrumbs, ShouldHaveLength, 1)
+				So(elements[0].Breadcrumbs[0].ID, ShouldEqual, "W92000004")
+				So(elements[1].ID, ShouldEqual, "E08000001")
+				So(elements[1].Breadcrumbs, ShouldBeEmpty)
+				So(poolMock.GetCalls(), ShouldHaveLength, 3)
+			})
+		})
+
+		Convey("When SearchHierarchy is called with a language", func() {
+			_, err := db.SearchHierarchy(ctx, testInstanceID, testDimensionName, "car", 2, driver.WithLanguage("cy"))
+
+			Convey("Then the labels in the language are searched, falling back to the default label", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.GetCalls()[0].Query, ShouldEqual,
+					`g.V().hasLabel('_hierarchy_node_f0a2f3f2-cc86-4bbb-a549-ffc99c89292c_aggregate')`+
+						`.where(coalesce(values('label_cy'),values('label')).is(regex('(?i)car')))`+
+						`.order().by(choose(coalesce(values('label_cy'),values('label')).is(regex('(?i)^car')),constant(0),constant(1))).by(coalesce(values('label_cy'),values('label')))`+
+						`.limit(2)`)
+			})
+		})
+
+		Convey("When SearchHierarchy is called with text containing special characters", func() {
+			_, err := db.SearchHierarchy(ctx, testInstanceID, testDimensionName, "St. John's", 10)
+
+			Convey("Then the text is escaped in the query", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.GetCalls()[0].Query, ShouldContainSubstring, `.where(values('label').is(regex('(?i)St\\. John\'s')))`)
+			})
+		})
+
+		Convey("When SearchHierarchy is called with invalid parameters", func() {
+			_, errText := db.SearchHierarchy(ctx, testInstanceID, testDimensionName, "", 10)
+			_, errLimit := db.SearchHierarchy(ctx, testInstanceID, testDimensionName, "car", -1)
+
+			Convey("Then the expected errors are returned and the database is not queried", func() {
+				So(errText.Error(), ShouldEqual, "search text is required but was empty")
+				So(errLimit.Error(), ShouldEqual, "search limit must be greater than zero")
+				So(poolMock.GetCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestNeptuneDB_GetHierarchyRoots(t *testing.T) {
+
+	rootVertex := func(code, label string) graphson.Vertex {
+		v, err := internal.MakeHierarchyVertex("_hierarchy_node_"+testInstanceID+"_"+testDimensionName, code, label, 0, true)
+		if err != nil {
+			t.Fail()
+		}
+		return v
+	}
+
+	Convey("Given a neptune DB with a hierarchy that has several roots", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{rootVertex("K04000001", "England and Wales"), rootVertex("S92000003", "Scotland")}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetHierarchyRoots is called", func() {
+			roots, err := db.GetHierarchyRoots(ctx, testInstanceID, testDimensionName)
+
+			Convey("Then all the roots are returned", func() {
+				So(err, ShouldBeNil)
+				So(roots, ShouldHaveLength, 2)
+				So(roots[0].ID, ShouldEqual, "K04000001")
+				So(roots[1].ID, ShouldEqual, "S92000003")
+			})
+
+			Convey("Then the roots are queried ordered by label", func() {
+				So(poolMock.GetCalls(), ShouldHaveLength, 1)
+				So(poolMock.GetCalls()[0].Query, ShouldEqual,
+					`g.V().hasLabel('_hierarchy_node_f0a2f3f2-cc86-4bbb-a549-ffc99c89292c_aggregate').not(outE('hasParent')).order().by('label')`)
+			})
+		})
+
+		Convey("When GetHierarchyRoot is called", func() {
+			_, err := db.GetHierarchyRoot(ctx, testInstanceID, testDimensionName)
+
+			Convey("Then ErrMultipleFound is returned", func() {
+				So(err, ShouldEqual, driver.ErrMultipleFound)
+			})
+		})
+
+		Convey("When GetHierarchyRoot is called requiring a single root", func() {
+			_, err := db.GetHierarchyRoot(ctx, testInstanceID, testDimensionName, driver.WithSingleRoot())
+
+			Convey("Then ErrMultipleRoots is returned with the codes of the roots", func() {
+				So(err, ShouldResemble, driver.ErrMultipleRoots{Codes: []string{"K04000001", "S92000003"}})
+				So(errors.Is(err, driver.ErrMultipleFound), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a neptune DB with a hierarchy that has no roots", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetHierarchyRoots is called", func() {
+			_, err := db.GetHierarchyRoots(ctx, testInstanceID, testDimensionName)
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(err, ShouldEqual, driver.ErrNotFound)
+			})
+		})
+	})
+}
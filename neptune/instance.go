@@ -3,11 +3,15 @@ package neptune
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
 
 	"github.com/ONSdigital/dp-graph/v2/neptune/query"
+	"github.com/ONSdigital/graphson"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/pkg/errors"
 )
@@ -39,16 +43,36 @@ func (n *NeptuneDB) AddVersionDetailsToInstance(ctx context.Context, instanceID
 	return nil
 }
 
-// SetInstanceIsPublished sets a flag on an instance node to indicate the published state
+// SetInstanceIsPublished sets the publish state of an instance node to published, and marks the
+// instances previously published for the same dataset edition as superseded
 func (n *NeptuneDB) SetInstanceIsPublished(ctx context.Context, instanceID string) error {
+	if err := n.SetInstancePublishState(ctx, instanceID, models.PublishStatePublished); err != nil {
+		return err
+	}
+
+	if _, err := n.exec(fmt.Sprintf(query.SupersedePublishedInstances, instanceID)); err != nil {
+		log.Error(ctx, "neptune exec failed on SetInstanceIsPublished", err, log.Data{"instance_id": instanceID})
+		return err
+	}
+	return nil
+}
+
+// SetInstancePublishState sets the publish state of an instance node. The is_published flag
+// is kept in step with the state, so superseded instances remain published.
+func (n *NeptuneDB) SetInstancePublishState(ctx context.Context, instanceID string, state models.PublishState) error {
+	if err := state.Validate(); err != nil {
+		return err
+	}
+
 	data := log.Data{
-		"instance_id": instanceID,
+		"instance_id":   instanceID,
+		"publish_state": state,
 	}
 
-	q := fmt.Sprintf(query.SetInstanceIsPublished, instanceID)
+	q := fmt.Sprintf(query.SetInstancePublishState, instanceID, state.IsPublished(), state)
 
 	if _, err := n.exec(q); err != nil {
-		log.Error(ctx, "neptune exec failed on SetInstanceIsPublished", err, data)
+		log.Error(ctx, "neptune exec failed on SetInstancePublishState", err, data)
 		return err
 	}
 	return nil
@@ -126,7 +150,7 @@ func (n *NeptuneDB) CreateCodeRelationship(ctx context.Context, instanceID, code
 	}
 
 	getCode := fmt.Sprintf(query.GetCode, code, codeListID)
-	existingCodes, err := n.getStringList(getCode)
+	existingCodes, err := n.writer().getStringList(getCode)
 	if err != nil {
 		return err
 	}
@@ -164,3 +188,259 @@ func (n *NeptuneDB) InstanceExists(ctx context.Context, instanceID string) (bool
 
 	return true, nil
 }
+
+// GetInstance returns the details stored on the instance node with the provided id
+func (n *NeptuneDB) GetInstance(ctx context.Context, instanceID string) (*models.Instance, error) {
+	if len(instanceID) == 0 {
+		return nil, errors.New("instance id is required but was empty")
+	}
+
+	data := log.Data{
+		"instance_id": instanceID,
+	}
+
+	vertices, err := n.getVertices(fmt.Sprintf(query.GetInstance, instanceID))
+	if err != nil {
+		log.Error(ctx, "neptune getVertices failed on GetInstance", err, data)
+		return nil, err
+	}
+	if len(vertices) == 0 {
+		return nil, driver.ErrNotFound
+	}
+	if len(vertices) > 1 {
+		return nil, driver.ErrMultipleFound
+	}
+
+	return convertVertexToInstance(vertices[0])
+}
+
+// ListInstances returns the instances matching the provided filter, ordered by dataset,
+// edition and version. A nil filter returns all instances. Instances are found by their
+// _<id>_Instance label and header property.
+func (n *NeptuneDB) ListInstances(ctx context.Context, filter *models.InstanceFilter) ([]*models.Instance, error) {
+	q := query.ListInstancesPart
+	if filter != nil {
+		if len(filter.DatasetID) > 0 {
+			q += fmt.Sprintf(query.InstanceDatasetIDFilterPart, filter.DatasetID)
+		}
+		if len(filter.Edition) > 0 {
+			q += fmt.Sprintf(query.InstanceEditionFilterPart, filter.Edition)
+		}
+		if filter.IsPublished != nil {
+			if *filter.IsPublished {
+				q += query.InstancePublishedFilterPart
+			} else {
+				q += query.InstanceNotPublishedFilterPart
+			}
+		}
+	}
+
+	vertices, err := n.getVertices(q)
+	if err != nil {
+		log.Error(ctx, "neptune getVertices failed on ListInstances", err, log.Data{"statement": q})
+		return nil, err
+	}
+
+	instances := make([]*models.Instance, 0, len(vertices))
+	for _, v := range vertices {
+		instance, err := convertVertexToInstance(v)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if a.DatasetID != b.DatasetID {
+			return a.DatasetID < b.DatasetID
+		}
+		if a.Edition != b.Edition {
+			return a.Edition < b.Edition
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.InstanceID < b.InstanceID
+	})
+
+	return instances, nil
+}
+
+// convertVertexToInstance maps an instance vertex to an instance. The version details,
+// dimensions and published flag are optional, as they are added after the instance is created.
+func convertVertexToInstance(v graphson.Vertex) (*models.Instance, error) {
+	id := v.GetID()
+	if !strings.HasPrefix(id, "_") || !strings.HasSuffix(id, "_Instance") {
+		return nil, errors.Errorf("vertex %q is not an instance", id)
+	}
+
+	instance := &models.Instance{
+		InstanceID: strings.TrimSuffix(strings.TrimPrefix(id, "_"), "_Instance"),
+	}
+
+	header, err := v.GetProperty("header")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get header of instance %q", instance.InstanceID)
+	}
+	instance.CSVHeader = strings.Split(header, ",")
+
+	dimensions, err := v.GetMultiProperty("dimensions")
+	if err != nil && err != graphson.ErrorPropertyNotFound {
+		return nil, errors.Wrapf(err, "failed to get dimensions of instance %q", instance.InstanceID)
+	}
+	for _, d := range dimensions {
+		instance.Dimensions = append(instance.Dimensions, d)
+	}
+
+	if instance.DatasetID, err = getOptionalProperty(v, "dataset_id"); err != nil {
+		return nil, errors.Wrapf(err, "failed to get dataset_id of instance %q", instance.InstanceID)
+	}
+
+	if instance.Edition, err = getOptionalProperty(v, "edition"); err != nil {
+		return nil, errors.Wrapf(err, "failed to get edition of instance %q", instance.InstanceID)
+	}
+
+	// the version is stored as a string
+	version, err := getOptionalProperty(v, "version")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get version of instance %q", instance.InstanceID)
+	}
+	if len(version) > 0 {
+		if instance.Version, err = strconv.Atoi(version); err != nil {
+			return nil, errors.Wrapf(err, "invalid version of instance %q", instance.InstanceID)
+		}
+	}
+
+	instance.IsPublished, err = v.GetPropertyBool("is_published")
+	if err != nil && err != graphson.ErrorPropertyNotFound {
+		return nil, errors.Wrapf(err, "failed to get is_published of instance %q", instance.InstanceID)
+	}
+
+	state, err := getOptionalProperty(v, "publish_state")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get publish_state of instance %q", instance.InstanceID)
+	}
+	instance.PublishState = models.PublishState(state)
+	// instances published before publish states were introduced only have the is_published flag
+	if len(instance.PublishState) == 0 && instance.IsPublished {
+		instance.PublishState = models.PublishStatePublished
+	}
+
+	return instance, nil
+}
+
+// ValidateInstance checks the consistency of an imported instance, for each of the
+// dimensions recorded on the instance node. The instance is read from the writer, as
+// it is validated straight after being imported.
+func (n *NeptuneDB) ValidateInstance(ctx context.Context, instanceID string) (*models.InstanceValidationReport, error) {
+	w := n.writer()
+
+	exists, err := w.InstanceExists(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, driver.ErrNotFound
+	}
+
+	dimensions, err := w.getStringList(fmt.Sprintf(query.GetInstanceDimensions, instanceID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get instance dimensions")
+	}
+	sort.Strings(dimensions)
+
+	count, err := w.CountInsertedObservations(ctx, instanceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count observations")
+	}
+
+	report := &models.InstanceValidationReport{
+		InstanceID:       instanceID,
+		ObservationCount: count,
+		Dimensions:       make([]*models.DimensionValidationReport, 0, len(dimensions)),
+	}
+
+	for _, dimension := range dimensions {
+		d, err := w.validateDimension(ctx, instanceID, dimension)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to validate dimension %q", dimension)
+		}
+		report.Dimensions = append(report.Dimensions, d)
+	}
+
+	log.Info(ctx, "instance validated", log.Data{"instance_id": instanceID, "is_valid": report.IsValid()})
+	return report, nil
+}
+
+func (n *NeptuneDB) validateDimension(ctx context.Context, instanceID, dimension string) (d *models.DimensionValidationReport, err error) {
+	d = &models.DimensionValidationReport{Name: dimension}
+
+	if d.ObservationsMissingEdges, err = n.getNumber(fmt.Sprintf(query.CountObservationsMissingDimension, instanceID, instanceID, dimension)); err != nil {
+		return nil, err
+	}
+
+	if d.OptionsWithoutObservations, err = n.getSortedStringList(fmt.Sprintf(query.GetDimensionOptionsWithoutObservations, instanceID, dimension)); err != nil {
+		return nil, err
+	}
+
+	if d.OrphanOptions, err = n.getSortedStringList(fmt.Sprintf(query.GetOrphanDimensionOptions, instanceID, dimension, instanceID)); err != nil {
+		return nil, err
+	}
+
+	if d.HasHierarchy, err = n.HierarchyExists(ctx, instanceID, dimension); err != nil {
+		return nil, err
+	}
+	if !d.HasHierarchy {
+		return d, nil
+	}
+
+	codesWithData, err := n.GetCodesWithData(ctx, 1, instanceID, dimension)
+	if err != nil {
+		return nil, err
+	}
+	flagged, err := n.getStringList(fmt.Sprintf(query.GetHierarchyCodesWithHasData, instanceID, dimension))
+	if err != nil {
+		return nil, err
+	}
+	unflagged, err := n.getStringList(fmt.Sprintf(query.GetHierarchyCodesWithoutHasData, instanceID, dimension))
+	if err != nil {
+		return nil, err
+	}
+
+	d.HasDataMismatches = hasDataMismatches(codesWithData, flagged, unflagged)
+	return d, nil
+}
+
+func (n *NeptuneDB) getSortedStringList(gremStmt string) ([]string, error) {
+	list, err := n.getStringList(gremStmt)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// hasDataMismatches returns the sorted hierarchy codes flagged with hasData that have no data,
+// and those not flagged that do have data
+func hasDataMismatches(codesWithData, flagged, unflagged []string) []string {
+	withData := make(map[string]bool, len(codesWithData))
+	for _, code := range codesWithData {
+		withData[code] = true
+	}
+
+	mismatches := make([]string, 0)
+	for _, code := range flagged {
+		if !withData[code] {
+			mismatches = append(mismatches, code)
+		}
+	}
+	for _, code := range unflagged {
+		if withData[code] {
+			mismatches = append(mismatches, code)
+		}
+	}
+
+	sort.Strings(mismatches)
+	return mismatches
+}
@@ -2,7 +2,10 @@ package neptune
 
 import (
 	"context"
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
+	"github.com/ONSdigital/graphson"
 	"github.com/ONSdigital/gremgo-neptune"
 	"github.com/pkg/errors"
 	"testing"
@@ -184,3 +187,315 @@ func TestNeptuneDB_CreateCodeRelationship(t *testing.T) {
 		})
 	})
 }
+
+func TestNeptuneDB_ValidateInstance(t *testing.T) {
+	ctx := context.Background()
+
+	createPoolMock := func() *internal.NeptunePoolMock {
+		return &internal.NeptunePoolMock{
+			GetCountFunc: func(query string, bindings map[string]string, rebindings map[string]string) (int64, error) {
+				switch query {
+				case "g.V('_123_Instance').count()":
+					return 1, nil
+				case "g.V().hasLabel('_123_observation').count()":
+					return 100, nil
+				case "g.V().hasLabel('_123_observation').not(out('isValueOf').hasLabel('_123_sex')).count()":
+					return 2, nil
+				}
+				return 0, nil
+			},
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				switch query {
+				case "g.V().hasId('_123_Instance').values('dimensions')":
+					return []string{"sex", "geography"}, nil
+				case "g.V().hasLabel('_123_sex').not(in('isValueOf')).values('value')":
+					return []string{"female", "any"}, nil
+				case "g.V().hasLabel('_123_geography').values('value')":
+					return []string{"K02000001", "E92000001"}, nil
+				case "g.V().hasLabel('_hierarchy_node_123_geography').has('hasData',true).values('code')":
+					return []string{"K02000001", "W92000004"}, nil
+				case "g.V().hasLabel('_hierarchy_node_123_geography').not(has('hasData',true)).values('code')":
+					return []string{"E92000001", "S92000003"}, nil
+				}
+				return []string{}, nil
+			},
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				if query == "g.V().hasLabel('_hierarchy_node_123_geography').limit(1)" {
+					vertices, err := internal.ReturnThreeCodeVertices(query, bindings, rebindings)
+					return vertices[:1], err
+				}
+				return []graphson.Vertex{}, nil
+			},
+		}
+	}
+
+	Convey("Given an instance with inconsistent dimensions", t, func() {
+		poolMock := createPoolMock()
+		db := mockDB(poolMock)
+
+		Convey("When ValidateInstance is called", func() {
+			report, err := db.ValidateInstance(ctx, "123")
+
+			Convey("Then the expected report is returned", func() {
+				So(err, ShouldBeNil)
+				So(report.InstanceID, ShouldEqual, "123")
+				So(report.ObservationCount, ShouldEqual, 100)
+				So(report.IsValid(), ShouldBeFalse)
+				So(report.Dimensions, ShouldHaveLength, 2)
+			})
+
+			Convey("Then the hierarchy of a dimension is checked against its options", func() {
+				geography := report.Dimensions[0]
+				So(geography.Name, ShouldEqual, "geography")
+				So(geography.HasHierarchy, ShouldBeTrue)
+				So(geography.HasDataMismatches, ShouldResemble, []string{"E92000001", "W92000004"})
+			})
+
+			Convey("Then missing edges and unused options are reported for a dimension", func() {
+				sex := report.Dimensions[1]
+				So(sex.Name, ShouldEqual, "sex")
+				So(sex.ObservationsMissingEdges, ShouldEqual, 2)
+				So(sex.OptionsWithoutObservations, ShouldResemble, []string{"any", "female"})
+				So(sex.OrphanOptions, ShouldBeEmpty)
+				So(sex.HasHierarchy, ShouldBeFalse)
+				So(sex.HasDataMismatches, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an instance that does not exist", t, func() {
+		poolMock := createPoolMock()
+		poolMock.GetCountFunc = internal.ReturnZero
+		db := mockDB(poolMock)
+
+		Convey("When ValidateInstance is called", func() {
+			report, err := db.ValidateInstance(ctx, "123")
+
+			Convey("Then a not found error is returned", func() {
+				So(report, ShouldBeNil)
+				So(err, ShouldEqual, driver.ErrNotFound)
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func Test_hasDataMismatches(t *testing.T) {
+	Convey("Given hierarchy codes with and without the hasData flag", t, func() {
+		codesWithData := []string{"a", "b", "c"}
+		flagged := []string{"a", "d"}
+		unflagged := []string{"b", "e"}
+
+		Convey("When hasDataMismatches is called", func() {
+			mismatches := hasDataMismatches(codesWithData, flagged, unflagged)
+
+			Convey("Then the codes whose flag does not match their data are returned", func() {
+				So(mismatches, ShouldResemble, []string{"b", "d"})
+			})
+		})
+	})
+}
+
+func TestNeptuneDB_GetInstance(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an instance with version details that has been published", t, func() {
+		vertex := internal.MakeInstanceVertex("instanceID", "V4_0,time,sex", "time", "sex")
+		internal.SetVersionDetails(&vertex, "datasetID", "2018", "3", true)
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{vertex}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetInstance is called", func() {
+			instance, err := db.GetInstance(ctx, "instanceID")
+
+			Convey("Then the instance vertex is requested", func() {
+				So(poolMock.GetCalls(), ShouldHaveLength, 1)
+				So(poolMock.GetCalls()[0].Query, ShouldEqual, "g.V().hasId('_instanceID_Instance')")
+			})
+
+			Convey("Then the instance details are returned", func() {
+				So(err, ShouldBeNil)
+				So(instance, ShouldResemble, &models.Instance{
+					InstanceID:   "instanceID",
+					CSVHeader:    []string{"V4_0", "time", "sex"},
+					Dimensions:   []interface{}{"time", "sex"},
+					DatasetID:    "datasetID",
+					Edition:      "2018",
+					Version:      3,
+					IsPublished:  true,
+					PublishState: models.PublishStatePublished,
+				})
+			})
+		})
+	})
+
+	Convey("Given an instance that has only been created", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{internal.MakeInstanceVertex("instanceID", "V4_0,time")}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetInstance is called", func() {
+			instance, err := db.GetInstance(ctx, "instanceID")
+
+			Convey("Then the optional details are left empty", func() {
+				So(err, ShouldBeNil)
+				So(instance, ShouldResemble, &models.Instance{
+					InstanceID: "instanceID",
+					CSVHeader:  []string{"V4_0", "time"},
+				})
+			})
+		})
+	})
+
+	Convey("Given an instance that does not exist", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetInstance is called", func() {
+			instance, err := db.GetInstance(ctx, "instanceID")
+
+			Convey("Then ErrNotFound is returned", func() {
+				So(instance, ShouldBeNil)
+				So(err, ShouldEqual, driver.ErrNotFound)
+			})
+		})
+	})
+}
+
+func TestNeptuneDB_ListInstances(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a database holding instances of several versions", t, func() {
+		v2 := internal.MakeInstanceVertex("2", "V4_0,time")
+		internal.SetVersionDetails(&v2, "datasetID", "2018", "2", false)
+		v1 := internal.MakeInstanceVertex("1", "V4_0,time")
+		internal.SetVersionDetails(&v1, "datasetID", "2018", "1", true)
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{v2, v1}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When ListInstances is called without a filter", func() {
+			instances, err := db.ListInstances(ctx, nil)
+
+			Convey("Then all instances are requested", func() {
+				So(poolMock.GetCalls(), ShouldHaveLength, 1)
+				So(poolMock.GetCalls()[0].Query, ShouldEqual, "g.V().hasLabel(endingWith('_Instance')).has('header')")
+			})
+
+			Convey("Then the instances are returned ordered by version", func() {
+				So(err, ShouldBeNil)
+				So(instances, ShouldHaveLength, 2)
+				So(instances[0].InstanceID, ShouldEqual, "1")
+				So(instances[1].InstanceID, ShouldEqual, "2")
+			})
+		})
+
+		Convey("When ListInstances is called with a filter", func() {
+			published := false
+			_, err := db.ListInstances(ctx, &models.InstanceFilter{
+				DatasetID:   "datasetID",
+				Edition:     "2018",
+				IsPublished: &published,
+			})
+
+			Convey("Then the filter is applied to the query", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.GetCalls()[0].Query, ShouldEqual,
+					"g.V().hasLabel(endingWith('_Instance')).has('header').has('dataset_id','datasetID').has('edition','2018').not(has('is_published',true))")
+			})
+		})
+	})
+
+	Convey("Given a database returning an error", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc: internal.ReturnMalformedNilInterfaceRequestErr,
+		}
+		db := mockDB(poolMock)
+
+		Convey("When ListInstances is called", func() {
+			instances, err := db.ListInstances(ctx, nil)
+
+			Convey("Then the error is returned", func() {
+				So(instances, ShouldBeNil)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestNeptuneDB_SetInstancePublishState(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a mock DB connection", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return []gremgo.Response{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When SetInstancePublishState is called with the superseded state", func() {
+			err := db.SetInstancePublishState(ctx, "instanceID", models.PublishStateSuperseded)
+
+			Convey("Then the instance remains published", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 1)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldEqual,
+					"g.V().hasId('_instanceID_Instance').property(single,'is_published',true).property(single,'publish_state','superseded')")
+			})
+		})
+
+		Convey("When SetInstancePublishState is called with the unpublished state", func() {
+			err := db.SetInstancePublishState(ctx, "instanceID", models.PublishStateUnpublished)
+
+			Convey("Then the instance is no longer published", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldEqual,
+					"g.V().hasId('_instanceID_Instance').property(single,'is_published',false).property(single,'publish_state','unpublished')")
+			})
+		})
+
+		Convey("When SetInstanceIsPublished is called", func() {
+			err := db.SetInstanceIsPublished(ctx, "instanceID")
+
+			Convey("Then the publish state of the instance is set to published", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 2)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldEqual,
+					"g.V().hasId('_instanceID_Instance').property(single,'is_published',true).property(single,'publish_state','published')")
+			})
+
+			Convey("Then the instances previously published for the same dataset edition are superseded", func() {
+				So(poolMock.ExecuteCalls()[1].Query, ShouldEqual,
+					"g.V().hasId('_instanceID_Instance').has('dataset_id').has('edition').as('i')"+
+						".V().hasLabel(endingWith('_Instance')).has('header').has('dataset_id').has('edition')"+
+						".has('is_published',true).not(has('publish_state','superseded')).where(neq('i'))"+
+						".where(eq('i')).by('dataset_id').where(eq('i')).by('edition')"+
+						".property(single,'publish_state','superseded')")
+			})
+		})
+
+		Convey("When SetInstancePublishState is called with an invalid state", func() {
+			err := db.SetInstancePublishState(ctx, "instanceID", models.PublishState("withdrawn"))
+
+			Convey("Then an error is returned and the database is not called", func() {
+				So(err.Error(), ShouldEqual, `invalid publish state "withdrawn"`)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
@@ -223,6 +223,60 @@ func SetOrder(vertex *graphson.Vertex, order float64) error {
 	return nil
 }
 
+// SetRollupCounts sets the number of options and observations of a hierarchy vertex, including descendants
+func SetRollupCounts(vertex *graphson.Vertex, options, observations float64) {
+	setVertexTypedProperty("g:Int64", vertex, "numberOfOptions", map[string]interface{}{"@type": "g:Int64", "@value": options})
+	setVertexTypedProperty("g:Int64", vertex, "numberOfObservations", map[string]interface{}{"@type": "g:Int64", "@value": observations})
+}
+
+// SetCodeList sets the code list of a hierarchy vertex
+func SetCodeList(vertex *graphson.Vertex, codeListID string) {
+	setVertexStringProperty(vertex, "code_list", codeListID)
+}
+
+// SetLocalisedLabel sets the label of a vertex in the provided language
+func SetLocalisedLabel(vertex *graphson.Vertex, language, label string) {
+	setVertexStringProperty(vertex, "label_"+language, label)
+}
+
+// MakeInstanceVertex makes an instance vertex with the provided header and dimensions
+func MakeInstanceVertex(instanceID, header string, dimensions ...string) graphson.Vertex {
+	vertex := makeVertex(fmt.Sprintf("_%s_Instance", instanceID))
+	vertex.Value.ID = fmt.Sprintf("_%s_Instance", instanceID)
+	setVertexStringProperty(&vertex, "header", header)
+	for _, d := range dimensions {
+		vertex.Value.Properties["dimensions"] = append(vertex.Value.Properties["dimensions"], graphson.VertexProperty{
+			Type:  "string",
+			Value: graphson.VertexPropertyValue{Label: "dimensions", Value: d},
+		})
+	}
+	return vertex
+}
+
+// SetVersionDetails sets the version details and published flag on an instance vertex
+func SetVersionDetails(vertex *graphson.Vertex, datasetID, edition, version string, isPublished bool) {
+	setVertexStringProperty(vertex, "dataset_id", datasetID)
+	setVertexStringProperty(vertex, "edition", edition)
+	setVertexStringProperty(vertex, "version", version)
+	setVertexTypedProperty("bool", vertex, "is_published", isPublished)
+}
+
+// MakeCodeListEditionVertex makes a code list vertex for an edition, with the provided additional properties
+func MakeCodeListEditionVertex(listID, edition string, properties map[string]interface{}) graphson.Vertex {
+	vertex := makeVertex("_code_list")
+	setVertexStringProperty(&vertex, "listID", listID)
+	setVertexStringProperty(&vertex, "edition", edition)
+	for key, value := range properties {
+		switch value.(type) {
+		case bool:
+			setVertexTypedProperty("bool", &vertex, key, value)
+		default:
+			setVertexStringProperty(&vertex, key, value)
+		}
+	}
+	return vertex
+}
+
 /*
 makeVertex makes a graphson.Vertex of a given type (e.g. "_code_list").
 */
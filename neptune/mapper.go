@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neptune/query"
 	"github.com/ONSdigital/graphson"
@@ -15,7 +16,7 @@ import (
 	"github.com/pkg/errors"
 )
 
-func (n *NeptuneDB) buildHierarchyNode(v graphson.Vertex, instanceID, dimension string, wantBreadcrumbs bool) (res *models.HierarchyResponse, err error) {
+func (n *NeptuneDB) buildHierarchyNode(v graphson.Vertex, instanceID, dimension string, wantBreadcrumbs bool, options *driver.ReadOptions) (res *models.HierarchyResponse, err error) {
 	ctx := context.Background()
 	logData := log.Data{"fn": "buildHierarchyNode"}
 
@@ -28,7 +29,7 @@ func (n *NeptuneDB) buildHierarchyNode(v graphson.Vertex, instanceID, dimension
 		return
 	}
 
-	if res.Label, err = v.GetProperty("label"); err != nil {
+	if res.Label, err = getLabelProperty(v, options.Language); err != nil {
 		log.Error(ctx, "bad label", err, logData)
 		return
 	}
@@ -44,22 +45,20 @@ func (n *NeptuneDB) buildHierarchyNode(v graphson.Vertex, instanceID, dimension
 		log.Error(ctx, "bad order", err, logData)
 		return
 	}
+	if res.NoOfOptions, err = getOptionalPropertyInt64(v, "numberOfOptions"); err != nil {
+		log.Error(ctx, "bad numberOfOptions", err, logData)
+		return
+	}
+	if res.NoOfObservations, err = getOptionalPropertyInt64(v, "numberOfObservations"); err != nil {
+		log.Error(ctx, "bad numberOfObservations", err, logData)
+		return
+	}
 	// Fetch new data from the database concerned with the node's children.
 	if res.NoOfChildren > 0 && instanceID != "" {
 
-		// Check if order is defined
-		var orderCount int64
-		gremStmt := fmt.Sprintf(query.CountChildrenWithOrder, instanceID, dimension, res.ID)
-		orderCount, err = n.getNumber(gremStmt)
-		if err != nil {
-			return nil, errors.Wrapf(err, "Gremlin query failed: %q", gremStmt)
-		}
-
-		// query depending on the presence of order property in child nodes
-		if orderCount > 0 {
-			gremStmt = fmt.Sprintf(query.GetChildrenWithOrder, instanceID, dimension, res.ID)
-		} else {
-			gremStmt = fmt.Sprintf(query.GetChildrenAlphabetically, instanceID, dimension, res.ID)
+		var gremStmt string
+		if gremStmt, err = n.childrenQuery(instanceID, dimension, res.ID, options); err != nil {
+			return nil, err
 		}
 		logData["statement"] = gremStmt
 
@@ -68,7 +67,7 @@ func (n *NeptuneDB) buildHierarchyNode(v graphson.Vertex, instanceID, dimension
 			log.Error(ctx, "get", err, logData)
 			return
 		}
-		if int64(len(childVertices)) != res.NoOfChildren {
+		if !options.IsPaginated() && int64(len(childVertices)) != res.NoOfChildren {
 			logData["num_children_prop"] = res.NoOfChildren
 			logData["num_children_get"] = len(childVertices)
 			logData["node_id"] = res.ID
@@ -76,7 +75,7 @@ func (n *NeptuneDB) buildHierarchyNode(v graphson.Vertex, instanceID, dimension
 		}
 		var childElement *models.HierarchyElement
 		for _, child := range childVertices {
-			if childElement, err = convertVertexToElement(child); err != nil {
+			if childElement, err = convertVertexToElement(child, options.Language); err != nil {
 				log.Error(ctx, "converting child", err, logData)
 				return
 			}
@@ -85,7 +84,7 @@ func (n *NeptuneDB) buildHierarchyNode(v graphson.Vertex, instanceID, dimension
 	}
 	// Fetch new data from the database concerned with the node's breadcrumbs.
 	if wantBreadcrumbs {
-		res.Breadcrumbs, err = n.buildBreadcrumbs(instanceID, dimension, res.ID)
+		res.Breadcrumbs, err = n.buildBreadcrumbs(instanceID, dimension, res.ID, options.Language)
 		if err != nil {
 			log.Error(ctx, "building breadcrumbs", err, logData)
 		}
@@ -93,13 +92,55 @@ func (n *NeptuneDB) buildHierarchyNode(v graphson.Vertex, instanceID, dimension
 	return
 }
 
+/*
+childrenQuery returns the query to get the children of a hierarchy node, sorted and paginated according
+to the provided options. Unless a sort is requested, children are sorted by their order property if
+any of them has one, or alphabetically otherwise. Children are sorted alphabetically by their label in
+the requested language, falling back to their default label.
+*/
+func (n *NeptuneDB) childrenQuery(instanceID, dimension, code string, options *driver.ReadOptions) (string, error) {
+	var gremStmt string
+	switch options.Sort {
+	case driver.SortByOrder:
+		gremStmt = fmt.Sprintf(query.GetChildrenWithOrder, instanceID, dimension, code)
+	case driver.SortByLabel:
+		gremStmt = fmt.Sprintf(query.GetChildrenAlphabetically, instanceID, dimension, code, labelPart(options))
+	case driver.SortByCode:
+		gremStmt = fmt.Sprintf(query.GetChildrenByCode, instanceID, dimension, code)
+	default:
+		// Check if order is defined
+		countStmt := fmt.Sprintf(query.CountChildrenWithOrder, instanceID, dimension, code)
+		orderCount, err := n.getNumber(countStmt)
+		if err != nil {
+			return "", errors.Wrapf(err, "Gremlin query failed: %q", countStmt)
+		}
+
+		// query depending on the presence of order property in child nodes
+		if orderCount > 0 {
+			gremStmt = fmt.Sprintf(query.GetChildrenWithOrder, instanceID, dimension, code)
+		} else {
+			gremStmt = fmt.Sprintf(query.GetChildrenAlphabetically, instanceID, dimension, code, labelPart(options))
+		}
+	}
+
+	if options.IsPaginated() {
+		// the upper bound of range is exclusive, -1 meaning all remaining children
+		high := -1
+		if options.Limit > 0 {
+			high = options.Offset + options.Limit
+		}
+		gremStmt += fmt.Sprintf(query.ChildrenRangePart, options.Offset, high)
+	}
+	return gremStmt, nil
+}
+
 /*
 buildBreadcrumbs launches a new query to the database, to trace the (recursive)
 parentage of a hierarchy node. It converts the returned chain of parent
 graphson vertices into a chain of models.HierarchyElement, and returns this list of
 elements.
 */
-func (n *NeptuneDB) buildBreadcrumbs(instanceID, dimension, code string) ([]*models.HierarchyElement, error) {
+func (n *NeptuneDB) buildBreadcrumbs(instanceID, dimension, code, language string) ([]*models.HierarchyElement, error) {
 	ctx := context.Background()
 	logData := log.Data{"fn": "buildBreadcrumbs"}
 	gremStmt := fmt.Sprintf(query.GetAncestry, instanceID, dimension, code)
@@ -111,7 +152,7 @@ func (n *NeptuneDB) buildBreadcrumbs(instanceID, dimension, code string) ([]*mod
 	}
 	elements := []*models.HierarchyElement{}
 	for _, ancestor := range ancestorVertices {
-		element, err := convertVertexToElement(ancestor)
+		element, err := convertVertexToElement(ancestor, language)
 		if err != nil {
 			log.Error(ctx, "convertVertexToElement", err, logData)
 			return nil, err
@@ -121,7 +162,7 @@ func (n *NeptuneDB) buildBreadcrumbs(instanceID, dimension, code string) ([]*mod
 	return elements, nil
 }
 
-func convertVertexToElement(v graphson.Vertex) (res *models.HierarchyElement, err error) {
+func convertVertexToElement(v graphson.Vertex, language string) (res *models.HierarchyElement, err error) {
 	ctx := context.Background()
 	logData := log.Data{"fn": "convertVertexToElement"}
 	res = &models.HierarchyElement{}
@@ -133,7 +174,7 @@ func convertVertexToElement(v graphson.Vertex) (res *models.HierarchyElement, er
 		return
 	}
 
-	if res.Label, err = v.GetProperty("label"); err != nil {
+	if res.Label, err = getLabelProperty(v, language); err != nil {
 		log.Error(ctx, "bad label", err, logData)
 		return
 	}
@@ -149,6 +190,14 @@ func convertVertexToElement(v graphson.Vertex) (res *models.HierarchyElement, er
 		log.Error(ctx, "bad order", err, logData)
 		return
 	}
+	if res.NoOfOptions, err = getOptionalPropertyInt64(v, "numberOfOptions"); err != nil {
+		log.Error(ctx, "bad numberOfOptions", err, logData)
+		return
+	}
+	if res.NoOfObservations, err = getOptionalPropertyInt64(v, "numberOfObservations"); err != nil {
+		log.Error(ctx, "bad numberOfObservations", err, logData)
+		return
+	}
 	return
 }
 
@@ -162,3 +211,64 @@ func getOptionalPropertyInt64(v graphson.Vertex, key string) (*int64, error) {
 	}
 	return &val, err
 }
+
+// getLabelProperty returns the label of a vertex in the provided language, falling back to the label
+// in the default language if it is not available
+func getLabelProperty(v graphson.Vertex, language string) (string, error) {
+	if key := driver.LabelProperty(language); key != driver.LabelProperty(driver.DefaultLanguage) {
+		label, err := getOptionalProperty(v, key)
+		if err != nil || len(label) > 0 {
+			return label, err
+		}
+	}
+	return v.GetProperty("label")
+}
+
+// getOptionalProperty returns the single string value for a given property `key`
+// will return an empty string if the property is not found
+func getOptionalProperty(v graphson.Vertex, key string) (string, error) {
+	val, err := v.GetProperty(key)
+	if err == graphson.ErrorPropertyNotFound {
+		return "", nil
+	}
+	return val, err
+}
+
+// getVertexProperties returns all the properties of a vertex. Typed numeric values are returned as int64
+// if they are integers, or float64 otherwise, and multi properties are returned as a slice of their values.
+func getVertexProperties(v graphson.Vertex) map[string]interface{} {
+	properties := make(map[string]interface{}, len(v.Value.Properties))
+	for key, props := range v.Value.Properties {
+		values := make([]interface{}, 0, len(props))
+		for _, prop := range props {
+			values = append(values, getTypedValue(prop.Value.Value))
+		}
+		if len(values) == 1 {
+			properties[key] = values[0]
+			continue
+		}
+		properties[key] = values
+	}
+	return properties
+}
+
+// getTypedValue returns the value of a graphson typed value, e.g. {"@type": "g:Int32", "@value": 1},
+// or the provided value if it is not typed
+func getTypedValue(value interface{}) interface{} {
+	typed, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	number, ok := typed["@value"].(float64)
+	if !ok {
+		return value
+	}
+
+	switch typed["@type"] {
+	case "g:Int32", "g:Int64":
+		return int64(number)
+	default:
+		return number
+	}
+}
@@ -3,12 +3,15 @@ package neptune
 import (
 	"testing"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
 	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
 	"github.com/ONSdigital/graphson"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
+var defaultOptions, _ = driver.NewReadOptions()
+
 func Test_buildHierarchyNode(t *testing.T) {
 
 	Convey("Given an example hierarchy node vertex returned from neptune", t, func() {
@@ -34,7 +37,7 @@ func Test_buildHierarchyNode(t *testing.T) {
 
 		Convey("Where the hierarchy node does not have an order property", func() {
 			Convey("When buildHierarchyNode is called", func() {
-				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs)
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, defaultOptions)
 				Convey("Then the expected values are mapped onto the returned hierarchy response", func() {
 					So(err, ShouldBeNil)
 					So(*hierarchyNode, ShouldResemble, models.HierarchyResponse{
@@ -47,6 +50,49 @@ func Test_buildHierarchyNode(t *testing.T) {
 			})
 		})
 
+		Convey("Where the hierarchy node has rollup counts", func() {
+			internal.SetRollupCounts(&vertex, 2, 1234)
+
+			Convey("When buildHierarchyNode is called", func() {
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, defaultOptions)
+				Convey("Then the rollup counts are mapped onto the returned hierarchy response", func() {
+					So(err, ShouldBeNil)
+					So(*hierarchyNode.NoOfOptions, ShouldEqual, 2)
+					So(*hierarchyNode.NoOfObservations, ShouldEqual, 1234)
+				})
+			})
+		})
+
+		Convey("Where the hierarchy node has a label in the requested language", func() {
+			internal.SetLocalisedLabel(&vertex, "cy", "label-cy")
+
+			Convey("When buildHierarchyNode is called with that language", func() {
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, &driver.ReadOptions{Language: "cy"})
+				Convey("Then the label in the requested language is returned", func() {
+					So(err, ShouldBeNil)
+					So(hierarchyNode.Label, ShouldEqual, "label-cy")
+				})
+			})
+
+			Convey("When buildHierarchyNode is called with the default language", func() {
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, &driver.ReadOptions{Language: "en"})
+				Convey("Then the default label is returned", func() {
+					So(err, ShouldBeNil)
+					So(hierarchyNode.Label, ShouldEqual, expectedLabel)
+				})
+			})
+		})
+
+		Convey("Where the hierarchy node does not have a label in the requested language", func() {
+			Convey("When buildHierarchyNode is called with that language", func() {
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, &driver.ReadOptions{Language: "cy"})
+				Convey("Then the label falls back to the default label", func() {
+					So(err, ShouldBeNil)
+					So(hierarchyNode.Label, ShouldEqual, expectedLabel)
+				})
+			})
+		})
+
 		Convey("Where the hierarchy node has an order property", func() {
 			var (
 				order         float64 = 123
@@ -58,7 +104,7 @@ func Test_buildHierarchyNode(t *testing.T) {
 			}
 
 			Convey("When buildHierarchyNode is called", func() {
-				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs)
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, defaultOptions)
 				Convey("Then the expected values are mapped onto the returned hierarchy response", func() {
 					So(err, ShouldBeNil)
 					So(*hierarchyNode, ShouldResemble, models.HierarchyResponse{
@@ -118,7 +164,7 @@ func Test_buildHierarchyNode(t *testing.T) {
 
 			Convey("When buildHierarchyNode is called", func() {
 
-				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs)
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, defaultOptions)
 
 				Convey("Then the returned error is nil", func() {
 					So(err, ShouldBeNil)
@@ -147,6 +193,71 @@ func Test_buildHierarchyNode(t *testing.T) {
 			})
 		})
 
+		Convey("Where a page of children sorted by code is requested", func() {
+			db := mockDB(poolMock)
+			options, err := driver.NewReadOptions(driver.WithOffset(10), driver.WithLimit(5), driver.WithSort(driver.SortByCode))
+			So(err, ShouldBeNil)
+
+			Convey("When buildHierarchyNode is called", func() {
+
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, options)
+
+				Convey("Then the returned error is nil", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("Then the presence of an 'order' property is not checked", func() {
+					So(poolMock.GetCountCalls(), ShouldHaveLength, 0)
+				})
+
+				Convey("Then the requested page of children is queried, sorted by code", func() {
+					So(poolMock.GetCalls(), ShouldHaveLength, 2)
+					So(poolMock.GetCalls()[0].Query, ShouldEqual,
+						"g.V().hasLabel('_hierarchy_node_instance-id_dimension').has('code','code').in('hasParent').order().by('code').range(10,15)")
+				})
+
+				Convey("Then the number of children still reports the total", func() {
+					So(hierarchyNode.NoOfChildren, ShouldEqual, 1)
+					So(hierarchyNode.Children, ShouldHaveLength, 1)
+				})
+			})
+		})
+
+		Convey("Where children sorted by their label in another language are requested", func() {
+			db := mockDB(poolMock)
+			options, err := driver.NewReadOptions(driver.WithLanguage("cy"), driver.WithSort(driver.SortByLabel))
+			So(err, ShouldBeNil)
+
+			Convey("When buildHierarchyNode is called", func() {
+				_, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, options)
+
+				Convey("Then the children are queried sorted by their label in that language, falling back to their default label", func() {
+					So(err, ShouldBeNil)
+					So(poolMock.GetCountCalls(), ShouldHaveLength, 0)
+					So(poolMock.GetCalls()[0].Query, ShouldEqual,
+						"g.V().hasLabel('_hierarchy_node_instance-id_dimension').has('code','code').in('hasParent').order().by(coalesce(values('label_cy'),values('label')))")
+				})
+			})
+		})
+
+		Convey("Where children are requested from an offset without a limit", func() {
+			poolMock.GetCountFunc = func(q string, bindings map[string]string, rebindings map[string]string) (int64, error) {
+				return 0, nil
+			}
+			db := mockDB(poolMock)
+			options, err := driver.NewReadOptions(driver.WithOffset(10))
+			So(err, ShouldBeNil)
+
+			Convey("When buildHierarchyNode is called", func() {
+				_, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, options)
+
+				Convey("Then all the remaining children are queried", func() {
+					So(err, ShouldBeNil)
+					So(poolMock.GetCalls()[0].Query, ShouldEqual, expectedGetAlphabeticallyQuery+".range(10,-1)")
+				})
+			})
+		})
+
 		Convey("Where the hierarchy node does not contain an 'order' property", func() {
 			poolMock.GetCountFunc = func(q string, bindings map[string]string, rebindings map[string]string) (int64, error) {
 				return 0, nil // '0' selects GetChildrenAlphabetically()
@@ -155,7 +266,7 @@ func Test_buildHierarchyNode(t *testing.T) {
 
 			Convey("When buildHierarchyNode is called", func() {
 
-				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs)
+				hierarchyNode, err := db.buildHierarchyNode(vertex, instanceID, dimension, wantBreadcrumbs, defaultOptions)
 
 				Convey("Then the returned error is nil", func() {
 					So(err, ShouldBeNil)
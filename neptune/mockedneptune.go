@@ -14,3 +14,11 @@ func mockDB(poolMock *internal.NeptunePoolMock) *NeptuneDB {
 	db := &NeptuneDB{driver, 5, time.Millisecond, 30, 25000, 150, 150}
 	return db
 }
+
+// mockDBWithReader provides a NeptuneDB like mockDB, sending read-only
+// queries to a separate mocked reader pool.
+func mockDBWithReader(poolMock, readerMock *internal.NeptunePoolMock) *NeptuneDB {
+	db := mockDB(poolMock)
+	db.ReaderPool = readerMock
+	return db
+}
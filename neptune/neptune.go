@@ -26,32 +26,35 @@ type NeptuneDB struct {
 	maxWorkers      int
 }
 
-func New(dbAddr string, size, timeout, retries, batchSizeReader, batchSizeWriter, maxWorkers int, tlsSkip bool, retryTime time.Duration, errs chan error) (n *NeptuneDB, err error) {
-	// set defaults if not provided
-	if size == 0 {
-		size = 30
+// New sets reasonable neptune specific defaults for the options not provided and instantiates a driver
+func New(dbAddr string, errs chan error, opts ...Option) (n *NeptuneDB, err error) {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
 	}
-	if timeout == 0 {
-		timeout = 30
+
+	// set defaults if not provided
+	if o.Timeout == 0 {
+		o.Timeout = 30
 	}
-	if retries == 0 {
-		retries = 5
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 5
 	}
-	if retryTime == 0 {
-		retryTime = 20 * time.Millisecond
+	if o.RetryTime == 0 {
+		o.RetryTime = 20 * time.Millisecond
 	}
-	if batchSizeReader == 0 {
-		batchSizeReader = 25000
+	if o.BatchSizeReader == 0 {
+		o.BatchSizeReader = 25000
 	}
-	if batchSizeWriter == 0 {
-		batchSizeWriter = 150
+	if o.BatchSizeWriter == 0 {
+		o.BatchSizeWriter = 150
 	}
-	if maxWorkers == 0 {
-		maxWorkers = 150
+	if o.MaxWorkers == 0 {
+		o.MaxWorkers = 150
 	}
 
 	var d *neptune.NeptuneDriver
-	if d, err = neptune.New(context.Background(), dbAddr, errs, tlsSkip); err != nil {
+	if d, err = neptune.New(context.Background(), dbAddr, o.ReaderAddress, errs, o.TLSSkipVerify, o.PoolSize, o.PoolMaxLifetime, o.PoolIdleTimeout); err != nil {
 		return
 	}
 
@@ -60,22 +63,42 @@ func New(dbAddr string, size, timeout, retries, batchSizeReader, batchSizeWriter
 
 	n = &NeptuneDB{
 		*d,
-		1 + retries,
-		retryTime,
-		timeout,
-		batchSizeReader,
-		batchSizeWriter,
-		maxWorkers,
+		1 + o.MaxRetries,
+		o.RetryTime,
+		o.Timeout,
+		o.BatchSizeReader,
+		o.BatchSizeWriter,
+		o.MaxWorkers,
 	}
 	return
 }
 
+// Capabilities returns the optional features supported by neptune. Gremlin statements
+// are each executed in their own transaction.
+func (n *NeptuneDB) Capabilities() driver.Capabilities {
+	return driver.Capabilities{
+		driver.CapabilityOrderedCodes,
+		driver.CapabilityCodeCounts,
+		driver.CapabilitySparseHierarchy,
+		driver.CapabilityStreaming,
+		driver.CapabilityBulkLoad,
+	}
+}
+
+// writer returns a copy of n sending read-only queries to the writer endpoint, for reads that must
+// see the changes just made, which may not have been replicated to the reader endpoint yet
+func (n *NeptuneDB) writer() *NeptuneDB {
+	w := *n
+	w.ReaderPool = nil
+	return &w
+}
+
 func (n *NeptuneDB) getVertices(gremStmt string) (vertices []graphson.Vertex, err error) {
 	ctx := context.Background()
 	logData := log.Data{"fn": "getVertices", "statement": statementSummary(gremStmt), "attempt": 1}
 
 	doer := func() (interface{}, error) {
-		return n.Pool.Get(gremStmt, nil, nil)
+		return n.Reader().Get(gremStmt, nil, nil)
 	}
 
 	res, err := n.attemptNeptuneRequest(ctx, doer, logData)
@@ -96,7 +119,7 @@ func (n *NeptuneDB) getStringList(gremStmt string) (strings []string, err error)
 	logData := log.Data{"fn": "getStringList", "statement": statementSummary(gremStmt), "attempt": 1}
 
 	doer := func() (interface{}, error) {
-		return n.Pool.GetStringList(gremStmt, nil, nil)
+		return n.Reader().GetStringList(gremStmt, nil, nil)
 	}
 
 	res, err := n.attemptNeptuneRequest(ctx, doer, nil)
@@ -1,9 +1,14 @@
 package neptune
 
 import (
+	"context"
+	"testing"
+
 	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
-	"testing"
+	"github.com/ONSdigital/dp-graph/v2/observation"
+	"github.com/ONSdigital/graphson"
+	gremgo "github.com/ONSdigital/gremgo-neptune"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -24,3 +29,106 @@ func TestNeptuneDB_getVertex(t *testing.T) {
 		})
 	})
 }
+
+func TestNeptuneDB_ReaderEndpoint(t *testing.T) {
+
+	Convey("Given a mocked neptune DB with a reader endpoint", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetFunc:           internal.ReturnMalformedNilInterfaceRequestErr,
+			GetStringListFunc: internal.ReturnEmptyCodesList,
+			GetCountFunc:      internal.ReturnZero,
+		}
+		readerMock := &internal.NeptunePoolMock{
+			GetFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{{}}, nil
+			},
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{"1", "2"}, nil
+			},
+			OpenStreamCursorFunc: func(ctx context.Context, query string, bindings map[string]string, rebindings map[string]string) (*gremgo.Stream, error) {
+				return &gremgo.Stream{}, nil
+			},
+		}
+		db := mockDBWithReader(poolMock, readerMock)
+
+		Convey("When read-only queries are made", func() {
+			_, err := db.getVertex("gremlin statement")
+			So(err, ShouldBeNil)
+			list, err := db.getStringList("gremlin statement")
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, []string{"1", "2"})
+
+			Convey("Then they are sent to the reader endpoint", func() {
+				So(readerMock.GetCalls(), ShouldHaveLength, 1)
+				So(readerMock.GetStringListCalls(), ShouldHaveLength, 1)
+				So(poolMock.GetCalls(), ShouldHaveLength, 0)
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When observations are streamed", func() {
+			filter := &observation.DimensionFilters{
+				Dimensions: []*observation.Dimension{{Name: "age", Options: []string{"29"}}},
+			}
+			_, err := db.StreamCSVRows(context.Background(), "888", "", filter, nil)
+			So(err, ShouldBeNil)
+
+			Convey("Then the streams are opened on the reader endpoint", func() {
+				So(readerMock.OpenStreamCursorCalls(), ShouldHaveLength, 2)
+				So(poolMock.OpenStreamCursorCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When nodes just written are read while building a hierarchy", func() {
+			ids, err := db.GetHierarchyNodeIDs(context.Background(), 1, "instanceID", "dimensionName")
+			So(err, ShouldBeNil)
+			So(ids, ShouldBeEmpty)
+
+			Convey("Then they are read from the writer endpoint", func() {
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 1)
+				So(readerMock.GetStringListCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When a hierarchy is checked to exist while building it", func() {
+			poolMock.GetFunc = func(query string, bindings map[string]string, rebindings map[string]string) ([]graphson.Vertex, error) {
+				return []graphson.Vertex{}, nil
+			}
+			exists, err := db.HierarchyExists(context.Background(), "instanceID", "dimensionName")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+
+			Convey("Then it is read from the writer endpoint", func() {
+				So(poolMock.GetCalls(), ShouldHaveLength, 1)
+				So(readerMock.GetCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When the pools are closed", func() {
+			poolMock.CloseFunc = func() {}
+			readerMock.CloseFunc = func() {}
+			err := db.Close(context.Background())
+
+			Convey("Then both pools are closed", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.CloseCalls(), ShouldHaveLength, 1)
+				So(readerMock.CloseCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+
+	Convey("Given a mocked neptune DB without a reader endpoint", t, func() {
+		poolMock := &internal.NeptunePoolMock{GetStringListFunc: internal.ReturnEmptyCodesList}
+		db := mockDB(poolMock)
+
+		Convey("When a read-only query is made", func() {
+			_, err := db.getStringList("gremlin statement")
+			So(err, ShouldBeNil)
+
+			Convey("Then it is sent to the writer endpoint", func() {
+				So(db.Reader(), ShouldEqual, poolMock)
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 1)
+			})
+		})
+	})
+}
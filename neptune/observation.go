@@ -30,13 +30,22 @@ var totalTime time.Time
 // StreamCSVRows returns a reader allowing individual CSV rows to be read.
 // Rows returned can be limited, to stop this pass in nil. If filter.DimensionFilters
 // is nil, empty or contains only empty values then a StreamRowReader for the entire dataset will be returned.
+// driver.ErrInstanceUnpublished is returned if the instance has been unpublished.
 func (n *NeptuneDB) StreamCSVRows(ctx context.Context, instanceID, filterID string, filter *observation.DimensionFilters, limit *int) (observation.StreamRowReader, error) {
 	if filter == nil {
 		return nil, ErrInvalidFilter
 	}
 
+	unpublished, err := n.getNumber(fmt.Sprintf(query.CountUnpublishedInstance, instanceID))
+	if err != nil {
+		return nil, err
+	}
+	if unpublished > 0 {
+		return nil, driver.ErrInstanceUnpublished
+	}
+
 	q := fmt.Sprintf(query.GetInstanceHeaderPart, instanceID)
-	headerReader, err := n.Pool.OpenStreamCursor(ctx, q, nil, nil)
+	headerReader, err := n.OpenStream(ctx, n.Reader(), q)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +57,7 @@ func (n *NeptuneDB) StreamCSVRows(ctx context.Context, instanceID, filterID stri
 		q += fmt.Sprintf(query.LimitPart, *limit)
 	}
 
-	observationReader, err := n.Pool.OpenStreamCursor(ctx, q, nil, nil)
+	observationReader, err := n.OpenStream(ctx, n.Reader(), q)
 	if err != nil {
 		return nil, err
 	}
@@ -201,7 +210,7 @@ func (n *NeptuneDB) removeExistingObservations(obsIDs []string) error {
 
 	// query for existing observations to drop - most likely there will be none
 	queryExistingObservations := fmt.Sprintf(query.GetObservations, `'`+strings.Join(obsIDs, `','`)+`'`)
-	existingObsIDs, err := n.getStringList(queryExistingObservations)
+	existingObsIDs, err := n.writer().getStringList(queryExistingObservations)
 	if err != nil {
 		return err
 	}
@@ -211,7 +220,7 @@ func (n *NeptuneDB) removeExistingObservations(obsIDs []string) error {
 		existingObsIDsJoined := `'` + strings.Join(existingObsIDs, `','`) + `'`
 
 		queryExistingObservationEdges := fmt.Sprintf(query.GetObservationsEdges, existingObsIDsJoined)
-		existingObsEdgeIDs, err := n.getStringList(queryExistingObservationEdges)
+		existingObsEdgeIDs, err := n.writer().getStringList(queryExistingObservationEdges)
 		if err != nil {
 			return err
 		}
@@ -234,3 +243,44 @@ func (n *NeptuneDB) removeExistingObservations(obsIDs []string) error {
 func escapeSingleQuotes(input string) string {
 	return strings.Replace(input, "'", "\\'", -1)
 }
+
+// RecordImportCheckpoint adds a checkpoint for an inserted batch to the instance node. The
+// checkpoints are stored with set cardinality, so recording the same checkpoint again has no effect.
+func (n *NeptuneDB) RecordImportCheckpoint(ctx context.Context, instanceID string, checkpoint *models.ImportCheckpoint) error {
+	if len(instanceID) == 0 {
+		return errors.New("instance id is required but was empty")
+	}
+	if err := checkpoint.Validate(); err != nil {
+		return err
+	}
+
+	data := log.Data{
+		"instance_id": instanceID,
+		"checkpoint":  checkpoint.String(),
+	}
+
+	q := fmt.Sprintf(query.AddImportCheckpoint, instanceID, checkpoint.String())
+	if _, err := n.exec(q); err != nil {
+		log.Error(ctx, "neptune exec failed on RecordImportCheckpoint", err, data)
+		return err
+	}
+	return nil
+}
+
+// GetImportProgress returns the checkpoints recorded for the instance
+func (n *NeptuneDB) GetImportProgress(ctx context.Context, instanceID string) (*models.ImportProgress, error) {
+	exists, err := n.InstanceExists(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, driver.ErrNotFound
+	}
+
+	checkpoints, err := n.writer().getStringList(fmt.Sprintf(query.GetImportCheckpoints, instanceID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get import checkpoints")
+	}
+
+	return models.NewImportProgress(instanceID, checkpoints)
+}
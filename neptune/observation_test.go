@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
 	"github.com/ONSdigital/dp-graph/v2/models"
 
 	"github.com/ONSdigital/dp-graph/v2/neptune/internal"
@@ -92,6 +93,7 @@ func Test_StreamCSVRows(t *testing.T) {
 
 	Convey("Given a store with a mock DB connection and a valid filter job", t, func() {
 		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc: internal.ReturnZero,
 			OpenStreamCursorFunc: func(ctx context.Context, query string, bindings map[string]string, rebindings map[string]string) (*gremgo.Stream, error) {
 				return &gremgo.Stream{}, nil
 			},
@@ -115,6 +117,27 @@ func Test_StreamCSVRows(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a store with a mock DB connection and an instance that has been unpublished", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc: internal.ReturnOne,
+			OpenStreamCursorFunc: func(ctx context.Context, query string, bindings map[string]string, rebindings map[string]string) (*gremgo.Stream, error) {
+				return &gremgo.Stream{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When StreamCSVRows is called", func() {
+			stream, err := db.StreamCSVRows(ctx, "888", "", &observation.DimensionFilters{}, nil)
+
+			Convey("Then ErrInstanceUnpublished is returned and no stream is opened", func() {
+				So(stream, ShouldBeNil)
+				So(err, ShouldEqual, driver.ErrInstanceUnpublished)
+				So(poolMock.GetCountCalls()[0].Q, ShouldEqual, "g.V().hasId('_888_Instance').has('publish_state','unpublished').count()")
+				So(poolMock.OpenStreamCursorCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
 }
 
 func Test_escapeSingleQuotes(t *testing.T) {
@@ -320,3 +343,83 @@ func Test_InsertObservationBatch(t *testing.T) {
 		})
 	})
 }
+
+func TestNeptuneDB_RecordImportCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	checkpoint := &models.ImportCheckpoint{MinRowIndex: 1, MaxRowIndex: 100, Count: 100, Checksum: 1234}
+
+	Convey("Given a valid checkpoint", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			ExecuteFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]gremgo.Response, error) {
+				return []gremgo.Response{}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When RecordImportCheckpoint is called", func() {
+			err := db.RecordImportCheckpoint(ctx, "instanceID", checkpoint)
+
+			Convey("Then the checkpoint is added to the instance node", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 1)
+				So(poolMock.ExecuteCalls()[0].Query, ShouldEqual, "g.V().hasId('_instanceID_Instance').property(set,'import_checkpoints','1:100:100:1234')")
+			})
+		})
+	})
+
+	Convey("Given an invalid checkpoint", t, func() {
+		poolMock := &internal.NeptunePoolMock{}
+		db := mockDB(poolMock)
+
+		Convey("When RecordImportCheckpoint is called", func() {
+			err := db.RecordImportCheckpoint(ctx, "instanceID", &models.ImportCheckpoint{MinRowIndex: 1, MaxRowIndex: 2, Count: 0})
+
+			Convey("Then an error is returned and the graph DB is not called", func() {
+				So(err.Error(), ShouldEqual, "import checkpoint count does not fit the row index range")
+				So(poolMock.ExecuteCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestNeptuneDB_GetImportProgress(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an instance with recorded checkpoints", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc: internal.ReturnOne,
+			GetStringListFunc: func(query string, bindings map[string]string, rebindings map[string]string) ([]string, error) {
+				return []string{"101:200:100:2", "1:100:100:1"}, nil
+			},
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetImportProgress is called", func() {
+			progress, err := db.GetImportProgress(ctx, "instanceID")
+
+			Convey("Then the checkpoints are read from the instance node", func() {
+				So(err, ShouldBeNil)
+				So(poolMock.GetStringListCalls()[0].Query, ShouldEqual, "g.V().hasId('_instanceID_Instance').values('import_checkpoints')")
+				So(progress.Checkpoints, ShouldHaveLength, 2)
+				So(progress.ResumeFrom(1), ShouldEqual, 201)
+			})
+		})
+	})
+
+	Convey("Given an instance that does not exist", t, func() {
+		poolMock := &internal.NeptunePoolMock{
+			GetCountFunc: internal.ReturnZero,
+		}
+		db := mockDB(poolMock)
+
+		Convey("When GetImportProgress is called", func() {
+			progress, err := db.GetImportProgress(ctx, "instanceID")
+
+			Convey("Then a not found error is returned", func() {
+				So(progress, ShouldBeNil)
+				So(err, ShouldEqual, driver.ErrNotFound)
+				So(poolMock.GetStringListCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
@@ -0,0 +1,109 @@
+package neptune
+
+import "time"
+
+// Options holds the options that can be provided to New. Zero values are replaced by defaults.
+type Options struct {
+	// ReaderAddress is the address of a reader endpoint used for read-only queries, the writer being used if empty
+	ReaderAddress string
+	// PoolSize is the maximum number of connections of each pool, unlimited by default
+	PoolSize int
+	// PoolMaxLifetime is the maximum time a connection is kept open, no limit by default
+	PoolMaxLifetime time.Duration
+	// PoolIdleTimeout is the time after which the connections of a pool are closed if none have been used, never by default
+	PoolIdleTimeout time.Duration
+	// Timeout is the maximum number of seconds allowed for a query, 30 by default
+	Timeout int
+	// MaxRetries is the number of times a failed query is retried, 5 by default
+	MaxRetries int
+	// RetryTime is the initial sleep time between attempts of a query, 20ms by default
+	RetryTime time.Duration
+	// BatchSizeReader is the batch size for queries to a reader endpoint, 25000 by default
+	BatchSizeReader int
+	// BatchSizeWriter is the batch size for queries to a writer endpoint, 150 by default
+	BatchSizeWriter int
+	// MaxWorkers is the maximum number of workers making batched queries concurrently, 150 by default
+	MaxWorkers int
+	// TLSSkipVerify skips TLS certificate verification, which should only be done when run locally
+	TLSSkipVerify bool
+}
+
+// Option sets an option of New
+type Option func(*Options)
+
+// WithReaderAddress sends read-only queries to the reader endpoint at the provided address
+func WithReaderAddress(addr string) Option {
+	return func(o *Options) {
+		o.ReaderAddress = addr
+	}
+}
+
+// WithPoolSize sets the maximum number of connections of each pool
+func WithPoolSize(size int) Option {
+	return func(o *Options) {
+		o.PoolSize = size
+	}
+}
+
+// WithPoolMaxLifetime sets the maximum time a connection is kept open
+func WithPoolMaxLifetime(maxLifetime time.Duration) Option {
+	return func(o *Options) {
+		o.PoolMaxLifetime = maxLifetime
+	}
+}
+
+// WithPoolIdleTimeout closes the connections of a pool once none have been used for the provided time
+func WithPoolIdleTimeout(idleTimeout time.Duration) Option {
+	return func(o *Options) {
+		o.PoolIdleTimeout = idleTimeout
+	}
+}
+
+// WithTimeout sets the maximum number of seconds allowed for a query
+func WithTimeout(timeout int) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithMaxRetries sets the number of times a failed query is retried
+func WithMaxRetries(retries int) Option {
+	return func(o *Options) {
+		o.MaxRetries = retries
+	}
+}
+
+// WithRetryTime sets the initial sleep time between attempts of a query
+func WithRetryTime(retryTime time.Duration) Option {
+	return func(o *Options) {
+		o.RetryTime = retryTime
+	}
+}
+
+// WithBatchSizeReader sets the batch size for queries to a reader endpoint
+func WithBatchSizeReader(size int) Option {
+	return func(o *Options) {
+		o.BatchSizeReader = size
+	}
+}
+
+// WithBatchSizeWriter sets the batch size for queries to a writer endpoint
+func WithBatchSizeWriter(size int) Option {
+	return func(o *Options) {
+		o.BatchSizeWriter = size
+	}
+}
+
+// WithMaxWorkers sets the maximum number of workers making batched queries concurrently
+func WithMaxWorkers(maxWorkers int) Option {
+	return func(o *Options) {
+		o.MaxWorkers = maxWorkers
+	}
+}
+
+// WithTLSSkipVerify sets whether TLS certificate verification is skipped
+func WithTLSSkipVerify(skip bool) Option {
+	return func(o *Options) {
+		o.TLSSkipVerify = skip
+	}
+}
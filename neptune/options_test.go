@@ -0,0 +1,102 @@
+package neptune
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNew(t *testing.T) {
+	Convey("When New is called without options", t, func() {
+		db, err := New("ws://localhost:8182/gremlin", nil)
+
+		Convey("Then the defaults are used", func() {
+			So(err, ShouldBeNil)
+			So(db.maxAttempts, ShouldEqual, 6)
+			So(db.retryTime, ShouldEqual, 20*time.Millisecond)
+			So(db.timeout, ShouldEqual, 30)
+			So(db.batchSizeReader, ShouldEqual, 25000)
+			So(db.batchSizeWriter, ShouldEqual, 150)
+			So(db.maxWorkers, ShouldEqual, 150)
+			So(db.ReaderPool, ShouldBeNil)
+			So(db.Stats().MaxOpenConnections, ShouldEqual, 0)
+		})
+
+		Convey("Then the capabilities of neptune are reported", func() {
+			So(db.Capabilities().Has(driver.CapabilityCodeCounts), ShouldBeTrue)
+			So(db.Capabilities().Has(driver.CapabilityTransactions), ShouldBeFalse)
+		})
+	})
+
+	Convey("When New is called with options", t, func() {
+		db, err := New("ws://localhost:8182/gremlin", nil,
+			WithReaderAddress("ws://localhost:8183/gremlin"),
+			WithPoolSize(10),
+			WithTimeout(15),
+			WithMaxRetries(2),
+			WithRetryTime(time.Second),
+			WithBatchSizeReader(100),
+			WithBatchSizeWriter(10),
+			WithMaxWorkers(5))
+
+		Convey("Then the options are used", func() {
+			So(err, ShouldBeNil)
+			So(db.maxAttempts, ShouldEqual, 3)
+			So(db.retryTime, ShouldEqual, time.Second)
+			So(db.timeout, ShouldEqual, 15)
+			So(db.batchSizeReader, ShouldEqual, 100)
+			So(db.batchSizeWriter, ShouldEqual, 10)
+			So(db.maxWorkers, ShouldEqual, 5)
+			So(db.ReaderPool, ShouldNotBeNil)
+			So(db.Stats().MaxOpenConnections, ShouldEqual, 20)
+		})
+	})
+}
+
+func TestRegister(t *testing.T) {
+	Convey("When the registered neptune driver is opened with neptune-specific settings", t, func() {
+		d, err := driver.Open("neptune", driver.Config{
+			Address:  "ws://localhost:8182/gremlin",
+			PoolSize: 8,
+			LoadSettings: func(settings interface{}) error {
+				*settings.(*Settings) = Settings{BatchSizeReader: 100, MaxWorkers: 5, TLSSkipVerify: true}
+				return nil
+			},
+		}, nil)
+
+		Convey("Then the pool size and the settings are used, with defaults for those not provided", func() {
+			So(err, ShouldBeNil)
+			db := d.(*NeptuneDB)
+			So(db.batchSizeReader, ShouldEqual, 100)
+			So(db.batchSizeWriter, ShouldEqual, 150)
+			So(db.maxWorkers, ShouldEqual, 5)
+			So(db.Stats().MaxOpenConnections, ShouldEqual, 8)
+		})
+	})
+
+	Convey("When the registered neptune driver is opened without settings", t, func() {
+		d, err := driver.Open("neptune", driver.Config{Address: "ws://localhost:8182/gremlin"}, nil)
+
+		Convey("Then the defaults are used", func() {
+			So(err, ShouldBeNil)
+			So(d.(*NeptuneDB).maxWorkers, ShouldEqual, 150)
+		})
+	})
+
+	Convey("When the registered neptune driver is opened with settings that cannot be loaded", t, func() {
+		d, err := driver.Open("neptune", driver.Config{
+			Address: "ws://localhost:8182/gremlin",
+			LoadSettings: func(settings interface{}) error {
+				return errors.New("NEPTUNE_MAX_WORKERS is not a number")
+			},
+		}, nil)
+
+		Convey("Then an error is returned", func() {
+			So(d, ShouldBeNil)
+			So(err.Error(), ShouldEqual, "invalid neptune settings: NEPTUNE_MAX_WORKERS is not a number")
+		})
+	})
+}
@@ -8,29 +8,63 @@ package query
 const (
 	// code lists
 	GetCodeLists          = `g.V().hasLabel('_code_list')`
-	GetCodeListsFiltered  = `g.V().hasLabel('_code_list').has('%s', true)`
 	GetCodeList           = `g.V().hasLabel('_code_list').has('listID', '%s')`
+	CodeListFlagPart      = `.has('%s', true)`
 	CodeListExists        = `g.V().hasLabel('_code_list').has('listID', '%s').count()`
 	CodeListEditionExists = `g.V().hasLabel('_code_list').has('listID', '%s').has('edition', '%s').count()`
 	CountCodes            = `g.V().has('_code_list','listID', '%s').has('edition', '%s')` +
 		`.in('usedBy').count()`
+	CountCodesByEdition = `g.V().has('_code_list','listID', '%s')` +
+		`.group().by('edition').by(__.in('usedBy').count())`
 	CountOrderedEdges      = `g.V().has('_code_list','listID', '%s').has('edition', '%s').inE('usedBy').has('order').count()`
 	GetCodesAlphabetically = `g.V().has('_code_list','listID', '%s').has('edition', '%s')` +
 		`.inE('usedBy').as('usedBy')` +
 		`.outV().order().by('value',asc).as('code')` +
-		`.select('usedBy', 'code').by('label').by('value')` +
+		`.select('usedBy', 'code').by(%s).by('value')` +
 		`.unfold().select(values)`
 	GetCodesWithOrder = `g.V().has('_code_list', 'listID', '%s').has('edition', '%s')` +
 		`.inE('usedBy').order().by('order',asc).as('usedBy')` +
 		`.outV().as('code')` +
-		`.select('usedBy', 'code').by('label').by('value')` +
+		`.select('usedBy', 'code').by(%s).by('value')` +
+		`.unfold().select(values)`
+	GetCodesByLabel = `g.V().has('_code_list', 'listID', '%s').has('edition', '%s')` +
+		`.inE('usedBy').order().by(%s,asc).as('usedBy')` +
+		`.outV().as('code')` +
+		`.select('usedBy', 'code').by(%s).by('value')` +
 		`.unfold().select(values)`
+	GetOrderedEdges = `g.V().has('_code_list','listID', '%s').has('edition', '%s')` +
+		`.inE('usedBy').has('order').as('usedBy')` +
+		`.outV().values('value').as('code').union(select('code', 'usedBy'))`
 	CodeExists = `g.V().hasLabel('_code_list')` +
 		`.has('listID', '%s').has('edition', '%s')` +
 		`.in('usedBy').has('value', "%s").count()`
+	GetCodeLabel = `g.V().hasLabel('_code_list')` +
+		`.has('listID', '%s').has('edition', '%s')` +
+		`.inE('usedBy').where(otherV().has('value', "%s")).coalesce(values('%s'),values('label'))`
+	GetCodeOrder = `g.V().hasLabel('_code_list')` +
+		`.has('listID', '%s').has('edition', '%s')` +
+		`.inE('usedBy').where(otherV().has('value', "%s")).as('usedBy')` +
+		`.outV().values('value').as('code').union(select('code', 'usedBy'))`
+	LabelPart                 = `'label'`
+	LocalisedLabelPart        = `coalesce(values('%s'),values('label'))`
 	GetUsedByEdgesFromNodeIDs = `g.V().hasLabel('_code_list').has('_code_list', 'listID', '%s')` +
 		`.inE('usedBy').where(otherV().has('value', within(%s))).as('usedBy')` +
 		`.outV().values('value').as('code').union(select('code', 'usedBy'))`
+	GetCodesBatch = `g.V().hasLabel('_code_list').or(%s).as('listID','edition')` +
+		`.inE('usedBy').where(otherV().has('value', within(%s))).as('usedBy')` +
+		`.outV().values('value').as('code')` +
+		`.select('listID', 'edition', 'code', 'usedBy').by('listID').by('edition').by().by()`
+	CodeListEditionPart    = `has('listID','%s').has('edition','%s')`
+	GetEditionsUsedByEdges = `g.V().hasLabel('_code_list').has('_code_list', 'listID', '%s')` +
+		`.has('edition', within('%s','%s')).as('edition')` +
+		`.inE('usedBy').as('usedBy')` +
+		`.outV().values('value').as('code')` +
+		`.select('edition', 'code', 'usedBy').by('edition').by().by()`
+
+	// GetCodeListsFiltered returns the code lists with the provided flag.
+	//
+	// Deprecated: use GetCodeLists followed by a CodeListFlagPart for each flag.
+	GetCodeListsFiltered = `g.V().hasLabel('_code_list').has('%s', true)`
 
 	/*
 		This query harvests data from both edges and nodes, so we collapse
@@ -57,7 +91,7 @@ const (
 				select('d').values('edition').as('de').
 				select('d').values('version').as('dv').
 				select('d').values('dataset_id').as('did'),
-			__.as('d').has('is_published',true)).
+			__.as('d').has('is_published',true).not(has('publish_state','superseded'))).
 		union(select('rl', 'de', 'dv', 'did')).unfold().select(values)
 	`
 
@@ -85,10 +119,18 @@ const (
 		`.property(single,'code',select('old').values('code'))` +
 		`.property(single,'label',select('old').values('label'))` +
 		`.property(single,'hasData', false)` +
-		`.property('code_list','%s').as('new')` +
+		`.property('code_list','%s').as('new')%s` +
 		`.addE('clone_of').to('old')` +
 		`.select('new')`
 
+	// GetPropertyKeys returns the distinct property keys of the generic hierarchy nodes, used to find
+	// the labels in languages other than the default
+	GetPropertyKeys = `g.V().hasLabel('_generic_hierarchy_node_%s').properties().key().dedup()`
+
+	// CloneLocalisedLabelPart copies a label in another language from the 'old' generic hierarchy node
+	// to the 'new' clone, only if the generic hierarchy node has it
+	CloneLocalisedLabelPart = `.sideEffect(select('old').has('%s').select('new').property(single,'%s',select('old').values('%s')))`
+
 	// CloneHierarchyNodesFromIDs traverses the provided node IDs and creates a clone for each one, thus:
 	// 1. get generic hierarchy nodes from IDs
 	// 2. create a new hierarchy node for the provided 'instance' and 'dimensionName'
@@ -96,13 +138,14 @@ const (
 	// 4. copy 'label' from the generic hierarchy node to the new node
 	// 5. set 'hasData' to true or false, according to the provided value
 	// 6. set 'code_list' property to the provided value
-	// 7. create a 'clone_of' edge between the new node and the generic node
+	// 7. copy the labels in other languages that the generic hierarchy node has, see CloneLocalisedLabelPart
+	// 8. create a 'clone_of' edge between the new node and the generic node
 	CloneHierarchyNodesFromIDs = `g.V(%s).as('old')` +
 		`.addV('_hierarchy_node_%s_%s')` +
 		`.property(single,'code',select('old').values('code'))` +
 		`.property(single,'label',select('old').values('label'))` +
 		`.property(single,'hasData', %t)` +
-		`.property('code_list','%s').as('new')` +
+		`.property('code_list','%s').as('new')%s` +
 		`.addE('clone_of').to('old')`
 
 	// CloneOrderFromIDs copies the order property from the code of a generic hierarchy node to its clone, thus:
@@ -153,15 +196,62 @@ const (
 	RemoveNodesNotMarkedToRemain = `g.V().hasLabel('_hierarchy_node_%s_%s').not(has('remain',true)).drop()`
 	RemoveRemainMarker           = `g.V().hasLabel('_hierarchy_node_%s_%s').has('remain').properties('remain').drop()`
 
+	// rollup counts, computed from the codes of the hierarchy nodes, the edges between them and the number of
+	// observations of each dimension option, then set with a part per node
+	GetHierarchyCodes         = `g.V().hasLabel('_hierarchy_node_%s_%s').values('code')`
+	CountObservationsByOption = `g.V().hasLabel('_%s_%s').group().by('value').by(__.in('isValueOf').count())`
+	SetRollupCountsPart       = `.V().hasLabel('_hierarchy_node_%s_%s').has('code','%s')` +
+		`.property(single,'numberOfOptions',%d).property(single,'numberOfObservations',%d)`
+
 	// hierarchy read
 	HierarchyExists           = `g.V().hasLabel('_hierarchy_node_%s_%s').limit(1)`
 	GetHierarchyRoot          = `g.V().hasLabel('_hierarchy_node_%s_%s').not(outE('hasParent'))`
+	GetHierarchyRoots         = `g.V().hasLabel('_hierarchy_node_%s_%s').not(outE('hasParent')).order().by('label')`
 	GetHierarchyElement       = `g.V().hasLabel('_hierarchy_node_%s_%s').has('code','%s')`
 	CountChildrenWithOrder    = `g.V().hasLabel('_hierarchy_node_%s_%s').has('code','%s').in('hasParent').has('order').count()`
-	GetChildrenAlphabetically = `g.V().hasLabel('_hierarchy_node_%s_%s').has('code','%s').in('hasParent').order().by('label')`
+	GetChildrenAlphabetically = `g.V().hasLabel('_hierarchy_node_%s_%s').has('code','%s').in('hasParent').order().by(%s)`
 	GetChildrenWithOrder      = `g.V().hasLabel('_hierarchy_node_%s_%s').has('code','%s').in('hasParent').order().by('order',asc)`
+	GetChildrenByCode         = `g.V().hasLabel('_hierarchy_node_%s_%s').has('code','%s').in('hasParent').order().by('code')`
+	ChildrenRangePart         = `.range(%d,%d)`
 	// Note this query is recursive
 	GetAncestry = `g.V().hasLabel('_hierarchy_node_%s_%s').has('code', '%s').repeat(out('hasParent')).emit()`
+	// SearchHierarchy matches the labels containing the search text with a case-insensitive regular expression,
+	// those starting with the text first
+	SearchHierarchy = `g.V().hasLabel('_hierarchy_node_%[1]s_%[2]s')` +
+		`.where(%[3]s.is(regex('(?i)%[4]s')))` +
+		`.order().by(choose(%[3]s.is(regex('(?i)^%[4]s')),constant(0),constant(1))).by(%[3]s)` +
+		`.limit(%[5]d)`
+	SearchLabelPart = `values('label')`
+
+	// hierarchy integrity and sync, for the nodes with the provided label
+	GetHierarchyIntegrityNodes = `g.V().hasLabel('%s')`
+	GetHierarchyEdges          = `g.V().hasLabel('%s').as('child').out('hasParent').hasLabel('%s').as('parent')` +
+		`.select('child', 'parent').by('code').unfold().select(values)`
+	GetCodeListCodes = `g.V().has('_code_list', 'listID', '%s').has('edition', '%s').in('usedBy').values('value').dedup()`
+
+	// GetHierarchySyncNodes projects the code, order and label properties of the hierarchy nodes with the provided label
+	GetHierarchySyncNodes = `g.V().hasLabel('%s').valueMap(%s)`
+
+	// SyncHierarchyNodes applies the changes made to the generic hierarchy to a batch of instance hierarchy nodes,
+	// each node being selected in a SyncHierarchyNodePart and changed with the parts below
+	SyncHierarchyNodes    = `g.inject(0)%s`
+	SyncHierarchyNodePart = `.sideEffect(V().hasLabel('_hierarchy_node_%s_%s').has('code','%s')%s)`
+	SyncLabelPart         = `.property(single,'%s','%s')`
+	SyncOrderPart         = `.property(single,'order',%d)`
+	SyncRemoveOrderPart   = `.sideEffect(properties('order').drop())`
+	SyncRemoveParentsPart = `.sideEffect(outE('hasParent').drop())`
+	SyncAddParentPart     = `.sideEffect(addE('hasParent').to(V().hasLabel('_hierarchy_node_%s_%s').has('code','%s')))`
+
+	// datasets
+	GetDatasetDimensions = `g.V().has('dataset_id','%s').has('edition','%s').has('version','%d')` +
+		`.in('inDataset').out('usedBy').hasLabel('_code_list').as('listID','edition')` +
+		`.select('listID','edition').by('listID').by('edition')` +
+		`.unfold().select(values)`
+	GetDatasetCodes = `g.V().has('dataset_id','%s').has('edition','%s').has('version','%d')` +
+		`.in('inDataset').as('code')` +
+		`.outE('usedBy').where(inV().has('_code_list','listID','%s')).as('usedBy')` +
+		`.select('usedBy', 'code').by('label').by('value')` +
+		`.unfold().select(values)`
 
 	// instance - import process
 	CreateInstance = `g.addV('_%s_Instance').property(id, '_%s_Instance').property(single,'header',"%s")`
@@ -171,8 +261,35 @@ const (
 	CreateInstanceToCodeRelationship = `g.V('_%s_Instance').as('i').V('%s').addE('inDataset').to('i')`
 	AddVersionDetailsToInstance      = `g.V().hasId('_%s_Instance').property(single,'dataset_id','%s').` +
 		`property(single,'edition','%s').property(single,'version','%d')`
-	SetInstanceIsPublished = `g.V().hasId('_%s_Instance').property(single,'is_published',true)`
-	CountObservations      = `g.V().hasLabel('_%s_observation').count()`
+	SetInstanceIsPublished   = `g.V().hasId('_%s_Instance').property(single,'is_published',true)`
+	SetInstancePublishState  = `g.V().hasId('_%s_Instance').property(single,'is_published',%t).property(single,'publish_state','%s')`
+	CountUnpublishedInstance = `g.V().hasId('_%s_Instance').has('publish_state','unpublished').count()`
+	CountObservations        = `g.V().hasLabel('_%s_observation').count()`
+	AddImportCheckpoint      = `g.V().hasId('_%s_Instance').property(set,'import_checkpoints','%s')`
+	GetImportCheckpoints     = `g.V().hasId('_%s_Instance').values('import_checkpoints')`
+
+	// SupersedePublishedInstances marks the other published instances of the dataset edition of an instance as superseded
+	SupersedePublishedInstances = `g.V().hasId('_%s_Instance').has('dataset_id').has('edition').as('i')` +
+		`.V().hasLabel(endingWith('_Instance')).has('header').has('dataset_id').has('edition')` +
+		`.has('is_published',true).not(has('publish_state','superseded')).where(neq('i'))` +
+		`.where(eq('i')).by('dataset_id').where(eq('i')).by('edition')` +
+		`.property(single,'publish_state','superseded')`
+
+	// instance - metadata
+	GetInstance                    = `g.V().hasId('_%s_Instance')`
+	ListInstancesPart              = `g.V().hasLabel(endingWith('_Instance')).has('header')`
+	InstanceDatasetIDFilterPart    = `.has('dataset_id','%s')`
+	InstanceEditionFilterPart      = `.has('edition','%s')`
+	InstancePublishedFilterPart    = `.has('is_published',true)`
+	InstanceNotPublishedFilterPart = `.not(has('is_published',true))`
+
+	// instance - validation
+	GetInstanceDimensions                  = `g.V().hasId('_%s_Instance').values('dimensions')`
+	CountObservationsMissingDimension      = `g.V().hasLabel('_%s_observation').not(out('isValueOf').hasLabel('_%s_%s')).count()`
+	GetDimensionOptionsWithoutObservations = `g.V().hasLabel('_%s_%s').not(in('isValueOf')).values('value')`
+	GetOrphanDimensionOptions              = `g.V().hasLabel('_%s_%s').not(out('HAS_DIMENSION').hasId('_%s_Instance')).values('value')`
+	GetHierarchyCodesWithHasData           = `g.V().hasLabel('_hierarchy_node_%s_%s').has('hasData',true).values('code')`
+	GetHierarchyCodesWithoutHasData        = `g.V().hasLabel('_hierarchy_node_%s_%s').not(has('hasData',true)).values('code')`
 
 	//instance - parts
 	AddInstanceDimensionsPart         = `g.V().hasId('_%s_Instance')`
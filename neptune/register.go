@@ -0,0 +1,53 @@
+package neptune
+
+import (
+	"github.com/ONSdigital/dp-graph/v2/graph/driver"
+	"github.com/pkg/errors"
+)
+
+// Settings holds the neptune-specific configuration, read from the environment variables in the tags of its fields.
+// Zero values are left to the defaults of New.
+type Settings struct {
+	BatchSizeReader int  `envconfig:"NEPTUNE_BATCH_SIZE_READER"`
+	BatchSizeWriter int  `envconfig:"NEPTUNE_BATCH_SIZE_WRITER"`
+	MaxWorkers      int  `envconfig:"NEPTUNE_MAX_WORKERS"`
+	TLSSkipVerify   bool `envconfig:"NEPTUNE_TLS_SKIP_VERIFY"`
+}
+
+// options returns the options of New for the settings
+func (s Settings) options() []Option {
+	return []Option{
+		WithBatchSizeReader(s.BatchSizeReader),
+		WithBatchSizeWriter(s.BatchSizeWriter),
+		WithMaxWorkers(s.MaxWorkers),
+		WithTLSSkipVerify(s.TLSSkipVerify),
+	}
+}
+
+func init() {
+	driver.Register("neptune", func(cfg driver.Config, errs chan error) (driver.Driver, error) {
+		var settings Settings
+		if cfg.LoadSettings != nil {
+			if err := cfg.LoadSettings(&settings); err != nil {
+				return nil, errors.Wrap(err, "invalid neptune settings")
+			}
+		}
+
+		n, err := New(
+			cfg.Address,
+			errs,
+			append([]Option{
+				WithReaderAddress(cfg.ReaderAddress),
+				WithPoolSize(cfg.PoolSize),
+				WithPoolMaxLifetime(cfg.PoolMaxLifetime),
+				WithPoolIdleTimeout(cfg.PoolIdleTimeout),
+				WithTimeout(cfg.QueryTimeout),
+				WithMaxRetries(cfg.MaxRetries),
+				WithRetryTime(cfg.RetryTime),
+			}, settings.options()...)...)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	})
+}